@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/notify"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/watch"
+)
+
+// newNotifier builds the alert notifier, or returns nil when no sinks are
+// configured
+func newNotifier(cfg *config.Config, registry *transport.Registry) (*notify.Notifier, error) {
+	if len(cfg.Notify.Sinks) == 0 {
+		return nil, nil
+	}
+	n, err := notify.FromConfig(cfg.Notify, registry.For(transport.ProviderNotify))
+	if err != nil {
+		return nil, fmt.Errorf("notify: %w", err)
+	}
+	return n, nil
+}
+
+// sendAlerts delivers events, printing a warning for each failed sink. n
+// may be nil.
+func sendAlerts(ctx context.Context, n *notify.Notifier, events ...notify.Event) {
+	if n == nil {
+		return
+	}
+	for _, e := range events {
+		if err := n.Notify(ctx, e); err != nil {
+			fmt.Printf("  WARNING: Could not deliver alert %q: %v\n", e.Title(), err)
+		}
+	}
+}
+
+// watchAlerts are the events of a watched token's screening: its state
+// change, and a honeypot alert when it newly failed as one
+func watchAlerts(r pipeline.TokenResult, change *watch.Transition) []notify.Event {
+	if change == nil {
+		return nil
+	}
+	events := []notify.Event{transitionEvent(*change)}
+	if isHoneypot(r) {
+		events = append(events, honeypotEvent(r))
+	}
+	return events
+}
+
+// screenAlerts are the events of a screening compared with the token's
+// previous stored result, if any
+func screenAlerts(prev *pipeline.TokenResult, r pipeline.TokenResult, listing pipeline.ListingThresholds) []notify.Event {
+	var events []notify.Event
+	if prev != nil {
+		from, to := watch.State(*prev, listing), watch.State(r, listing)
+		if from != "" && to != "" && from != to {
+			events = append(events, transitionEvent(watch.Transition{
+				Chain:     r.Chain,
+				Address:   r.Address,
+				Symbol:    r.Symbol,
+				From:      from,
+				To:        to,
+				FromScore: prev.Score,
+				ToScore:   r.Score,
+				Reasons:   r.Listing(listing).Reasons,
+				At:        r.CheckedAt,
+			}))
+		}
+	}
+	if isHoneypot(r) && (prev == nil || !isHoneypot(*prev)) {
+		events = append(events, honeypotEvent(r))
+	}
+	return events
+}
+
+func transitionEvent(t watch.Transition) notify.Event {
+	return notify.Event{
+		Kind:    notify.KindTransition,
+		Chain:   t.Chain,
+		Address: t.Address,
+		Symbol:  t.Symbol,
+		From:    t.From,
+		To:      t.To,
+		Score:   t.ToScore,
+		Reasons: t.Reasons,
+		At:      t.At,
+	}
+}
+
+func honeypotEvent(r pipeline.TokenResult) notify.Event {
+	return notify.Event{
+		Kind:    notify.KindHoneypot,
+		Chain:   r.Chain,
+		Address: r.Address,
+		Symbol:  r.Symbol,
+		Reasons: r.FailureReasons,
+		At:      r.CheckedAt,
+	}
+}
+
+// isHoneypot reports whether a result was rejected by a honeypot rule
+func isHoneypot(r pipeline.TokenResult) bool {
+	return r.Status == pipeline.StatusFailed && r.Fraud != nil && r.Fraud.IsHoneypot
+}
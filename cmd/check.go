@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// runCheck screens a single address and prints the verdict
+func runCheck(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("check", cfg)
+	symbol := fs.String("symbol", "", "token symbol shown in the report")
+	asJSON := fs.Bool("json", false, "print the full result as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := finishConfig(cfg, !*asJSON); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dex-token-screener check [flags] <address>")
+	}
+
+	address := fs.Arg(0)
+	if !models.IsAddress(address) {
+		return fmt.Errorf("invalid address %q", address)
+	}
+	if err := requireAPIKey(cfg); err != nil {
+		return err
+	}
+
+	registry := transport.NewRegistry(cfg.ProviderLimits)
+	clients, responseCache, err := newClients(cfg, registry)
+	if err != nil {
+		return err
+	}
+	defer responseCache.Close()
+	screener := pipeline.New(cfg, clients)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	result := screener.ScreenToken(ctx, models.BasicTokenInfo{Address: address, Symbol: *symbol})
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Print(formatTokenResult(0, 1, result, cfg))
+	return nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/discovery"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/market"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/storage"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// runDiscover follows the chain's V2/V3 factories for new quote-asset pools
+// and appends each new token to a token list once its cooldown has passed,
+// optionally screening it right away
+func runDiscover(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("discover", cfg)
+	output := fs.String("output", "discovered.txt", "token list the discovered tokens are appended to (\"address symbol\" lines); tokens already in it are skipped; empty = none")
+	screen := fs.Bool("screen", false, "screen each token when it is released and print the result")
+	fromBlock := fs.Uint64("from-block", 0, "first block to scan; 0 = discovery.start_blocks behind the head")
+	once := fs.Bool("once", false, "scan once up to the confirmed head, print what was found and exit")
+	fs.DurationVar(&cfg.Discovery.Cooldown, "cooldown", cfg.Discovery.Cooldown, "wait after a pool is created before its token is released (env DISCOVERY_COOLDOWN)")
+	fs.DurationVar(&cfg.Discovery.PollInterval, "poll-interval", cfg.Discovery.PollInterval, "how often new blocks are scanned (env DISCOVERY_POLL_INTERVAL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := finishConfig(cfg, true); err != nil {
+		return err
+	}
+	if *screen {
+		if err := requireAPIKey(cfg); err != nil {
+			return err
+		}
+	}
+
+	registry := transport.NewRegistry(cfg.ProviderLimits)
+	scanner, err := newDiscoveryScanner(cfg, registry)
+	if err != nil {
+		return err
+	}
+	feed := discovery.NewFeed(scanner, cfg.Discovery).StartAt(*fromBlock)
+
+	// Tokens already in the output list are not released again
+	if *output != "" {
+		if known, err := readTokens(*output); err == nil {
+			for _, t := range known {
+				feed.Queue().MarkSeen(t.Address)
+			}
+			fmt.Printf("%d tokens already in %s\n", len(known), *output)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var screenBatch func([]discovery.Candidate)
+	if *screen {
+		var closeStore func()
+		screenBatch, closeStore, err = newDiscoveryScreener(ctx, cfg, registry)
+		if err != nil {
+			return err
+		}
+		defer closeStore()
+	}
+
+	onQueued := func(p discovery.Pair) {
+		fmt.Printf("%s block %d: new %s pool %s of %s against %s, release at %s\n",
+			p.SeenAt.Format("2006-01-02 15:04:05"), p.Block, p.Version, p.Pool, p.Token, p.Quote,
+			p.SeenAt.Add(cfg.Discovery.Cooldown).Format("15:04:05"))
+	}
+	onReady := func(ready []discovery.Candidate) {
+		for _, c := range ready {
+			fmt.Printf("%s released %s %s (%s pool %s)\n",
+				time.Now().Format("2006-01-02 15:04:05"), c.Token, c.Symbol, c.Version, c.Pool)
+		}
+		if *output != "" {
+			if err := appendTokens(*output, ready); err != nil {
+				fmt.Printf("WARNING: %v\n", err)
+			}
+		}
+		if screenBatch != nil {
+			screenBatch(ready)
+		}
+	}
+	onError := func(err error) {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+
+	fmt.Printf("Discovering %s pools against %v every %s, releasing tokens after %s\n\n",
+		cfg.Chain, cfg.QuoteAssets, cfg.Discovery.PollInterval, cfg.Discovery.Cooldown)
+
+	if *once {
+		queued, ready, err := feed.Poll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, p := range queued {
+			onQueued(p)
+		}
+		if len(ready) > 0 {
+			onReady(ready)
+		}
+		fmt.Printf("\n%d new tokens, %d released, %d waiting out the cooldown\n", len(queued), len(ready), feed.Queue().Len())
+		return nil
+	}
+
+	err = feed.Run(ctx, onQueued, onReady, onError)
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("\nStopped with %d tokens waiting out the cooldown\n", feed.Queue().Len())
+		return nil
+	}
+	return err
+}
+
+// newDiscoveryScanner builds the factory log scanner for cfg.Chain over
+// cfg.RPCURL, falling back to the chain's public RPC
+func newDiscoveryScanner(cfg *config.Config, registry *transport.Registry) (*discovery.Scanner, error) {
+	ch, err := chain.Lookup(cfg.Chain)
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := market.ParseQuoteAssets(ch, cfg.QuoteAssets)
+	if err != nil {
+		return nil, err
+	}
+	rpcURL := cfg.RPCURL
+	if rpcURL == "" {
+		rpcURL = ch.RPCURL
+	}
+	if rpcURL == "" || rpcURL == config.RPCDisabled {
+		return nil, fmt.Errorf("discovery reads factory logs and needs an RPC endpoint: set RPC_URL or -rpc-url")
+	}
+	client := rpc.NewClient(rpcURL, registry.For(transport.ProviderRPC))
+	return discovery.NewScanner(ch, client, quotes).WithBlockRange(uint64(max(cfg.LogBlockRange, 0))), nil
+}
+
+// newDiscoveryScreener returns a function that screens released tokens,
+// prints the results and, with a database, stores them as one screening
+// run per batch. Alerts go out as for the screen command. The returned
+// close function releases the database and the response cache.
+func newDiscoveryScreener(ctx context.Context, cfg *config.Config, registry *transport.Registry) (func([]discovery.Candidate), func(), error) {
+	clients, responseCache, err := newClients(cfg, registry)
+	if err != nil {
+		return nil, nil, err
+	}
+	screener := pipeline.New(cfg, clients)
+	notifier, err := newNotifier(cfg, registry)
+	if err != nil {
+		responseCache.Close()
+		return nil, nil, err
+	}
+
+	var store *storage.Store
+	closeStore := func() { responseCache.Close() }
+	if cfg.DatabaseURL != "" {
+		store, err = storage.Open(ctx, cfg.DatabaseURL, listingThresholds(cfg))
+		if err != nil {
+			responseCache.Close()
+			return nil, nil, fmt.Errorf("could not open database: %w", err)
+		}
+		closeStore = func() {
+			store.Close()
+			responseCache.Close()
+		}
+		screener.WithSnapshots(store)
+	}
+
+	return func(ready []discovery.Candidate) {
+		tokens := make([]models.BasicTokenInfo, len(ready))
+		for i, c := range ready {
+			tokens[i] = models.BasicTokenInfo{Address: c.Token, Symbol: c.Symbol, Decimals: 18}
+		}
+
+		var runID int64
+		if store != nil {
+			id, err := store.StartRun(ctx, "discover", len(tokens))
+			if err != nil {
+				fmt.Printf("WARNING: Could not record screening run: %v\n", err)
+				return
+			}
+			runID = id
+		}
+		screener.Run(ctx, tokens, func(i int, r pipeline.TokenResult) {
+			fmt.Print(formatTokenResult(i, len(tokens), r, cfg))
+			if store != nil {
+				if err := store.SaveResult(ctx, runID, r); err != nil {
+					fmt.Printf("  WARNING: Could not save result: %v\n", err)
+				}
+			}
+			// A released token is new, so there is no earlier result to compare with
+			sendAlerts(ctx, notifier, screenAlerts(nil, r, listingThresholds(cfg))...)
+		})
+		if store != nil {
+			if err := store.FinishRun(ctx, runID); err != nil {
+				fmt.Printf("WARNING: Could not finish screening run: %v\n", err)
+			}
+		}
+	}, closeStore, nil
+}
+
+// appendTokens adds released tokens to a plain-text token list, which the
+// screen and watch commands read (watch reloads it when it changes)
+func appendTokens(fileName string, ready []discovery.Candidate) error {
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", fileName, err)
+	}
+	defer f.Close()
+	for _, c := range ready {
+		line := c.Token
+		if c.Symbol != "" {
+			line += " " + c.Symbol
+		}
+		if _, err := fmt.Fprintln(f, line); err != nil {
+			return fmt.Errorf("could not write %s: %w", fileName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/evaluation"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// runEvaluate screens a labelled dataset and reports detection quality
+func runEvaluate(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("evaluate", cfg)
+	dataset := fs.String("dataset", "tokenData/labelled/benchmark.json",
+		"labelled dataset: JSON [{address, label}] or CSV address,label[,symbol]")
+	fixtures := fs.String("fixtures", "",
+		`replay recorded responses instead of calling live APIs: a fixture directory or "embedded"`)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fixtures != "" {
+		// Replayed responses must not be mixed with cached live ones
+		cfg.Cache.Enabled = false
+	}
+	if err := finishConfig(cfg, !*asJSON); err != nil {
+		return err
+	}
+
+	samples, err := evaluation.LoadDataset(*dataset)
+	if err != nil {
+		return err
+	}
+
+	registry := transport.NewRegistry(cfg.ProviderLimits)
+	if *fixtures != "" {
+		var srv *mockapi.Server
+		if *fixtures == "embedded" {
+			srv = mockapi.New()
+		} else {
+			srv = mockapi.NewFromDir(*fixtures)
+		}
+		defer srv.Close()
+
+		cfg.DexScreenerBaseURL = srv.DexScreenerURL()
+		cfg.HoneypotBaseURL = srv.HoneypotURL()
+		cfg.GoPlusBaseURL = srv.GoPlusURL()
+		cfg.EtherscanBaseURL = srv.EtherscanURL()
+		cfg.RPCURL = srv.RPCURL()
+		if cfg.BscScanAPIKey == "" {
+			cfg.BscScanAPIKey = "fixtures"
+		}
+
+		// Replayed responses need neither rate limiting nor retries
+		offline := transport.Limits{Timeout: transport.DefaultLimits("").Timeout}
+		registry = transport.NewRegistry(map[string]transport.Limits{
+			transport.ProviderDexScreener: offline,
+			transport.ProviderHoneypot:    offline,
+			transport.ProviderGoPlus:      offline,
+			transport.ProviderEtherscan:   offline,
+			transport.ProviderRPC:         offline,
+		})
+	} else if err := requireAPIKey(cfg); err != nil {
+		return err
+	}
+
+	clients, responseCache, err := newClients(cfg, registry)
+	if err != nil {
+		return err
+	}
+	defer responseCache.Close()
+	screener := pipeline.New(cfg, clients)
+	results := screener.Run(context.Background(), evaluation.TokenInfos(samples), nil)
+	report := evaluation.Evaluate(samples, results)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("Evaluation of %s\n\n", *dataset)
+	fmt.Print(report.String())
+	return nil
+}
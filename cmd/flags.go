@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/fraud"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/liquidity"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// loadConfig loads the configuration for a command. -config and -profile
+// are picked out of args first, since every other flag's default comes from
+// the file and profile they select.
+func loadConfig(args []string) (*config.Config, error) {
+	var opts config.Options
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || args[i] == "--" {
+			break
+		}
+		if name != "config" && name != "profile" {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		if name == "config" {
+			opts.File = value
+		} else {
+			opts.Profile = value
+		}
+	}
+	return config.Load(opts)
+}
+
+// finishConfig validates cfg once flags are parsed and, when print is set,
+// writes the effective configuration to stdout
+func finishConfig(cfg *config.Config, print bool) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if print {
+		if err := cfg.PrintEffective(os.Stdout); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// newFlagSet creates a command flag set with every config.Config threshold
+// bound as an override on top of the file/profile/env values in cfg
+func newFlagSet(name string, cfg *config.Config) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	// Already applied by loadConfig; declared so they parse and show in -h
+	fs.StringVar(&cfg.ConfigFile, "config", cfg.ConfigFile, "YAML config file (env CONFIG_FILE)")
+	fs.StringVar(&cfg.Profile, "profile", cfg.Profile,
+		fmt.Sprintf("named settings profile: %s or one defined in the config file (env PROFILE)", strings.Join(config.ProfileNames(), ", ")))
+
+	fs.StringVar(&cfg.BscScanAPIKey, "bscscan-api-key", cfg.BscScanAPIKey, "Etherscan v2 API key (env BSCSCAN_API_KEY)")
+	fs.StringVar(&cfg.DatabaseURL, "database-url", cfg.DatabaseURL, "Postgres URL for persisting results (env DATABASE_URL)")
+	fs.IntVar(&cfg.Workers, "workers", cfg.Workers, "number of tokens screened concurrently (env SCREENER_WORKERS)")
+	fs.Func("chain", fmt.Sprintf("chain to screen on: %s or a chain ID (env CHAIN, default %s)", strings.Join(chain.Names(), ", "), cfg.Chain),
+		func(v string) error {
+			if _, err := chain.Lookup(v); err != nil {
+				return err
+			}
+			cfg.Chain = v
+			return nil
+		})
+	fs.StringVar(&cfg.RPCURL, "rpc-url", cfg.RPCURL,
+		fmt.Sprintf("JSON-RPC endpoint (node or anvil/hardhat fork) for slippage simulation; empty = chain's public RPC, %q = skip (env RPC_URL)", config.RPCDisabled))
+	fs.Func("quote-assets", "comma-separated quote assets counted towards liquidity, e.g. USDT,WBNB or SYM=0xaddress (env QUOTE_ASSETS, default wrapped native + stablecoins)",
+		func(v string) error {
+			cfg.QuoteAssets = strings.Split(v, ",")
+			return nil
+		})
+
+	fs.Func("holder-source", fmt.Sprintf("holder distribution source: %q (Honeypot.is) or %q (Transfer logs over -rpc-url) (env HOLDER_SOURCE, default %s)",
+		config.HolderSourceAPI, config.HolderSourceOnChain, cfg.HolderSource),
+		func(v string) error {
+			if v != config.HolderSourceAPI && v != config.HolderSourceOnChain {
+				return fmt.Errorf("must be %q or %q", config.HolderSourceAPI, config.HolderSourceOnChain)
+			}
+			cfg.HolderSource = v
+			return nil
+		})
+	fs.IntVar(&cfg.HolderTopN, "holder-top-n", cfg.HolderTopN, "largest holders reported by the on-chain holder index (env HOLDER_TOP_N)")
+	fs.IntVar(&cfg.LogBlockRange, "log-block-range", cfg.LogBlockRange, "blocks per eth_getLogs request; halved automatically when the node refuses (env LOG_BLOCK_RANGE)")
+
+	fs.BoolFunc("no-cache", "fetch every provider response and keep none (config cache.enabled: false)", func(string) error {
+		cfg.Cache.Enabled = false
+		return nil
+	})
+	fs.BoolVar(&cfg.Cache.Refresh, "refresh", cfg.Cache.Refresh, "ignore cached provider responses and replace them with fresh ones")
+	fs.StringVar(&cfg.Cache.Path, "cache-file", cfg.Cache.Path, "BoltDB file of the on-disk response cache; empty = memory only (env CACHE_FILE)")
+
+	fs.StringVar(&cfg.FraudPolicy, "fraud-policy", cfg.FraudPolicy, "YAML/JSON fraud rule policy; rules it omits keep their defaults (env FRAUD_POLICY)")
+	fs.Func("fraud-threshold", "fraud rule threshold as rule=value, e.g. excessive_tax=25, over any -fraud-policy; repeatable, see the rules command",
+		func(v string) error { return setFraudThreshold(cfg, v) })
+	fs.Func("drain-window", "liquidity drain window as period=warn/reject percent drop, e.g. 24h=30/60; replaces the window with that period or adds one, repeatable (config liquidity_drain.windows)",
+		func(v string) error { return setDrainWindow(&cfg.LiquidityDrain, v) })
+
+	fs.Float64Var(&cfg.MinLiquidityUSD, "min-liquidity", cfg.MinLiquidityUSD, "minimum aggregated liquidity in USD")
+	fs.Float64Var(&cfg.MinVolume24h, "min-volume", cfg.MinVolume24h, "minimum 24h volume in USD")
+	fs.Float64Var(&cfg.MaxTop10HolderConcentration, "max-top10-holders", cfg.MaxTop10HolderConcentration, "maximum top 10 holder concentration in percent")
+	fs.Float64Var(&cfg.MinPairAgeDays, "min-pair-age", cfg.MinPairAgeDays, "minimum age in days of the largest pool")
+
+	fs.Float64Var(&cfg.LiquidityWeight, "liquidity-weight", cfg.LiquidityWeight, "liquidity score weight")
+	fs.Float64Var(&cfg.VolumeWeight, "volume-weight", cfg.VolumeWeight, "volume score weight")
+	fs.Float64Var(&cfg.HolderWeight, "holder-weight", cfg.HolderWeight, "holder distribution score weight")
+	fs.Float64Var(&cfg.FragmentationWeight, "fragmentation-weight", cfg.FragmentationWeight, "liquidity fragmentation score weight")
+	fs.Float64Var(&cfg.SlippageWeight, "slippage-weight", cfg.SlippageWeight, "simulated $1K slippage score weight (env SLIPPAGE_WEIGHT)")
+	fs.Float64Var(&cfg.ContractWeight, "contract-weight", cfg.ContractWeight, "source analysis red flag score weight")
+	fs.Float64Var(&cfg.FraudRiskWeight, "fraud-risk-weight", cfg.FraudRiskWeight, "fraud risk score weight (100 minus the risk score)")
+
+	fs.Float64Var(&cfg.FeaturedThreshold, "featured-threshold", cfg.FeaturedThreshold, "minimum composite score for featured status")
+	fs.Float64Var(&cfg.VisibleThreshold, "visible-threshold", cfg.VisibleThreshold, "minimum composite score for visible status")
+
+	return fs
+}
+
+// setFraudThreshold records a -fraud-threshold rule=value override
+func setFraudThreshold(cfg *config.Config, v string) error {
+	name, value, ok := strings.Cut(v, "=")
+	threshold, err := strconv.ParseFloat(value, 64)
+	if !ok || err != nil {
+		return fmt.Errorf("want rule=threshold, e.g. excessive_tax=25")
+	}
+	if fraud.RuleDescriptions()[name] == "" {
+		return fmt.Errorf("unknown rule %q", name)
+	}
+	if cfg.FraudThresholds == nil {
+		cfg.FraudThresholds = map[string]float64{}
+	}
+	cfg.FraudThresholds[name] = threshold
+	return nil
+}
+
+// setDrainWindow applies a -drain-window period=warn/reject override
+func setDrainWindow(p *liquidity.Policy, v string) error {
+	period, levels, ok := strings.Cut(v, "=")
+	warn, reject, ok2 := strings.Cut(levels, "/")
+	d, err := time.ParseDuration(period)
+	warnPct, warnErr := strconv.ParseFloat(warn, 64)
+	rejectPct, rejectErr := strconv.ParseFloat(reject, 64)
+	if !ok || !ok2 || errors.Join(err, warnErr, rejectErr) != nil {
+		return fmt.Errorf("want period=warn/reject, e.g. 24h=30/60")
+	}
+	window := liquidity.Window{Period: d, WarnPct: warnPct, RejectPct: rejectPct}
+	for i := range p.Windows {
+		if p.Windows[i].Period == d {
+			p.Windows[i] = window
+			return nil
+		}
+	}
+	p.Windows = append(p.Windows, window)
+	return nil
+}
+
+// listingThresholds returns the featured and visible tier thresholds
+func listingThresholds(cfg *config.Config) pipeline.ListingThresholds {
+	return pipeline.ListingThresholds{Featured: cfg.FeaturedThreshold, Visible: cfg.VisibleThreshold}
+}
+
+// requireAPIKey fails early when the Etherscan key is missing
+func requireAPIKey(cfg *config.Config) error {
+	if cfg.BscScanAPIKey == "" {
+		return fmt.Errorf("BSCSCAN_API_KEY not set (or pass -bscscan-api-key)")
+	}
+	return nil
+}
+
+// newClients creates the API clients with the provider response cache
+// attached. The cache is nil when disabled with -no-cache.
+func newClients(cfg *config.Config, registry *transport.Registry) (pipeline.Clients, *cache.Cache, error) {
+	clients, err := pipeline.NewClients(cfg, registry)
+	if err != nil {
+		return pipeline.Clients{}, nil, err
+	}
+	rc, err := cache.Open(cfg.Cache)
+	if err != nil {
+		return pipeline.Clients{}, nil, err
+	}
+	if rc != nil {
+		rc.WithOnError(func(err error) { fmt.Printf("WARNING: %v\n", err) })
+	}
+	return clients.WithCache(rc), rc, nil
+}
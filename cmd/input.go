@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+)
+
+// readTokens loads a token list from a file, or from stdin when fileName is "-".
+// JSON arrays in the tokenData format and plain-text lists (one address per
+// line, optionally followed by a symbol and name) are both accepted.
+func readTokens(fileName string) ([]models.BasicTokenInfo, error) {
+	var data []byte
+	var err error
+	if fileName == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(fileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", fileName, err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var tokens []models.BasicTokenInfo
+		if err := json.Unmarshal(trimmed, &tokens); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", fileName, err)
+		}
+		return tokens, nil
+	}
+
+	return parseAddressList(trimmed, fileName)
+}
+
+// parseAddressList parses "address [symbol [name...]]" lines, separated by
+// whitespace or commas. Blank lines and lines starting with # are skipped.
+func parseAddressList(data []byte, source string) ([]models.BasicTokenInfo, error) {
+	var tokens []models.BasicTokenInfo
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if !models.IsAddress(fields[0]) {
+			return nil, fmt.Errorf("%s:%d: invalid address %q", source, lineNo, fields[0])
+		}
+
+		token := models.BasicTokenInfo{Address: fields[0], Decimals: 18}
+		if len(fields) > 1 {
+			token.Symbol = fields[1]
+		}
+		if len(fields) > 2 {
+			token.Name = strings.Join(fields[2:], " ")
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, scanner.Err()
+}
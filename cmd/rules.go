@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/fraud"
+
+	"gopkg.in/yaml.v3"
+)
+
+// runRules prints the effective fraud policy
+func runRules(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("rules", cfg)
+	asYAML := fs.Bool("yaml", false, "print the policy as a YAML file to start editing from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := finishConfig(cfg, false); err != nil {
+		return err
+	}
+
+	policy, err := cfg.FraudRules()
+	if err != nil {
+		return err
+	}
+
+	if *asYAML {
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		return enc.Encode(policy)
+	}
+
+	var sources []string
+	if cfg.ConfigFile != "" {
+		sources = append(sources, cfg.ConfigFile)
+	}
+	if cfg.Profile != "" {
+		sources = append(sources, "profile "+cfg.Profile)
+	}
+	if cfg.FraudPolicy != "" {
+		sources = append(sources, cfg.FraudPolicy)
+	}
+	if len(cfg.FraudThresholds) > 0 {
+		sources = append(sources, "-fraud-threshold")
+	}
+	source := "built-in defaults"
+	if len(sources) > 0 {
+		source = "defaults + " + strings.Join(sources, " + ")
+	}
+	fmt.Printf("Fraud policy: %s (min holder sample %d)\n\n", source, policy.MinHolderSample)
+
+	descriptions := fraud.RuleDescriptions()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tSEVERITY\tWEIGHT\tTHRESHOLD\tENABLED\tCHECKS")
+	for _, r := range policy.Rules {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%g\t%t\t%s\n", r.Name, r.Severity, r.Weight, r.Threshold, r.Enabled, descriptions[r.Name])
+	}
+	return w.Flush()
+}
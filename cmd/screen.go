@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/report"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/storage"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// runScreen screens a token list and writes the reports
+func runScreen(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("screen", cfg)
+	input := fs.String("input", "tokenData/parsed_10000_BSC_tokens.json",
+		"token list: JSON array or plain-text address list, \"-\" for stdin")
+	outputDir := fs.String("output", "./results", "directory for result files")
+	format := fs.String("format", report.FormatText,
+		"comma-separated extra outputs written next to the text report: jsonl, csv, summary")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := finishConfig(cfg, true); err != nil {
+		return err
+	}
+
+	formats, err := report.ParseFormats(*format)
+	if err != nil {
+		return err
+	}
+	if err := requireAPIKey(cfg); err != nil {
+		return err
+	}
+
+	// Initialize clients (one rate-limited transport per provider) and the worker pool
+	registry := transport.NewRegistry(cfg.ProviderLimits)
+	clients, responseCache, err := newClients(cfg, registry)
+	if err != nil {
+		return err
+	}
+	defer responseCache.Close()
+	screener := pipeline.New(cfg, clients)
+	notifier, err := newNotifier(cfg, registry)
+	if err != nil {
+		return err
+	}
+
+	tokenInfos, err := readTokens(*input)
+	if err != nil {
+		return err
+	}
+	if len(tokenInfos) == 0 {
+		return fmt.Errorf("no tokens found in %s", *input)
+	}
+
+	// Create output files
+	if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	outputBase := filepath.Join(*outputDir, fmt.Sprintf("screening_results_%s", time.Now().Format("2006-01-02_15-04-05")))
+	outputFile, err := os.Create(outputBase + ".txt")
+	if err != nil {
+		return fmt.Errorf("could not create output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	var writers []report.ResultWriter
+	if formats[report.FormatJSONL] {
+		jsonlFile, err := os.Create(outputBase + ".jsonl")
+		if err != nil {
+			return fmt.Errorf("could not create output file: %w", err)
+		}
+		defer jsonlFile.Close()
+		writers = append(writers, report.NewJSONLWriter(jsonlFile, listingThresholds(cfg)))
+	}
+	if formats[report.FormatCSV] {
+		csvFile, err := os.Create(outputBase + ".csv")
+		if err != nil {
+			return fmt.Errorf("could not create output file: %w", err)
+		}
+		defer csvFile.Close()
+		writers = append(writers, report.NewCSVWriter(csvFile, listingThresholds(cfg)))
+	}
+
+	// Write header
+	header := fmt.Sprintf("%s Token Screening Pipeline - %s\nTotal: %d tokens\n\n",
+		strings.ToUpper(clients.Chain.Name), time.Now().Format("2006-01-02 15:04:05"), len(tokenInfos))
+	fmt.Print(header)
+	outputFile.WriteString(header)
+
+	// Optional Postgres persistence
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var store *storage.Store
+	var runID int64
+	if cfg.DatabaseURL != "" {
+		store, err = storage.Open(ctx, cfg.DatabaseURL, listingThresholds(cfg))
+		if err != nil {
+			return fmt.Errorf("could not open database: %w", err)
+		}
+		defer store.Close()
+		screener.WithSnapshots(store)
+
+		runID, err = store.StartRun(ctx, *input, len(tokenInfos))
+		if err != nil {
+			return fmt.Errorf("could not record screening run: %w", err)
+		}
+	}
+
+	// Results are streamed in input order while workers screen ahead
+	results := screener.Run(ctx, tokenInfos, func(i int, result pipeline.TokenResult) {
+		text := formatTokenResult(i, len(tokenInfos), result, cfg)
+		fmt.Print(text)
+		outputFile.WriteString(text)
+
+		for _, w := range writers {
+			if err := w.WriteResult(result); err != nil {
+				fmt.Printf("  WARNING: Could not write structured result: %v\n", err)
+			}
+		}
+
+		// Alerts compare with the token's previous stored result, if any
+		var prev *pipeline.TokenResult
+		if store != nil {
+			prev, _ = store.LatestResult(ctx, result.Chain, result.Address)
+			if err := store.SaveResult(ctx, runID, result); err != nil {
+				fmt.Printf("  WARNING: Could not save result: %v\n", err)
+			}
+		}
+		sendAlerts(ctx, notifier, screenAlerts(prev, result, listingThresholds(cfg))...)
+	})
+
+	if store != nil {
+		if err := store.FinishRun(ctx, runID); err != nil {
+			fmt.Printf("WARNING: Could not finish screening run: %v\n", err)
+		}
+	}
+
+	for _, w := range writers {
+		if err := w.Flush(); err != nil {
+			fmt.Printf("WARNING: Could not flush structured output: %v\n", err)
+		}
+	}
+
+	stats := pipeline.Summarize(results, listingThresholds(cfg))
+
+	// Generate summary
+	summary := generateSummary(stats)
+	fmt.Print(summary)
+	outputFile.WriteString(summary)
+
+	// Write detailed breakdown
+	breakdown := generateDetailedBreakdown(results, stats)
+	outputFile.WriteString(breakdown)
+
+	// API usage per provider
+	apiUsage := generateAPIUsage(registry.Stats(), responseCache.Stats())
+	fmt.Print(apiUsage)
+	outputFile.WriteString(apiUsage)
+
+	if formats[report.FormatSummary] {
+		summaryFile, err := os.Create(outputBase + "_summary.json")
+		if err != nil {
+			return fmt.Errorf("could not create output file: %w", err)
+		}
+		defer summaryFile.Close()
+
+		err = report.WriteSummaryJSON(summaryFile, report.Summary{
+			GeneratedAt: time.Now(),
+			Input:       *input,
+			Statistics:  stats,
+			APIUsage:    registry.Stats(),
+		})
+		if err != nil {
+			fmt.Printf("WARNING: Could not write summary: %v\n", err)
+		}
+	}
+
+	fmt.Printf("\nResults saved to: %s.*\n", outputBase)
+	return nil
+}
+
+// Key Improvement : Optimized Pipeline Order (run per token by the pipeline worker pool)
+// 1. DexScreener → Aggregate pairs in every quote asset, reject drained liquidity
+// 2. Check liq/vol thresholds → Skip API calls if below
+// 3. Holder concentration → Honeypot.is or the on-chain index
+// 4. BscScan → Contract verified (implementation too, for proxies)
+// 5. Fraud APIs → Honeypot + GoPlus → If all pass
+// 6. Slippage → Simulated trades over RPC
+// 7. Scoring → Final score
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/api"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/storage"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// runServe starts the HTTP API, backed by Postgres when DATABASE_URL is set
+// and by an in-memory store otherwise
+func runServe(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("serve", cfg)
+	fs.StringVar(&cfg.APIAddr, "addr", cfg.APIAddr, "listen address (env API_ADDR)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := finishConfig(cfg, true); err != nil {
+		return err
+	}
+	if err := requireAPIKey(cfg); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var store storage.ResultStore = storage.NewMemoryStore(listingThresholds(cfg))
+	var pgStore *storage.Store
+	if cfg.DatabaseURL != "" {
+		var err error
+		pgStore, err = storage.Open(ctx, cfg.DatabaseURL, listingThresholds(cfg))
+		if err != nil {
+			return fmt.Errorf("could not open database: %w", err)
+		}
+		defer pgStore.Close()
+		store = pgStore
+	} else {
+		fmt.Println("WARNING: DATABASE_URL not set, results are kept in memory only")
+	}
+
+	registry := transport.NewRegistry(cfg.ProviderLimits)
+	clients, responseCache, err := newClients(cfg, registry)
+	if err != nil {
+		return err
+	}
+	defer responseCache.Close()
+	screener := pipeline.New(cfg, clients)
+	if pgStore != nil {
+		screener.WithSnapshots(pgStore)
+	}
+	server := api.NewServer(store, screener, listingThresholds(cfg)).WithScreenLimit(cfg.Workers)
+
+	fmt.Printf("Listening on %s\n", cfg.APIAddr)
+	if err := server.ListenAndServe(ctx, cfg.APIAddr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("API server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/liquidity"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/market"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/slippage"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/watch"
+)
+
+// formatTokenResult renders the per-token section of the text report
+func formatTokenResult(i, total int, r pipeline.TokenResult, cfg *config.Config) string {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("[%d/%d] %s (%s)\n", i+1, total, r.Symbol, r.Address))
+
+	for _, warning := range r.Warnings {
+		out.WriteString(fmt.Sprintf("  WARNING: %s\n", warning))
+	}
+
+	switch {
+	case r.Status == pipeline.StatusError:
+		switch r.Stage {
+		case pipeline.StageHolders:
+			out.WriteString(fmt.Sprintf("  ERROR: Holder concentration check failed: %s\n\n", r.ErrorReason))
+		case pipeline.StageBscScan:
+			out.WriteString(fmt.Sprintf("  ERROR: BscScan verification check failed: %s\n\n", r.ErrorReason))
+		default:
+			out.WriteString(fmt.Sprintf("  ERROR: %s\n\n", r.ErrorReason))
+		}
+
+	case r.Stage == pipeline.StageThresholds:
+		out.WriteString(fmt.Sprintf("  REJECTED: Below thresholds (Liq: $%.0f, Vol: $%.0f)\n\n", r.Liquidity, r.Volume))
+
+	case r.Stage == pipeline.StageBscScan:
+		out.WriteString(fmt.Sprintf("  REJECTED: %s\n\n", r.FailureReasons[0]))
+
+	case r.Stage == pipeline.StageLiquidity:
+		out.WriteString(fmt.Sprintf("  REJECTED [liquidity_drain]: %s\n", r.FailureReasons[0]))
+		out.WriteString(fmt.Sprintf("  Liquidity: %s\n\n", formatDeltas(r.LiquidityDrain.Deltas)))
+
+	case r.Stage == pipeline.StageFraud:
+		for _, hit := range r.Fraud.Rejections() {
+			out.WriteString(fmt.Sprintf("  REJECTED [%s]: %s\n", hit.Rule, hit.Message))
+		}
+		if len(r.RiskFactors) > 0 {
+			out.WriteString(fmt.Sprintf("  Risk Factors: %v\n", r.RiskFactors))
+		}
+		out.WriteString("\n")
+
+	default:
+		score := r.TokenScore
+		out.WriteString(fmt.Sprintf("  Verified: %t | Liq: $%.0f | Vol: $%.0f | Age: %.1fd | Frag: %t | Conc: %.2f%%\n",
+			r.Verified, r.Liquidity, r.Volume, r.Age, !r.Fragmented, r.Concentration))
+		if len(r.Quotes) > 1 {
+			out.WriteString(fmt.Sprintf("  Quotes: %s\n", formatQuotes(r.Quotes)))
+		}
+		out.WriteString(fmt.Sprintf("  Score: %.2f (L:%.0f V:%.0f H:%.0f F:%.0f C:%.0f R:%.0f)\n",
+			score.CompositeScore, score.LiquidityScore, score.VolumeScore, score.HolderScore, score.FragmentationScore,
+			score.ContractScore, score.FraudScore))
+
+		if d := r.HolderDistribution; d != nil {
+			out.WriteString(fmt.Sprintf("  Holders (on-chain): %d | Top %d: %.2f%% | Gini: %.2f%s\n",
+				d.HolderCount, d.TopN, d.TopNConcentration, d.Gini, formatExcluded(d.Excluded)))
+		}
+		if b := r.HolderBreakdown; b != nil {
+			out.WriteString(fmt.Sprintf("  Holders (%s): Top 10: %.2f%% (raw %.2f%%) | %s\n",
+				b.Source, b.Top10Concentration, b.RawTop10Concentration, formatClasses(b.Classes)))
+		}
+
+		if d := r.LiquidityDrain; d != nil && len(d.Deltas) > 0 {
+			out.WriteString(fmt.Sprintf("  Liquidity: %s\n", formatDeltas(d.Deltas)))
+		}
+
+		if r.Slippage != nil {
+			out.WriteString(fmt.Sprintf("  Slippage: %s (Score: %.0f/100)\n", formatSlippage(r.Slippage), score.SlippageScore))
+		}
+
+		// Add fraud risk factors if any
+		if len(r.RiskFactors) > 0 {
+			out.WriteString(fmt.Sprintf("  Fraud Risk: %v (Score: %d/100)\n", r.RiskFactors, r.Fraud.RiskScore))
+		}
+
+		if r.Proxy != nil {
+			out.WriteString(fmt.Sprintf("  Proxy: %s -> %s (%s), upgrader %s\n",
+				r.Proxy.ResolvedBy, r.Proxy.Implementation, r.Proxy.ImplementationName, r.Proxy.Upgrader))
+		}
+
+		// Add contract red flags from the source analysis
+		if r.ContractAnalysis != nil && len(r.ContractAnalysis.Kinds()) > 0 {
+			out.WriteString(fmt.Sprintf("  Contract Flags: %v (Score: %.0f/100)\n", r.ContractAnalysis.Kinds(), score.ContractScore))
+		}
+
+		if r.Status == pipeline.StatusPassed {
+			listing := r.Listing(listingThresholds(cfg))
+			out.WriteString(fmt.Sprintf("  Result: PASSED - %s (%s)\n", strings.ToUpper(listing.Status), listing.Reasons[0]))
+			for _, warning := range listing.Warnings {
+				out.WriteString(fmt.Sprintf("  ⚠️  %s\n", warning))
+			}
+			out.WriteString("\n")
+		} else {
+			out.WriteString(fmt.Sprintf("  Result: REJECTED - %s\n\n", r.FailureReasons[0]))
+		}
+	}
+
+	return out.String()
+}
+
+// formatExcluded renders excluded holders as " | Excluded: pair 59.00%, locker (PinkLock) 5.00%"
+func formatExcluded(excluded []holders.Holder) string {
+	if len(excluded) == 0 {
+		return ""
+	}
+	parts := make([]string, len(excluded))
+	for i, h := range excluded {
+		parts[i] = fmt.Sprintf("%s %.2f%%", h.Label, h.Percent)
+		if h.Name != "" {
+			parts[i] = fmt.Sprintf("%s (%s) %.2f%%", h.Label, h.Name, h.Percent)
+		}
+	}
+	return " | Excluded: " + strings.Join(parts, ", ")
+}
+
+// formatClasses renders class shares as "burn 34.30% (1), pair 8.81% (1), wallet 52.40% (8)"
+func formatClasses(classes []holders.ClassShare) string {
+	parts := make([]string, len(classes))
+	for i, c := range classes {
+		parts[i] = fmt.Sprintf("%s %.2f%% (%d)", c.Label, c.Percent, c.Holders)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatSlippage renders simulated trades as "$1K 0.03% via v2 USDT>TOKEN, ..."
+func formatSlippage(s *slippage.Result) string {
+	parts := make([]string, len(s.Trades))
+	for i, t := range s.Trades {
+		parts[i] = fmt.Sprintf("$%gK %.2f%% via %s", t.SizeUSD/1000, t.PriceImpactPct, t.Route)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDeltas renders liquidity changes as "1h -2.1% | 24h +4.0%"
+func formatDeltas(deltas []liquidity.Delta) string {
+	parts := make([]string, len(deltas))
+	for i, d := range deltas {
+		parts[i] = fmt.Sprintf("%s %+.1f%%", d.Window, d.ChangePct)
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatTransition renders a watch state change, e.g.
+// "CAKE (0x0e09…): FEATURED (91.0) -> FAILED: rejected at fraud stage"
+func formatTransition(t watch.Transition) string {
+	from := t.From
+	switch t.From {
+	case "":
+		from = "NEW"
+	case watch.StateFailed:
+	default:
+		from += fmt.Sprintf(" (%.1f)", t.FromScore)
+	}
+	to := t.To
+	if t.To != watch.StateFailed {
+		to += fmt.Sprintf(" (%.1f)", t.ToScore)
+	}
+	return fmt.Sprintf("%s (%s): %s -> %s: %s", t.Symbol, t.Address, from, to, strings.Join(t.Reasons, "; "))
+}
+
+// formatWatchlist renders each watched token's state and schedule
+func formatWatchlist(entries []watch.Entry) string {
+	var out strings.Builder
+	out.WriteString("\n" + repeatChar('=', 60) + "\n")
+	out.WriteString("                      WATCHLIST\n")
+	out.WriteString(repeatChar('=', 60) + "\n")
+	for _, e := range entries {
+		state := e.State
+		if state == "" {
+			state = "UNKNOWN"
+		}
+		line := fmt.Sprintf("  %-10s %-9s", truncate(e.Token.Symbol, 10), state)
+		if e.State != "" && e.State != watch.StateFailed {
+			line += fmt.Sprintf(" %5.1f", e.Score)
+		} else {
+			line += "      "
+		}
+		if !e.NextCheck.IsZero() {
+			line += "  next " + e.NextCheck.Format("2006-01-02 15:04")
+		}
+		line += "  " + e.Token.Address
+		if e.LastError != "" {
+			line += "  last error: " + truncate(e.LastError, 60)
+		}
+		out.WriteString(line + "\n")
+	}
+	out.WriteString(repeatChar('=', 60) + "\n")
+	return out.String()
+}
+
+func generateSummary(stats pipeline.Statistics) string {
+	summary := "\n" + repeatChar('=', 60) + "\n"
+	summary += "                  SCREENING SUMMARY\n"
+	summary += repeatChar('=', 60) + "\n\n"
+	summary += fmt.Sprintf("Total Tokens Processed: %d\n\n", stats.TotalTokens)
+
+	summary += "Data Availability:\n"
+	summary += fmt.Sprintf("  • Tokens with errors: %d (%.1f%%)\n",
+		stats.ErrorCount, float64(stats.ErrorCount)/float64(stats.TotalTokens)*100)
+	summary += fmt.Sprintf("    - No supported quote pairs: %d\n", stats.NoQuotePairs)
+	summary += fmt.Sprintf("    - Not on DexScreener: %d\n", stats.NoDexScreenerData)
+	summary += fmt.Sprintf("    - Fraud API errors: %d\n", stats.FraudAPIErrors)
+	summary += fmt.Sprintf("    - Other errors: %d\n", stats.OtherErrors)
+	summary += fmt.Sprintf("  • Tokens evaluated: %d (%.1f%%)\n\n",
+		stats.EvaluatedCount, float64(stats.EvaluatedCount)/float64(stats.TotalTokens)*100)
+
+	summary += "Evaluation Results:\n"
+	if stats.EvaluatedCount > 0 {
+		summary += fmt.Sprintf("  • PASSED: %d (%.1f%% of evaluated)\n",
+			stats.PassedCount, float64(stats.PassedCount)/float64(stats.EvaluatedCount)*100)
+		summary += fmt.Sprintf("  • FAILED: %d (%.1f%% of evaluated)\n",
+			stats.FailedCount, float64(stats.FailedCount)/float64(stats.EvaluatedCount)*100)
+		if stats.HoneypotRejected > 0 {
+			summary += fmt.Sprintf("    - Honeypot/Fraud: %d\n", stats.HoneypotRejected)
+		}
+	} else {
+		summary += "  No tokens were evaluated\n"
+	}
+
+	summary += "\nListing Tiers:\n"
+	summary += fmt.Sprintf("  • FEATURED: %d\n", stats.FeaturedCount)
+	summary += fmt.Sprintf("  • VISIBLE (with warnings): %d\n", stats.VisibleCount)
+	summary += fmt.Sprintf("  • HIDDEN: %d (incl. failed and errors)\n", stats.HiddenCount)
+
+	summary += fmt.Sprintf("\nFinal Whitelisted Tokens: %d/%d (%.1f%% of total)\n",
+		stats.FeaturedCount+stats.VisibleCount, stats.TotalTokens,
+		float64(stats.FeaturedCount+stats.VisibleCount)/float64(stats.TotalTokens)*100)
+	summary += repeatChar('=', 60) + "\n"
+
+	return summary
+}
+
+func generateDetailedBreakdown(results []pipeline.TokenResult, stats pipeline.Statistics) string {
+	var breakdown strings.Builder
+	breakdown.WriteString("\n\n" + repeatChar('=', 60) + "\n")
+	breakdown.WriteString("                 DETAILED BREAKDOWN\n")
+	breakdown.WriteString(repeatChar('=', 60) + "\n\n")
+
+	// Passed tokens
+	breakdown.WriteString(fmt.Sprintf("PASSED TOKENS (%d):\n", stats.PassedCount))
+	breakdown.WriteString(repeatChar('-', 60) + "\n")
+	breakdown.WriteString(fmt.Sprintf("%-10s | %-42s | Score | Liquidity\n", "Symbol", "Address"))
+	breakdown.WriteString(repeatChar('-', 60) + "\n")
+	for _, r := range results {
+		if r.Status == "PASSED" {
+			breakdown.WriteString(fmt.Sprintf("%-10s | %s | %.2f | $%.0f\n",
+				truncate(r.Symbol, 10), r.Address, r.Score, r.Liquidity))
+		}
+	}
+
+	// Failed tokens
+	breakdown.WriteString(fmt.Sprintf("\n\nFAILED TOKENS (%d):\n", stats.FailedCount))
+	breakdown.WriteString(repeatChar('-', 60) + "\n")
+	breakdown.WriteString(fmt.Sprintf("%-10s | %-42s | Reason\n", "Symbol", "Address"))
+	breakdown.WriteString(repeatChar('-', 60) + "\n")
+	for _, r := range results {
+		if r.Status == "FAILED" {
+			reason := "Unknown"
+			if len(r.FailureReasons) > 0 {
+				reason = truncate(r.FailureReasons[0], 40)
+			}
+			breakdown.WriteString(fmt.Sprintf("%-10s | %s | %s\n", truncate(r.Symbol, 10), r.Address, reason))
+		}
+	}
+
+	// Error tokens (sample)
+	breakdown.WriteString(fmt.Sprintf("\n\nERROR TOKENS (showing first 50 of %d):\n", stats.ErrorCount))
+	breakdown.WriteString(repeatChar('-', 60) + "\n")
+	breakdown.WriteString(fmt.Sprintf("%-10s | %-42s | Error\n", "Symbol", "Address"))
+	breakdown.WriteString(repeatChar('-', 60) + "\n")
+	count := 0
+	for _, r := range results {
+		if r.Status == "ERROR" && count < 50 {
+			breakdown.WriteString(fmt.Sprintf("%-10s | %s | %s\n",
+				truncate(r.Symbol, 10), r.Address, truncate(r.ErrorReason, 40)))
+			count++
+		}
+	}
+
+	return breakdown.String()
+}
+
+func generateAPIUsage(providerStats map[string]transport.Stats, cacheStats cache.Stats) string {
+	providers := make([]string, 0, len(providerStats))
+	for provider := range providerStats {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	var usage strings.Builder
+	usage.WriteString("\n\n" + repeatChar('=', 60) + "\n")
+	usage.WriteString("                    API USAGE\n")
+	usage.WriteString(repeatChar('=', 60) + "\n")
+	usage.WriteString(fmt.Sprintf("%-12s | %8s | %7s | %5s | %5s | %5s | %6s | Throttled\n",
+		"Provider", "Requests", "Retries", "429s", "5xx", "Net", "Failed"))
+	usage.WriteString(repeatChar('-', 60) + "\n")
+	for _, provider := range providers {
+		s := providerStats[provider]
+		usage.WriteString(fmt.Sprintf("%-12s | %8d | %7d | %5d | %5d | %5d | %6d | %s\n",
+			provider, s.Requests, s.Retries, s.RateLimited, s.ServerErrors, s.NetworkErrors, s.Failures,
+			s.Throttled.Round(time.Second)))
+	}
+	if cacheStats.Hits+cacheStats.Misses > 0 {
+		usage.WriteString(fmt.Sprintf("Response cache: %d served from cache, %d fetched (%d kept)\n",
+			cacheStats.Hits, cacheStats.Misses, cacheStats.Stored))
+	}
+
+	return usage.String()
+}
+
+// formatQuotes renders the per-quote liquidity breakdown, e.g. "WBNB $1.2M (2 pairs)"
+func formatQuotes(quotes []market.QuoteLiquidity) string {
+	parts := make([]string, len(quotes))
+	for i, q := range quotes {
+		parts[i] = fmt.Sprintf("%s $%.0f (%d pairs)", q.Symbol, q.LiquidityUSD, q.Pairs)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func repeatChar(char rune, count int) string {
+	var result strings.Builder
+	for range count {
+		result.WriteString(string(char))
+	}
+	return result.String()
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/storage"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/watch"
+)
+
+// runWatch keeps re-screening a watchlist and reports state changes. With a
+// database, transitions and results are persisted and the last recorded
+// states survive restarts.
+func runWatch(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("watch", cfg)
+	input := fs.String("input", "watchlist.txt", "watchlist: JSON array or plain-text address list; reloaded when it changes")
+	reload := fs.Duration("reload", time.Minute, "how often to check the watchlist file for changes; 0 = never")
+	once := fs.Bool("once", false, "screen every token once, report changes since the last recorded state and exit")
+	verbose := fs.Bool("verbose", false, "print the full report of every screening, not only state changes")
+	fs.DurationVar(&cfg.Watch.Featured, "featured-interval", cfg.Watch.Featured, "re-screen interval of featured tokens (env WATCH_FEATURED_INTERVAL)")
+	fs.DurationVar(&cfg.Watch.Visible, "visible-interval", cfg.Watch.Visible, "re-screen interval of visible tokens (env WATCH_VISIBLE_INTERVAL)")
+	fs.DurationVar(&cfg.Watch.Hidden, "hidden-interval", cfg.Watch.Hidden, "re-screen interval of hidden and rejected tokens (env WATCH_HIDDEN_INTERVAL)")
+	fs.DurationVar(&cfg.Watch.Error, "error-interval", cfg.Watch.Error, "retry interval after a screening error (env WATCH_ERROR_INTERVAL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := finishConfig(cfg, true); err != nil {
+		return err
+	}
+	if err := requireAPIKey(cfg); err != nil {
+		return err
+	}
+
+	tokenInfos, err := readTokens(*input)
+	if err != nil {
+		return err
+	}
+
+	registry := transport.NewRegistry(cfg.ProviderLimits)
+	clients, responseCache, err := newClients(cfg, registry)
+	if err != nil {
+		return err
+	}
+	defer responseCache.Close()
+	screener := pipeline.New(cfg, clients)
+	watcher := watch.New(screener, clients.Chain.Name, cfg.Watch, listingThresholds(cfg))
+	notifier, err := newNotifier(cfg, registry)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Optional Postgres persistence: one screening run per watch session,
+	// holding the latest result of each token
+	var store *storage.Store
+	var runID int64
+	if cfg.DatabaseURL != "" {
+		store, err = storage.Open(ctx, cfg.DatabaseURL, listingThresholds(cfg))
+		if err != nil {
+			return fmt.Errorf("could not open database: %w", err)
+		}
+		defer store.Close()
+		screener.WithSnapshots(store)
+		watcher.WithHistory(store)
+
+		if err := watcher.Restore(ctx); err != nil {
+			return err
+		}
+		runID, err = store.StartRun(ctx, "watch:"+*input, len(tokenInfos))
+		if err != nil {
+			return fmt.Errorf("could not record screening run: %w", err)
+		}
+		defer func() {
+			if err := store.FinishRun(context.Background(), runID); err != nil {
+				fmt.Printf("WARNING: Could not finish screening run: %v\n", err)
+			}
+		}()
+	} else {
+		fmt.Println("WARNING: DATABASE_URL not set, watch history is kept in memory only")
+	}
+	watcher.Set(tokenInfos)
+
+	fmt.Printf("Watching %d tokens on %s (featured every %s, visible %s, hidden %s)\n\n",
+		len(tokenInfos), clients.Chain.Name, cfg.Watch.Featured, cfg.Watch.Visible, cfg.Watch.Hidden)
+
+	onResult := func(r pipeline.TokenResult, change *watch.Transition) {
+		if *verbose {
+			fmt.Print(formatTokenResult(0, 1, r, cfg))
+		}
+		if change != nil {
+			fmt.Printf("%s %s\n", change.At.Format("2006-01-02 15:04:05"), formatTransition(*change))
+		}
+		if r.Status == pipeline.StatusError {
+			fmt.Printf("%s %s %s: not screened: %s\n", time.Now().Format("2006-01-02 15:04:05"), r.Symbol, r.Address, r.ErrorReason)
+		}
+		if store != nil {
+			if err := store.SaveResult(ctx, runID, r); err != nil {
+				fmt.Printf("  WARNING: Could not save result: %v\n", err)
+			}
+		}
+		sendAlerts(ctx, notifier, watchAlerts(r, change)...)
+	}
+	onError := func(err error) {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+
+	if *once {
+		_, err := watcher.Cycle(ctx, onResult)
+		if err != nil {
+			onError(err)
+		}
+		fmt.Print(formatWatchlist(watcher.Entries()))
+		return nil
+	}
+
+	if *reload > 0 && *input != "-" {
+		go reloadWatchlist(ctx, watcher, *input, *reload)
+	}
+	err = watcher.Run(ctx, onResult, onError)
+	fmt.Print(formatWatchlist(watcher.Entries()))
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// reloadWatchlist replaces the watchlist whenever the file's modification
+// time changes, until ctx is cancelled
+func reloadWatchlist(ctx context.Context, watcher *watch.Watcher, fileName string, every time.Duration) {
+	var modified time.Time
+	if info, err := os.Stat(fileName); err == nil {
+		modified = info.ModTime()
+	}
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(fileName)
+		if err != nil || info.ModTime().Equal(modified) {
+			continue
+		}
+		tokens, err := readTokens(fileName)
+		if err != nil {
+			fmt.Printf("WARNING: Keeping the current watchlist: %v\n", err)
+			continue
+		}
+		modified = info.ModTime()
+		watcher.Set(tokens)
+		fmt.Printf("Reloaded %s: watching %d tokens\n", fileName, len(tokens))
+	}
+}
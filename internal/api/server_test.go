@@ -0,0 +1,185 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/api"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/storage"
+)
+
+const cake = "0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82"
+
+var listing = pipeline.ListingThresholds{Featured: 70, Visible: 50}
+
+// fakeScreener passes every token with a fixed score. When release is set,
+// each screen waits for it (or for ctx) before returning.
+type fakeScreener struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeScreener) ScreenToken(ctx context.Context, tokenInfo models.BasicTokenInfo) pipeline.TokenResult {
+	if f.release != nil {
+		f.started <- struct{}{}
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+		}
+	}
+	return pipeline.TokenResult{
+		Chain: "bsc", Address: tokenInfo.Address, Symbol: tokenInfo.Symbol,
+		Status: "PASSED", Stage: "scoring", Score: 80, CheckedAt: time.Now(),
+	}
+}
+
+func newServer(t *testing.T, screener api.Screener) (*httptest.Server, *storage.MemoryStore) {
+	t.Helper()
+	store := storage.NewMemoryStore(listing)
+	srv := httptest.NewServer(api.NewServer(store, screener, listing).WithScreenLimit(1))
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func do(t *testing.T, method, url, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decoding: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestGetToken(t *testing.T) {
+	srv, store := newServer(t, &fakeScreener{})
+	store.SaveResult(context.Background(), 1, pipeline.TokenResult{
+		Chain: "bsc", Address: cake, Symbol: "CAKE", Status: "PASSED", Stage: "scoring", Score: 75,
+	})
+
+	var found api.TokenResponse
+	if status := do(t, http.MethodGet, srv.URL+"/tokens/"+strings.ToLower(cake), "", &found); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if found.Result == nil || found.Result.Symbol != "CAKE" || found.ListingStatus != pipeline.ListingFeatured {
+		t.Errorf("response = %+v", found)
+	}
+
+	for path, want := range map[string]int{
+		"/tokens/0x0000000000000000000000000000000000000001": http.StatusNotFound,
+		"/tokens/" + cake + "?chain=ethereum":                http.StatusNotFound,
+		"/tokens/" + cake + "?chain=nowhere":                 http.StatusBadRequest,
+		"/tokens/0x1234":                                     http.StatusBadRequest,
+	} {
+		var body map[string]string
+		if status := do(t, http.MethodGet, srv.URL+path, "", &body); status != want || body["error"] == "" {
+			t.Errorf("GET %s = %d %v, want %d with an error", path, status, body, want)
+		}
+	}
+}
+
+func TestListTokens(t *testing.T) {
+	srv, store := newServer(t, &fakeScreener{})
+	for i, r := range []pipeline.TokenResult{
+		{Status: "PASSED", Stage: "scoring", Score: 75},
+		{Status: "PASSED", Stage: "scoring", Score: 60},
+		{Status: "FAILED", Stage: "scoring", Score: 20},
+	} {
+		r.Chain, r.Address = "bsc", "0x000000000000000000000000000000000000000"+string(rune('1'+i))
+		store.SaveResult(context.Background(), 1, r)
+	}
+
+	var list struct {
+		Count  int            `json:"count"`
+		Tokens []models.Token `json:"tokens"`
+	}
+	if status := do(t, http.MethodGet, srv.URL+"/tokens", "", &list); status != http.StatusOK || list.Count != 3 {
+		t.Fatalf("GET /tokens = %d, %d tokens", status, list.Count)
+	}
+	if list.Tokens[0].CompositeScore != 75 || list.Tokens[2].CompositeScore != 20 {
+		t.Errorf("tokens not ordered by score: %+v", list.Tokens)
+	}
+
+	if status := do(t, http.MethodGet, srv.URL+"/tokens?status=visible", "", &list); status != http.StatusOK || list.Count != 1 || list.Tokens[0].CompositeScore != 60 {
+		t.Errorf("GET /tokens?status=visible = %d, %+v", status, list.Tokens)
+	}
+	if status := do(t, http.MethodGet, srv.URL+"/tokens?chain=ethereum", "", &list); status != http.StatusOK || list.Count != 0 || list.Tokens == nil {
+		t.Errorf("GET /tokens?chain=ethereum = %d, %+v, want an empty list", status, list.Tokens)
+	}
+
+	for _, query := range []string{"?status=unknown", "?limit=0", "?limit=abc", "?chain=nowhere"} {
+		if status := do(t, http.MethodGet, srv.URL+"/tokens"+query, "", nil); status != http.StatusBadRequest {
+			t.Errorf("GET /tokens%s = %d, want 400", query, status)
+		}
+	}
+}
+
+func TestScreen(t *testing.T) {
+	srv, store := newServer(t, &fakeScreener{})
+
+	for _, body := range []string{`{"address":"0x1234"}`, `{}`, `not json`} {
+		if status := do(t, http.MethodPost, srv.URL+"/screen", body, nil); status != http.StatusBadRequest {
+			t.Errorf("POST /screen %s = %d, want 400", body, status)
+		}
+	}
+
+	var screened api.TokenResponse
+	if status := do(t, http.MethodPost, srv.URL+"/screen", `{"address":"`+cake+`","symbol":"CAKE"}`, &screened); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if screened.Result == nil || screened.Result.Score != 80 {
+		t.Errorf("response = %+v", screened)
+	}
+	if _, err := store.LatestResult(context.Background(), "bsc", cake); err != nil {
+		t.Errorf("result not stored: %v", err)
+	}
+}
+
+func TestScreenLimit(t *testing.T) {
+	screener := &fakeScreener{started: make(chan struct{}), release: make(chan struct{})}
+	srv, _ := newServer(t, screener)
+
+	first := make(chan int)
+	go func() {
+		resp, err := http.Post(srv.URL+"/screen", "application/json", strings.NewReader(`{"address":"`+cake+`"}`))
+		if err != nil {
+			first <- 0
+			return
+		}
+		resp.Body.Close()
+		first <- resp.StatusCode
+	}()
+	<-screener.started
+
+	// The only slot is taken, so a second screen is turned away
+	if status := do(t, http.MethodPost, srv.URL+"/screen", `{"address":"`+cake+`"}`, nil); status != http.StatusServiceUnavailable {
+		t.Errorf("second screen = %d, want 503", status)
+	}
+
+	close(screener.release)
+	if status := <-first; status != http.StatusOK {
+		t.Errorf("first screen = %d", status)
+	}
+
+	// Once it finishes the slot is free again
+	go func() { <-screener.started }()
+	if status := do(t, http.MethodPost, srv.URL+"/screen", `{"address":"`+cake+`"}`, nil); status != http.StatusOK {
+		t.Errorf("screen after the first finished = %d", status)
+	}
+}
@@ -0,0 +1,336 @@
+// Package cache keeps provider responses so repeated screenings of the same
+// tokens do not spend rate-limit budget on data that has not changed.
+// Entries are keyed by provider, API host, endpoint, chain and address, and
+// expire by a TTL per endpoint: token supply changes slowly, liquidity is
+// stale within minutes. Only responses known to be immutable, such as a
+// contract's creation record or the verified source of a contract that is
+// not a proxy, are kept without expiry. Entries live in memory and, when
+// a file is configured, in a BoltDB file so they survive restarts.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// Endpoints, each with its own TTL
+const (
+	SourceCode       = "source_code"       // Etherscan getsourcecode
+	ContractCreation = "contract_creation" // Etherscan getcontractcreation
+	TokenSupply      = "token_supply"      // Etherscan tokensupply
+	Liquidity        = "liquidity"         // DexScreener token pairs
+	TopHolders       = "top_holders"       // Honeypot.is TopHolders
+	Honeypot         = "honeypot"          // Honeypot.is IsHoneypot simulation
+	TokenSecurity    = "token_security"    // GoPlus token security
+)
+
+// Endpoints lists every cached endpoint
+var Endpoints = []string{SourceCode, ContractCreation, TokenSupply, Liquidity, TopHolders, Honeypot, TokenSecurity}
+
+// Key identifies one cached response. BaseURL keeps responses from
+// different hosts (a test server, a self-hosted mirror) apart.
+type Key struct {
+	Provider string
+	BaseURL  string
+	Endpoint string
+	Chain    int64
+	Address  string
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s/%s/%d/%s", k.Provider, k.BaseURL, k.Endpoint, k.Chain, k.Address)
+}
+
+// normalize lowercases the address and the scheme and host of the base URL
+// and drops a trailing slash, so equivalent keys share an entry. The cache
+// normalizes every key before it is used, in memory and in the store.
+func (k Key) normalize() Key {
+	k.Address = strings.ToLower(k.Address)
+	k.BaseURL = strings.TrimSuffix(k.BaseURL, "/")
+	if u, err := url.Parse(k.BaseURL); err == nil {
+		u.Scheme, u.Host = strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+		k.BaseURL = u.String()
+	}
+	return k
+}
+
+// Retention says whether and how long a fetched response is kept
+type Retention int
+
+const (
+	Discard Retention = iota // Not kept: errors, rate limits, missing data
+	Expire                   // Kept for the endpoint's TTL
+	Forever                  // Kept without expiry: the response cannot change
+)
+
+// ValidJSON keeps any well-formed JSON body for the endpoint's TTL
+func ValidJSON(body []byte) Retention {
+	if json.Valid(body) {
+		return Expire
+	}
+	return Discard
+}
+
+// Entry is a stored response body
+type Entry struct {
+	Body     []byte    `json:"body"`
+	StoredAt time.Time `json:"stored_at"`
+	Forever  bool      `json:"forever,omitempty"`
+}
+
+// Store persists entries beyond the process
+type Store interface {
+	Load(key Key) (Entry, bool, error)
+	Save(key Key, e Entry) error
+}
+
+// Stats counts cache lookups
+type Stats struct {
+	Hits   int64 `json:"hits"`   // Served from the cache
+	Misses int64 `json:"misses"` // Fetched from the provider
+	Stored int64 `json:"stored"` // Fetched responses kept
+}
+
+// DefaultMaxEntries caps the entries held in memory unless WithMaxEntries
+// says otherwise. The store, if any, keeps every entry.
+const DefaultMaxEntries = 10000
+
+// Cache is a TTL cache of provider responses. It is safe for concurrent
+// use. A nil *Cache fetches every time.
+type Cache struct {
+	ttl        map[string]time.Duration
+	store      Store
+	refresh    bool
+	now        func() time.Time
+	maxEntries int
+	onError    func(error)
+
+	mu  sync.Mutex
+	mem map[Key]*list.Element // Values are memEntry, in lru
+	lru *list.List            // Most recently used first
+
+	hits, misses, stored atomic.Int64
+}
+
+// New creates an in-memory cache. ttl maps endpoints to how long their
+// responses stay fresh. An endpoint without a TTL only keeps responses that
+// never expire; a TTL of zero or less disables caching for it.
+func New(ttl map[string]time.Duration) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		now:        time.Now,
+		maxEntries: DefaultMaxEntries,
+		mem:        make(map[Key]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// WithMaxEntries caps the entries held in memory at n, dropping the least
+// recently used beyond that. A cap of zero or less keeps the default.
+func (c *Cache) WithMaxEntries(n int) *Cache {
+	if n > 0 {
+		c.maxEntries = n
+	}
+	return c
+}
+
+// WithStore keeps entries in s as well as in memory
+func (c *Cache) WithStore(s Store) *Cache {
+	c.store = s
+	return c
+}
+
+// WithRefresh ignores stored entries, so every response is fetched again
+// and replaces the stored one
+func (c *Cache) WithRefresh(refresh bool) *Cache {
+	c.refresh = refresh
+	return c
+}
+
+// WithOnError reports store failures to onError. The cache keeps working
+// without the store's copy, so these are warnings rather than errors.
+func (c *Cache) WithOnError(onError func(error)) *Cache {
+	c.onError = onError
+	return c
+}
+
+// WithClock replaces time.Now, for tests
+func (c *Cache) WithClock(now func() time.Time) *Cache {
+	c.now = now
+	return c
+}
+
+// Fetch returns the fresh entry for key, or calls fetch and stores the body
+// as long as fetch says to keep it. Error responses should be discarded, or
+// a rate-limit message would be served until it expires.
+func (c *Cache) Fetch(key Key, fetch func() (body []byte, keep Retention, err error)) ([]byte, error) {
+	if c == nil {
+		body, _, err := fetch()
+		return body, err
+	}
+	ttl, listed := c.ttl[key.Endpoint]
+	if listed && ttl <= 0 {
+		body, _, err := fetch()
+		return body, err
+	}
+	key = key.normalize()
+
+	if !c.refresh {
+		if e, ok := c.lookup(key); ok {
+			if e.Forever || c.now().Sub(e.StoredAt) < ttl {
+				c.hits.Add(1)
+				return e.Body, nil
+			}
+			// Expired entries are not kept in memory until replaced
+			c.forget(key)
+		}
+	}
+
+	c.misses.Add(1)
+	body, keep, err := fetch()
+	if err != nil || keep == Discard || (keep == Expire && ttl <= 0) {
+		return body, err
+	}
+
+	e := Entry{Body: body, StoredAt: c.now(), Forever: keep == Forever}
+	c.remember(key, e)
+	c.stored.Add(1)
+	if c.store != nil {
+		if err := c.store.Save(key, e); err != nil {
+			// The response is still good; only the on-disk copy is missing
+			c.warn(fmt.Errorf("storing cached response %s: %w", key, err))
+		}
+	}
+	return body, nil
+}
+
+// Get fetches url with client through the cache. Only 200 responses are
+// stored, for as long as keep says; a nil keep keeps them for the TTL.
+func (c *Cache) Get(ctx context.Context, client *transport.Client, key Key, url string, keep func(body []byte) Retention) ([]byte, error) {
+	return c.Fetch(key, func() ([]byte, Retention, error) {
+		resp, err := client.Get(ctx, url)
+		if err != nil {
+			return nil, Discard, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, Discard, err
+		}
+		switch {
+		case resp.StatusCode != http.StatusOK:
+			return body, Discard, nil
+		case keep == nil:
+			return body, Expire, nil
+		}
+		return body, keep(body), nil
+	})
+}
+
+// lookup returns the entry for key from memory, falling back to the store
+func (c *Cache) lookup(key Key) (Entry, bool) {
+	c.mu.Lock()
+	el, ok := c.mem[key]
+	if ok {
+		c.lru.MoveToFront(el)
+	}
+	c.mu.Unlock()
+	if ok {
+		return el.Value.(memEntry).entry, true
+	}
+	if c.store == nil {
+		return Entry{}, false
+	}
+
+	e, ok, err := c.store.Load(key)
+	if err != nil {
+		c.warn(fmt.Errorf("reading cached response %s: %w", key, err))
+		return Entry{}, false
+	}
+	if ok {
+		c.remember(key, e)
+	}
+	return e, ok
+}
+
+// warn passes err to the onError callback, if any
+func (c *Cache) warn(err error) {
+	if c.onError != nil {
+		c.onError(err)
+	}
+}
+
+// memEntry is an entry held in memory, with its key for eviction
+type memEntry struct {
+	key   Key
+	entry Entry
+}
+
+// remember holds e in memory as the most recently used entry, dropping the
+// least recently used ones beyond the cap
+func (c *Cache) remember(key Key, e Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.mem[key]; ok {
+		el.Value = memEntry{key, e}
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.mem[key] = c.lru.PushFront(memEntry{key, e})
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.mem, oldest.Value.(memEntry).key)
+	}
+}
+
+// forget drops the entry for key from memory
+func (c *Cache) forget(key Key) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.mem[key]; ok {
+		c.lru.Remove(el)
+		delete(c.mem, key)
+	}
+}
+
+// Len returns the number of entries held in memory
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Close closes the store, if it has one to close. A nil cache has none.
+func (c *Cache) Close() error {
+	if c == nil {
+		return nil
+	}
+	if closer, ok := c.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Stats returns a snapshot of the counters. A nil cache has none.
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Stored: c.stored.Load()}
+}
@@ -0,0 +1,293 @@
+package cache_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+)
+
+const cake = "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"
+
+var at = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+const etherscan = "https://api.etherscan.io/v2/api"
+
+// source counts fetches and answers with body, kept as keep says
+type source struct {
+	calls int
+	body  string
+	keep  cache.Retention
+	err   error
+}
+
+func (s *source) fetch() ([]byte, cache.Retention, error) {
+	s.calls++
+	return []byte(s.body), s.keep, s.err
+}
+
+func key(endpoint string) cache.Key {
+	return cache.Key{Provider: "etherscan", BaseURL: etherscan, Endpoint: endpoint, Chain: 56, Address: cake}
+}
+
+func TestFetchHonoursTTLs(t *testing.T) {
+	now := at
+	c := cache.New(map[string]time.Duration{
+		cache.SourceCode:       time.Hour,
+		cache.ContractCreation: 0,
+		cache.Liquidity:        5 * time.Minute,
+		cache.Honeypot:         -1,
+	}).WithClock(func() time.Time { return now })
+
+	liquidity := &source{body: `[]`, keep: cache.Expire}
+	creation := &source{body: `{"status":"1"}`, keep: cache.Expire}
+	honeypot := &source{body: `{}`, keep: cache.Expire}
+	supply := &source{body: `{"status":"1"}`, keep: cache.Expire}
+	for range 3 {
+		c.Fetch(key(cache.Liquidity), liquidity.fetch)
+		c.Fetch(key(cache.ContractCreation), creation.fetch)
+		c.Fetch(key(cache.Honeypot), honeypot.fetch)
+		c.Fetch(key(cache.TokenSupply), supply.fetch)
+	}
+	if liquidity.calls != 1 {
+		t.Errorf("cached endpoint fetched %d times, want once", liquidity.calls)
+	}
+	// Zero, negative and missing TTLs are not cached
+	if creation.calls != 3 || honeypot.calls != 3 || supply.calls != 3 {
+		t.Errorf("uncached endpoints fetched %d, %d and %d times, want 3", creation.calls, honeypot.calls, supply.calls)
+	}
+
+	now = now.Add(5 * time.Minute)
+	body, err := c.Fetch(key(cache.Liquidity), liquidity.fetch)
+	if err != nil || string(body) != `[]` || liquidity.calls != 2 {
+		t.Errorf("expired entry: body %q, err %v, %d fetches", body, err, liquidity.calls)
+	}
+
+	// Missing TTLs are looked up in case a response never expires
+	if s := c.Stats(); s.Hits != 2 || s.Misses != 5 || s.Stored != 2 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestFetchKeepsOnlyImmutableResponsesForever(t *testing.T) {
+	now := at
+	c := cache.New(map[string]time.Duration{cache.SourceCode: time.Hour}).
+		WithClock(func() time.Time { return now })
+
+	verified := &source{body: `{"status":"1","result":[{"SourceCode":"contract Cake {}"}]}`, keep: cache.Forever}
+	proxy := &source{body: `{"status":"1","result":[{"Proxy":"1"}]}`, keep: cache.Expire}
+	proxyKey := key(cache.SourceCode)
+	proxyKey.Address = "0x00000000000000000000000000000000000000aa"
+
+	c.Fetch(key(cache.SourceCode), verified.fetch)
+	c.Fetch(proxyKey, proxy.fetch)
+	now = now.Add(365 * 24 * time.Hour)
+	c.Fetch(key(cache.SourceCode), verified.fetch)
+	c.Fetch(proxyKey, proxy.fetch)
+	if verified.calls != 1 {
+		t.Errorf("verified source fetched %d times, want once", verified.calls)
+	}
+	if proxy.calls != 2 {
+		t.Errorf("proxy source fetched %d times, want it to expire", proxy.calls)
+	}
+
+	// Immutable responses need no TTL, but a TTL of zero turns them off too
+	creation := &source{body: `{"status":"1"}`, keep: cache.Forever}
+	c.Fetch(key(cache.ContractCreation), creation.fetch)
+	c.Fetch(key(cache.ContractCreation), creation.fetch)
+	off := cache.New(map[string]time.Duration{cache.ContractCreation: 0})
+	off.Fetch(key(cache.ContractCreation), creation.fetch)
+	off.Fetch(key(cache.ContractCreation), creation.fetch)
+	if creation.calls != 3 {
+		t.Errorf("contract creation fetched %d times, want 3", creation.calls)
+	}
+}
+
+func TestKeysAreNormalized(t *testing.T) {
+	c := cache.New(cache.DefaultConfig().TTL).WithStore(mustBoltStore(t, filepath.Join(t.TempDir(), "cache.db")))
+	liquidity := &source{body: `[]`, keep: cache.Expire}
+
+	c.Fetch(key(cache.Liquidity), liquidity.fetch)
+	same := key(cache.Liquidity)
+	same.Address = "0x" + strings.ToUpper(cake[2:])
+	same.BaseURL = "https://API.etherscan.io/v2/api/"
+	c.Fetch(same, liquidity.fetch)
+	if liquidity.calls != 1 {
+		t.Errorf("equivalent keys fetched %d times, want once", liquidity.calls)
+	}
+
+	// Another host, such as a test server, has its own entries
+	other := key(cache.Liquidity)
+	other.BaseURL = "http://127.0.0.1:8545/v2/api"
+	c.Fetch(other, liquidity.fetch)
+	if liquidity.calls != 2 {
+		t.Errorf("a different host was served the cached entry")
+	}
+}
+
+func TestMemoryIsBounded(t *testing.T) {
+	now := at
+	c := cache.New(map[string]time.Duration{cache.Liquidity: 5 * time.Minute}).
+		WithMaxEntries(2).
+		WithClock(func() time.Time { return now })
+	liquidity := &source{body: `[]`, keep: cache.Expire}
+	tokenKey := func(i int) cache.Key {
+		k := key(cache.Liquidity)
+		k.Address = fmt.Sprintf("0x%040x", i)
+		return k
+	}
+
+	// The least recently used entry goes first: 1 was read after 0
+	c.Fetch(tokenKey(0), liquidity.fetch)
+	c.Fetch(tokenKey(1), liquidity.fetch)
+	c.Fetch(tokenKey(0), liquidity.fetch)
+	c.Fetch(tokenKey(2), liquidity.fetch)
+	if c.Len() != 2 || liquidity.calls != 3 {
+		t.Fatalf("%d entries after %d fetches, want 2 after 3", c.Len(), liquidity.calls)
+	}
+	c.Fetch(tokenKey(0), liquidity.fetch)
+	c.Fetch(tokenKey(1), liquidity.fetch)
+	if liquidity.calls != 4 {
+		t.Errorf("%d fetches, want only the evicted entry fetched again", liquidity.calls)
+	}
+
+	// An expired entry is dropped when read, even if the refetch is discarded
+	now = now.Add(5 * time.Minute)
+	c.Fetch(tokenKey(0), (&source{body: `{"error":"rate limited"}`, keep: cache.Discard}).fetch)
+	if c.Len() != 1 {
+		t.Errorf("%d entries after an expired read, want 1", c.Len())
+	}
+}
+
+func mustBoltStore(t *testing.T, path string) *cache.BoltStore {
+	t.Helper()
+	store, err := cache.NewBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestFetchDoesNotKeepErrors(t *testing.T) {
+	c := cache.New(cache.DefaultConfig().TTL)
+
+	rateLimited := &source{body: `{"status":"0","result":"Max rate limit reached"}`, keep: cache.Discard}
+	for range 2 {
+		body, err := c.Fetch(key(cache.SourceCode), rateLimited.fetch)
+		if err != nil || !strings.Contains(string(body), "rate limit") {
+			t.Fatalf("body %q, err %v: the response should still be returned", body, err)
+		}
+	}
+	failing := &source{err: errors.New("connection refused"), keep: cache.Expire}
+	for range 2 {
+		if _, err := c.Fetch(key(cache.ContractCreation), failing.fetch); err == nil {
+			t.Fatal("fetch error was not returned")
+		}
+	}
+	if rateLimited.calls != 2 || failing.calls != 2 {
+		t.Errorf("error responses were cached: %d and %d fetches", rateLimited.calls, failing.calls)
+	}
+
+	// A nil cache fetches every time
+	var none *cache.Cache
+	ok := &source{body: `{}`, keep: cache.Expire}
+	none.Fetch(key(cache.SourceCode), ok.fetch)
+	none.Fetch(key(cache.SourceCode), ok.fetch)
+	if ok.calls != 2 || none.Stats() != (cache.Stats{}) {
+		t.Errorf("nil cache: %d fetches, stats %+v", ok.calls, none.Stats())
+	}
+}
+
+// brokenStore fails every load and save
+type brokenStore struct{}
+
+func (brokenStore) Load(cache.Key) (cache.Entry, bool, error) {
+	return cache.Entry{}, false, errors.New("disk unreadable")
+}
+
+func (brokenStore) Save(cache.Key, cache.Entry) error { return errors.New("disk full") }
+
+func TestStoreFailuresAreReported(t *testing.T) {
+	var reported []error
+	c := cache.New(cache.DefaultConfig().TTL).WithStore(brokenStore{}).
+		WithOnError(func(err error) { reported = append(reported, err) })
+
+	// The store's failures do not fail the fetch, and memory still serves it
+	src := &source{body: `{}`, keep: cache.Expire}
+	for range 2 {
+		if body, err := c.Fetch(key(cache.TokenSupply), src.fetch); err != nil || string(body) != "{}" {
+			t.Fatalf("body %q, err %v", body, err)
+		}
+	}
+	if src.calls != 1 {
+		t.Errorf("%d fetches, want 1", src.calls)
+	}
+	if len(reported) != 2 || !strings.Contains(reported[0].Error(), "disk unreadable") || !strings.Contains(reported[1].Error(), "disk full") {
+		t.Errorf("reported %v, want the failed load then the failed save", reported)
+	}
+}
+
+func TestBoltStoreSurvivesRestartAndRefresh(t *testing.T) {
+	cfg := cache.DefaultConfig()
+	cfg.Path = filepath.Join(t.TempDir(), "nested", "cache.db")
+	open := func() *cache.Cache {
+		t.Helper()
+		c, err := cache.Open(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	first := open()
+	src := &source{body: `{"status":"1","result":[{"ContractName":"CakeToken"}]}`, keep: cache.Forever}
+	first.Fetch(key(cache.SourceCode), src.fetch)
+
+	// The file is locked while open, so a second process fails fast
+	if _, err := cache.Open(cfg); err == nil || !strings.Contains(err.Error(), "in use by another process") {
+		t.Errorf("opening a locked cache file: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new process reads the stored entry instead of fetching
+	second := open()
+	body, err := second.Fetch(key(cache.SourceCode), src.fetch)
+	if err != nil || string(body) != src.body || src.calls != 1 {
+		t.Errorf("after restart: body %q, err %v, %d fetches", body, err, src.calls)
+	}
+	second.Close()
+
+	// -refresh fetches again and replaces the stored entry
+	cfg.Refresh = true
+	refreshed := open()
+	src.body = `{"status":"1","result":[{"ContractName":"CakeTokenV2"}]}`
+	refreshed.Fetch(key(cache.SourceCode), src.fetch)
+	refreshed.Close()
+	if src.calls != 2 {
+		t.Errorf("refresh served the stored entry")
+	}
+	cfg.Refresh = false
+	third := open()
+	defer third.Close()
+	if body, _ := third.Fetch(key(cache.SourceCode), src.fetch); !strings.Contains(string(body), "CakeTokenV2") {
+		t.Errorf("refreshed entry not stored: %s", body)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	if c, err := cache.Open(cache.Config{Enabled: false}); c != nil || err != nil {
+		t.Errorf("disabled cache = %v, %v", c, err)
+	}
+	cfg := cache.DefaultConfig()
+	cfg.TTL["pairs"] = time.Minute
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), `unknown ttl endpoints pairs`) {
+		t.Errorf("Validate = %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config configures the response cache
+type Config struct {
+	Enabled    bool                     `yaml:"enabled"`
+	Path       string                   `yaml:"path"`        // BoltDB file; empty = memory only
+	Refresh    bool                     `yaml:"refresh"`     // Fetch everything again, replacing stored entries
+	TTL        map[string]time.Duration `yaml:"ttl"`         // By endpoint; zero or negative = not cached
+	MaxEntries int                      `yaml:"max_entries"` // Held in memory; the least recently used go first
+}
+
+// MarshalYAML writes TTLs as "5m0s" rather than nanoseconds
+func (c Config) MarshalYAML() (any, error) {
+	ttl := make(map[string]string, len(c.TTL))
+	for endpoint, d := range c.TTL {
+		ttl[endpoint] = d.String()
+	}
+	return struct {
+		Enabled    bool              `yaml:"enabled"`
+		Path       string            `yaml:"path"`
+		Refresh    bool              `yaml:"refresh"`
+		TTL        map[string]string `yaml:"ttl"`
+		MaxEntries int               `yaml:"max_entries"`
+	}{c.Enabled, c.Path, c.Refresh, ttl, c.MaxEntries}, nil
+}
+
+// DefaultConfig keeps source code for 6 hours (verified source of a
+// contract that is not a proxy for good, like contract creation), token
+// supply and holders for half an hour, fraud checks for 15 minutes and
+// liquidity for 5, in .screener-cache.db and at most DefaultMaxEntries in
+// memory
+func DefaultConfig() Config {
+	return Config{
+		Enabled:    true,
+		Path:       ".screener-cache.db",
+		MaxEntries: DefaultMaxEntries,
+		TTL: map[string]time.Duration{
+			SourceCode:    6 * time.Hour,
+			TokenSupply:   30 * time.Minute,
+			TopHolders:    30 * time.Minute,
+			Honeypot:      15 * time.Minute,
+			TokenSecurity: 15 * time.Minute,
+			Liquidity:     5 * time.Minute,
+		},
+	}
+}
+
+// Validate checks that TTLs name known endpoints and the memory cap is not negative
+func (c Config) Validate() error {
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("max_entries must not be negative, got %d", c.MaxEntries)
+	}
+	var unknown []string
+	for endpoint := range c.TTL {
+		if !slices.Contains(Endpoints, endpoint) {
+			unknown = append(unknown, endpoint)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown ttl endpoints %s (want %s)", strings.Join(unknown, ", "), strings.Join(Endpoints, ", "))
+	}
+	return nil
+}
+
+// Open returns the configured cache, or nil when it is disabled
+func Open(c Config) (*Cache, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if !c.Enabled {
+		return nil, nil
+	}
+	cache := New(c.TTL).WithRefresh(c.Refresh).WithMaxEntries(c.MaxEntries)
+	if c.Path != "" {
+		store, err := NewBoltStore(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		cache.WithStore(store)
+	}
+	return cache, nil
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// entriesBucket holds every entry, keyed by Key.String()
+var entriesBucket = []byte("entries")
+
+// openTimeout bounds the wait for the file lock. BoltDB allows one process
+// per file, so a second run sharing the cache fails instead of hanging.
+const openTimeout = time.Second
+
+// BoltStore keeps entries in a single BoltDB file. Writes are transactions,
+// so a crash never leaves half an entry behind.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (or creates) the cache file at path, creating its
+// directory if needed
+func NewBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: openTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("cache file %s is in use by another process (use another -cache-file or -no-cache)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening cache file %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(entriesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening cache file %s: %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Load reads the entry for key; a missing entry is not an error
+func (s *BoltStore) Load(key Key) (Entry, bool, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		// The value is only valid inside the transaction
+		data = append(data, tx.Bucket(entriesBucket).Get([]byte(key.String()))...)
+		return nil
+	})
+	if err != nil || data == nil {
+		return Entry{}, false, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, false, fmt.Errorf("decoding cached response %s: %w", key, err)
+	}
+	return e, true, nil
+}
+
+// Save writes the entry for key, replacing any previous one
+func (s *BoltStore) Save(key Key, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).Put([]byte(key.String()), data)
+	})
+}
+
+// Close releases the file so another process can open it
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
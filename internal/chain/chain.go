@@ -0,0 +1,173 @@
+// Package chain describes the EVM chains the screener supports: chain IDs,
+// provider slugs and the quote assets (wrapped native token and stablecoins)
+// that count towards liquidity
+package chain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Token is a well-known token on a chain
+type Token struct {
+	Symbol   string `json:"symbol"`
+	Address  string `json:"address"`  // Lowercase
+	Decimals int    `json:"decimals"` // ERC-20 decimals (18 for custom quote assets)
+}
+
+// Chain carries everything the provider clients need to query one chain
+type Chain struct {
+	Name            string  // Canonical name, e.g. "bsc"
+	ID              int64   // EVM chain ID, used by Etherscan v2, Honeypot.is and GoPlus
+	DexScreenerSlug string  // Chain segment of DexScreener URLs
+	WrappedNative   Token   // WBNB, WETH, ...
+	Stablecoins     []Token // USD stablecoins quoted against
+	OtherQuotes     []Token // Other liquid quote assets, selectable but not used by default
+
+	RPCURL     string   // Public JSON-RPC endpoint used when none is configured
+	V2Router   string   // Uniswap V2-style router (getAmountsOut)
+	V3Quoter   string   // Uniswap V3-style QuoterV2 (quoteExactInputSingle)
+	V3FeeTiers []uint32 // Fee tiers of the V3 deployment, in hundredths of a bip
+	V2Factory  string   // Emits PairCreated for new V2 pairs
+	V3Factory  string   // Emits PoolCreated for new V3 pools
+}
+
+var (
+	BSC = Chain{
+		Name:            "bsc",
+		ID:              56,
+		DexScreenerSlug: "bsc",
+		WrappedNative:   Token{Symbol: "WBNB", Address: "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c", Decimals: 18},
+		Stablecoins: []Token{
+			{Symbol: "USDT", Address: "0x55d398326f99059ff775485246999027b3197955", Decimals: 18},
+			{Symbol: "BUSD", Address: "0xe9e7cea3dedca5984780bafc599bd69add087d56", Decimals: 18},
+			{Symbol: "USDC", Address: "0x8ac76a51cc950d9822d68b83fe1ad97b32cd580d", Decimals: 18},
+			{Symbol: "FDUSD", Address: "0xc5f0f7b66764f6ec8c8dff7ba683102295e16409", Decimals: 18},
+		},
+		OtherQuotes: []Token{
+			{Symbol: "ETH", Address: "0x2170ed0880ac9a755fd29b2688956bd959f933f8", Decimals: 18},
+			{Symbol: "BTCB", Address: "0x7130d2a12b9bcbfae4f2634d864a1ee1ce3ead9c", Decimals: 18},
+		},
+
+		RPCURL:     "https://bsc-dataseed.bnbchain.org",
+		V2Router:   "0x10ed43c718714eb63d5aa57b78b54704e256024e", // PancakeSwap V2
+		V3Quoter:   "0xb048bbc1ee6b733fffcfb9e9cef7375518e25997", // PancakeSwap V3
+		V3FeeTiers: []uint32{100, 500, 2500, 10000},
+		V2Factory:  "0xca143ce32fe78f1f7019d7d551a6402fc5350c73",
+		V3Factory:  "0x0bfbcf9fa4f9c56b0f40a671ad40e0805a091865",
+	}
+
+	Ethereum = Chain{
+		Name:            "ethereum",
+		ID:              1,
+		DexScreenerSlug: "ethereum",
+		WrappedNative:   Token{Symbol: "WETH", Address: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Decimals: 18},
+		Stablecoins: []Token{
+			{Symbol: "USDT", Address: "0xdac17f958d2ee523a2206206994597c13d831ec7", Decimals: 6},
+			{Symbol: "USDC", Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Decimals: 6},
+			{Symbol: "DAI", Address: "0x6b175474e89094c44da98b954eedeac495271d0f", Decimals: 18},
+		},
+		OtherQuotes: []Token{
+			{Symbol: "WBTC", Address: "0x2260fac5e5542a773aa44fbcfedf7c193bc2c599", Decimals: 8},
+		},
+
+		RPCURL:     "https://ethereum-rpc.publicnode.com",
+		V2Router:   "0x7a250d5630b4cf539739df2c5dacb4c659f2488d", // Uniswap V2
+		V3Quoter:   "0x61ffe014ba17989e743c5f6cb21bf9697530b21e", // Uniswap V3
+		V3FeeTiers: []uint32{100, 500, 3000, 10000},
+		V2Factory:  "0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f",
+		V3Factory:  "0x1f98431c8ad98523631ae4a59f267346ea31f984",
+	}
+
+	Base = Chain{
+		Name:            "base",
+		ID:              8453,
+		DexScreenerSlug: "base",
+		WrappedNative:   Token{Symbol: "WETH", Address: "0x4200000000000000000000000000000000000006", Decimals: 18},
+		Stablecoins: []Token{
+			{Symbol: "USDC", Address: "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", Decimals: 6},
+			{Symbol: "USDbC", Address: "0xd9aaec86b65d86f6a7b5b1b0c42ffa531710b6ca", Decimals: 6},
+			{Symbol: "DAI", Address: "0x50c5725949a6f0c72e6c4a641f24049a917db0cb", Decimals: 18},
+		},
+
+		RPCURL:     "https://mainnet.base.org",
+		V2Router:   "0x4752ba5dbc23f44d87826276bf6fd6b1c372ad24", // Uniswap V2
+		V3Quoter:   "0x3d4e44eb1374240ce5f1b871ab261cd16335b76a", // Uniswap V3
+		V3FeeTiers: []uint32{100, 500, 3000, 10000},
+		V2Factory:  "0x8909dc15e40173ff4699343b6eb8132c65e18ec6",
+		V3Factory:  "0x33128a8fc17869897dce68ed026d694621f6fdfd",
+	}
+
+	Arbitrum = Chain{
+		Name:            "arbitrum",
+		ID:              42161,
+		DexScreenerSlug: "arbitrum",
+		WrappedNative:   Token{Symbol: "WETH", Address: "0x82af49447d8a07e3bd95bd0d56f35241523fbab1", Decimals: 18},
+		Stablecoins: []Token{
+			{Symbol: "USDC", Address: "0xaf88d065e77c8cc2239327c5edb3a432268e5831", Decimals: 6},
+			{Symbol: "USDC.e", Address: "0xff970a61a04b1ca14834a43f5de4533ebddb5cc8", Decimals: 6},
+			{Symbol: "USDT", Address: "0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9", Decimals: 6},
+			{Symbol: "DAI", Address: "0xda10009cbd5d07dd0cecc66161fc93d7c9000da1", Decimals: 18},
+		},
+
+		RPCURL:     "https://arb1.arbitrum.io/rpc",
+		V2Router:   "0x4752ba5dbc23f44d87826276bf6fd6b1c372ad24", // Uniswap V2
+		V3Quoter:   "0x61ffe014ba17989e743c5f6cb21bf9697530b21e", // Uniswap V3
+		V3FeeTiers: []uint32{100, 500, 3000, 10000},
+		V2Factory:  "0xf1d7cc64fb4452f05c498126312ebe29f30fbcf9",
+		V3Factory:  "0x1f98431c8ad98523631ae4a59f267346ea31f984",
+	}
+)
+
+// All lists the supported chains
+var All = []Chain{BSC, Ethereum, Base, Arbitrum}
+
+// aliases maps alternative names to canonical chain names
+var aliases = map[string]string{
+	"bnb":      "bsc",
+	"bnbchain": "bsc",
+	"eth":      "ethereum",
+	"mainnet":  "ethereum",
+	"arb":      "arbitrum",
+}
+
+// Lookup finds a chain by name, alias or numeric chain ID
+func Lookup(nameOrID string) (Chain, error) {
+	key := strings.ToLower(strings.TrimSpace(nameOrID))
+	if canonical, ok := aliases[key]; ok {
+		key = canonical
+	}
+	id, _ := strconv.ParseInt(key, 10, 64)
+
+	for _, c := range All {
+		if c.Name == key || (id != 0 && c.ID == id) {
+			return c, nil
+		}
+	}
+	return Chain{}, fmt.Errorf("unsupported chain %q (supported: %s)", nameOrID, strings.Join(Names(), ", "))
+}
+
+// Names returns the canonical names of all supported chains
+func Names() []string {
+	names := make([]string, len(All))
+	for i, c := range All {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// DefaultQuoteAssets is the wrapped native token followed by the stablecoins
+func (c Chain) DefaultQuoteAssets() []Token {
+	return append([]Token{c.WrappedNative}, c.Stablecoins...)
+}
+
+// KnownTokens returns every token that can be selected as a quote asset by symbol
+func (c Chain) KnownTokens() []Token {
+	return append(c.DefaultQuoteAssets(), c.OtherQuotes...)
+}
+
+// IDString returns the chain ID as used in provider query strings
+func (c Chain) IDString() string {
+	return strconv.FormatInt(c.ID, 10)
+}
@@ -0,0 +1,47 @@
+package chain
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := map[string]string{
+		"bsc":      "bsc",
+		"BNB":      "bsc",
+		"56":       "bsc",
+		"eth":      "ethereum",
+		"1":        "ethereum",
+		"Base":     "base",
+		"8453":     "base",
+		"arbitrum": "arbitrum",
+		"42161":    "arbitrum",
+	}
+	for input, want := range tests {
+		c, err := Lookup(input)
+		if err != nil || c.Name != want {
+			t.Errorf("Lookup(%q) = %q, %v; want %q", input, c.Name, err, want)
+		}
+	}
+
+	for _, bad := range []string{"", "solana", "137"} {
+		if _, err := Lookup(bad); err == nil {
+			t.Errorf("Lookup(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestChainTokens(t *testing.T) {
+	for _, c := range All {
+		seen := map[string]bool{}
+		for _, tok := range c.KnownTokens() {
+			if len(tok.Address) != 42 || tok.Address[:2] != "0x" {
+				t.Errorf("%s %s: malformed address %q", c.Name, tok.Symbol, tok.Address)
+			}
+			if seen[tok.Address] {
+				t.Errorf("%s: duplicate token %s", c.Name, tok.Address)
+			}
+			seen[tok.Address] = true
+		}
+		if c.DefaultQuoteAssets()[0] != c.WrappedNative {
+			t.Errorf("%s: default quotes must start with the wrapped native token", c.Name)
+		}
+	}
+}
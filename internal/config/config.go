@@ -1,60 +1,396 @@
-// Package config provides configuration curerntly for bscscan and dexscreener
+// Package config provides configuration for the screener: API keys, chain,
+// thresholds, weights, provider limits and the fraud policy. Values are
+// layered: built-in defaults, then a YAML config file, then a named profile,
+// then environment variables (and .env), then command-line flags.
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/discovery"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/fraud"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/liquidity"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/notify"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/scoring"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	// API Keys
-	BscScanAPIKey string
-	DatabaseURL   string
+	BscScanAPIKey string `yaml:"bscscan_api_key"`
+	DatabaseURL   string `yaml:"database_url"`
+	APIAddr       string `yaml:"api_addr"` // Listen address for the serve command
 
 	// Thresholds
-	MinLiquidityUSD             float64
-	MinVolume24h                float64
-	MaxTop10HolderConcentration float64
+	MinLiquidityUSD             float64 `yaml:"min_liquidity_usd"`
+	MinVolume24h                float64 `yaml:"min_volume_24h"`
+	MaxTop10HolderConcentration float64 `yaml:"max_top10_holder_concentration"`
+	MinPairAgeDays              float64 `yaml:"min_pair_age_days"`
 
-	// Scoring weights
-	LiquidityWeight     float64
-	VolumeWeight        float64
-	HolderWeight        float64
-	FragmentationWeight float64
+	// Scoring weights, summing to 1
+	LiquidityWeight     float64 `yaml:"liquidity_weight"`
+	VolumeWeight        float64 `yaml:"volume_weight"`
+	HolderWeight        float64 `yaml:"holder_weight"`
+	FragmentationWeight float64 `yaml:"fragmentation_weight"`
+	SlippageWeight      float64 `yaml:"slippage_weight"`   // Simulated slippage; 0 leaves it out of the composite
+	ContractWeight      float64 `yaml:"contract_weight"`   // Source analysis red flags
+	FraudRiskWeight     float64 `yaml:"fraud_risk_weight"` // 100 minus the fraud rules' risk score
 
 	// Score thresholds
-	FeaturedThreshold float64 // ADD THIS
-	VisibleThreshold  float64 // ADD THIS
+	FeaturedThreshold float64 `yaml:"featured_threshold"` // Minimum composite score for featured listing
+	VisibleThreshold  float64 `yaml:"visible_threshold"`  // Minimum composite score for visible listing
+
+	// Pipeline
+	Chain       string   `yaml:"chain"`        // Chain to screen on (name or chain ID, see package chain)
+	Workers     int      `yaml:"workers"`      // Number of tokens screened concurrently
+	QuoteAssets []string `yaml:"quote_assets"` // Quote assets whose pairs count towards liquidity ("WBNB" or "SYMBOL=0xaddress"); empty = chain defaults
+	RPCURL      string   `yaml:"rpc_url"`      // JSON-RPC endpoint for slippage simulation and on-chain holders; empty = chain's public RPC, RPCDisabled = skip
+
+	// Fraud rules
+	Fraud       fraud.Policy `yaml:"fraud"`        // Built-in rules with the config file and profile applied
+	FraudPolicy string       `yaml:"fraud_policy"` // YAML/JSON policy file applied on top of Fraud; empty = none
+
+	FraudThresholds map[string]float64 `yaml:"-"` // Rule thresholds from -fraud-threshold, applied last
+
+	// Liquidity history: drops over each window raise liquidity_drain or reject
+	LiquidityDrain liquidity.Policy `yaml:"liquidity_drain"`
+
+	// How often the watch command re-screens each listing tier
+	Watch WatchIntervals `yaml:"watch"`
+
+	// Alert sinks, routing rules and deduplication
+	Notify notify.Config `yaml:"notify"`
+
+	// How the discover command follows new pools and when it releases their tokens
+	Discovery discovery.Config `yaml:"discovery"`
+
+	// Provider response cache and its TTL per endpoint
+	Cache cache.Config `yaml:"cache"`
+
+	// Holder distribution
+	HolderSource  string `yaml:"holder_source"`   // HolderSourceAPI or HolderSourceOnChain
+	HolderTopN    int    `yaml:"holder_top_n"`    // Largest holders reported by the on-chain index
+	LogBlockRange int    `yaml:"log_block_range"` // Blocks per eth_getLogs request when indexing Transfer logs
+
+	// Provider base URLs (empty = production endpoints)
+	DexScreenerBaseURL string `yaml:"dexscreener_base_url"`
+	HoneypotBaseURL    string `yaml:"honeypot_base_url"`
+	GoPlusBaseURL      string `yaml:"goplus_base_url"`
+	EtherscanBaseURL   string `yaml:"etherscan_base_url"`
+
+	// Per-provider rate limits and retry policy, keyed by transport provider name
+	ProviderLimits map[string]transport.Limits `yaml:"provider_limits"`
+
+	// Where the values came from, for PrintEffective
+	ConfigFile string `yaml:"-"`
+	Profile    string `yaml:"-"`
 }
 
-func Load() *Config {
-	_ = godotenv.Load()
+// RPCDisabled as RPCURL turns off the on-chain checks that need a node
+const RPCDisabled = "off"
+
+// Holder distribution sources
+const (
+	HolderSourceAPI     = "api"     // Honeypot.is top holders, indexed on-chain when it has none
+	HolderSourceOnChain = "onchain" // Replay Transfer logs over RPC, falling back to the API
+)
+
+// Providers lists the transport providers that have limits
+var Providers = []string{
+	transport.ProviderDexScreener,
+	transport.ProviderHoneypot,
+	transport.ProviderGoPlus,
+	transport.ProviderEtherscan,
+	transport.ProviderRPC,
+	transport.ProviderNotify,
+}
+
+// WatchIntervals are the delays before a watched token is screened again,
+// by the state its last screening left it in. Featured tokens are checked
+// most often since a bad listing there costs the most.
+type WatchIntervals struct {
+	Featured time.Duration `yaml:"featured"`
+	Visible  time.Duration `yaml:"visible"`
+	Hidden   time.Duration `yaml:"hidden"` // Also rejected tokens
+	Error    time.Duration `yaml:"error"`  // Retry after a provider error
+}
+
+// MarshalYAML writes durations as "15m0s" rather than nanoseconds
+func (w WatchIntervals) MarshalYAML() (any, error) {
+	return struct {
+		Featured string `yaml:"featured"`
+		Visible  string `yaml:"visible"`
+		Hidden   string `yaml:"hidden"`
+		Error    string `yaml:"error"`
+	}{w.Featured.String(), w.Visible.String(), w.Hidden.String(), w.Error.String()}, nil
+}
+
+// Options selects the config file and profile. Empty fields fall back to
+// the CONFIG_FILE and PROFILE environment variables.
+type Options struct {
+	File    string
+	Profile string
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	limits := make(map[string]transport.Limits, len(Providers))
+	for _, provider := range Providers {
+		limits[provider] = transport.DefaultLimits(provider)
+	}
 
 	return &Config{
-		BscScanAPIKey: os.Getenv("BSCSCAN_API_KEY"),
-		DatabaseURL:   os.Getenv("DATABASE_URL"),
+		APIAddr: ":8080",
 
-		MinLiquidityUSD:             getEnvFloat("MIN_LIQUIDITY_USD", 100000),
-		MinVolume24h:                getEnvFloat("MIN_VOLUME_24H", 10000),
-		MaxTop10HolderConcentration: getEnvFloat("MAX_TOP10_HOLDERS", 90),
+		MinLiquidityUSD:             100000,
+		MinVolume24h:                10000,
+		MaxTop10HolderConcentration: 90,
+		MinPairAgeDays:              7,
 
-		LiquidityWeight:     0.35,
-		VolumeWeight:        0.30,
-		HolderWeight:        0.25,
+		LiquidityWeight:     0.25,
+		VolumeWeight:        0.20,
+		HolderWeight:        0.20,
 		FragmentationWeight: 0.10,
+		SlippageWeight:      0.10,
+		ContractWeight:      0.10,
+		FraudRiskWeight:     0.05,
+
+		FeaturedThreshold: 70,
+		VisibleThreshold:  50,
+
+		Chain:   "bsc",
+		Workers: 8,
+
+		Fraud: fraud.DefaultPolicy(),
+
+		LiquidityDrain: liquidity.DefaultPolicy(),
+
+		Watch: WatchIntervals{
+			Featured: 15 * time.Minute,
+			Visible:  time.Hour,
+			Hidden:   6 * time.Hour,
+			Error:    10 * time.Minute,
+		},
+
+		Notify: notify.DefaultConfig(),
+
+		Discovery: discovery.DefaultConfig(),
+
+		Cache: cache.DefaultConfig(),
+
+		HolderSource:  HolderSourceAPI,
+		HolderTopN:    10,
+		LogBlockRange: 5000,
+
+		ProviderLimits: limits,
+	}
+}
+
+// Load builds the configuration from the defaults, the config file and
+// profile in opts, and the environment. It does not validate: call
+// Validate once flags have been applied.
+func Load(opts Options) (*Config, error) {
+	_ = godotenv.Load()
+
+	if opts.File == "" {
+		opts.File = os.Getenv("CONFIG_FILE")
+	}
+	if opts.Profile == "" {
+		opts.Profile = os.Getenv("PROFILE")
+	}
+
+	cfg := Default()
+	if err := cfg.applyFile(opts.File, opts.Profile); err != nil {
+		return nil, err
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// FraudRules returns the fraud policy with the FraudPolicy file and then
+// FraudThresholds applied
+func (c *Config) FraudRules() (fraud.Policy, error) {
+	p, err := c.Fraud.OverlayFile(c.FraudPolicy)
+	if err != nil {
+		return fraud.Policy{}, err
+	}
+	return p.WithThresholds(c.FraudThresholds)
+}
+
+// Scorer returns a scorer with the configured weights and hard filters
+func (c *Config) Scorer() *scoring.Scorer {
+	return scoring.NewScorer(
+		scoring.Weights{
+			Liquidity:     c.LiquidityWeight,
+			Volume:        c.VolumeWeight,
+			Holder:        c.HolderWeight,
+			Fragmentation: c.FragmentationWeight,
+			Slippage:      c.SlippageWeight,
+			Contract:      c.ContractWeight,
+			FraudRisk:     c.FraudRiskWeight,
+		},
+		scoring.Thresholds{
+			MinLiquidityUSD:             c.MinLiquidityUSD,
+			MinVolume24h:                c.MinVolume24h,
+			MaxTop10HolderConcentration: c.MaxTop10HolderConcentration,
+			MinPairAgeDays:              c.MinPairAgeDays,
+		},
+	)
+}
+
+// applyEnv overrides values with the environment variables that are set.
+// Unparsable values are errors rather than zeros.
+func (c *Config) applyEnv() error {
+	env := envReader{}
+
+	env.string("BSCSCAN_API_KEY", &c.BscScanAPIKey)
+	env.string("DATABASE_URL", &c.DatabaseURL)
+	env.string("API_ADDR", &c.APIAddr)
+
+	env.float("MIN_LIQUIDITY_USD", &c.MinLiquidityUSD)
+	env.float("MIN_VOLUME_24H", &c.MinVolume24h)
+	env.float("MAX_TOP10_HOLDERS", &c.MaxTop10HolderConcentration)
+	env.float("MIN_PAIR_AGE_DAYS", &c.MinPairAgeDays)
+
+	env.float("LIQUIDITY_WEIGHT", &c.LiquidityWeight)
+	env.float("VOLUME_WEIGHT", &c.VolumeWeight)
+	env.float("HOLDER_WEIGHT", &c.HolderWeight)
+	env.float("FRAGMENTATION_WEIGHT", &c.FragmentationWeight)
+	env.float("SLIPPAGE_WEIGHT", &c.SlippageWeight)
+	env.float("CONTRACT_WEIGHT", &c.ContractWeight)
+	env.float("FRAUD_RISK_WEIGHT", &c.FraudRiskWeight)
+
+	env.float("FEATURED_THRESHOLD", &c.FeaturedThreshold)
+	env.float("VISIBLE_THRESHOLD", &c.VisibleThreshold)
+
+	env.string("CHAIN", &c.Chain)
+	env.int("SCREENER_WORKERS", &c.Workers)
+	env.list("QUOTE_ASSETS", &c.QuoteAssets)
+	env.string("RPC_URL", &c.RPCURL)
+
+	env.string("FRAUD_POLICY", &c.FraudPolicy)
+
+	env.string("HOLDER_SOURCE", &c.HolderSource)
+	env.int("HOLDER_TOP_N", &c.HolderTopN)
+	env.int("LOG_BLOCK_RANGE", &c.LogBlockRange)
+
+	env.duration("WATCH_FEATURED_INTERVAL", &c.Watch.Featured)
+	env.duration("WATCH_VISIBLE_INTERVAL", &c.Watch.Visible)
+	env.duration("WATCH_HIDDEN_INTERVAL", &c.Watch.Hidden)
+	env.duration("WATCH_ERROR_INTERVAL", &c.Watch.Error)
+
+	env.duration("DISCOVERY_COOLDOWN", &c.Discovery.Cooldown)
+	env.duration("DISCOVERY_POLL_INTERVAL", &c.Discovery.PollInterval)
+
+	env.string("CACHE_FILE", &c.Cache.Path)
+
+	env.string("DEXSCREENER_BASE_URL", &c.DexScreenerBaseURL)
+	env.string("HONEYPOT_BASE_URL", &c.HoneypotBaseURL)
+	env.string("GOPLUS_BASE_URL", &c.GoPlusBaseURL)
+	env.string("ETHERSCAN_BASE_URL", &c.EtherscanBaseURL)
+
+	// <PROVIDER>_RPS, <PROVIDER>_BURST and HTTP_MAX_RETRIES
+	for _, provider := range Providers {
+		l := c.ProviderLimits[provider]
+		prefix := strings.ToUpper(provider)
+		env.float(prefix+"_RPS", &l.RequestsPerSecond)
+		env.int(prefix+"_BURST", &l.Burst)
+		env.int("HTTP_MAX_RETRIES", &l.MaxRetries)
+		env.duration(prefix+"_TIMEOUT", &l.Timeout)
+		c.ProviderLimits[provider] = l
+	}
+
+	return env.err()
+}
 
-		FeaturedThreshold: 70.0, // ADD THIS
-		VisibleThreshold:  50.0, // ADD THIS
+// envReader sets values from environment variables that are present and
+// collects parse errors
+type envReader struct {
+	errs []string
+}
+
+func (e *envReader) lookup(key string) (string, bool) {
+	val, ok := os.LookupEnv(key)
+	val = strings.TrimSpace(val)
+	return val, ok && val != ""
+}
+
+func (e *envReader) fail(key, val string, err error) {
+	e.errs = append(e.errs, fmt.Sprintf("%s=%q: %v", key, val, err))
+}
+
+func (e *envReader) string(key string, dst *string) {
+	if val, ok := e.lookup(key); ok {
+		*dst = val
+	}
+}
+
+// list splits a comma-separated variable
+func (e *envReader) list(key string, dst *[]string) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func (e *envReader) float(key string, dst *float64) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		e.fail(key, val, err)
+		return
+	}
+	*dst = f
+}
+
+func (e *envReader) int(key string, dst *int) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		e.fail(key, val, err)
+		return
+	}
+	*dst = i
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		e.fail(key, val, err)
+		return
 	}
+	*dst = d
 }
 
-func getEnvFloat(key string, defaultVal float64) float64 {
-	val := os.Getenv(key)
-	if val == "" {
-		return defaultVal
+func (e *envReader) err() error {
+	if len(e.errs) == 0 {
+		return nil
 	}
-	f, _ := strconv.ParseFloat(val, 64)
-	return f
+	return fmt.Errorf("invalid environment: %s", strings.Join(e.errs, "; "))
 }
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profiles are named sets of overrides, written like a config file. A config
+// file may add its own under "profiles:" or extend these.
+var Profiles = map[string]string{
+	// Established tokens only: deep, old, well-distributed, low tax
+	"conservative": `
+min_liquidity_usd: 250000
+min_volume_24h: 50000
+max_top10_holder_concentration: 60
+min_pair_age_days: 30
+featured_threshold: 80
+visible_threshold: 60
+fraud:
+  min_holder_sample: 50
+  rules:
+    - name: excessive_tax
+      threshold: 10
+    - name: high_tax
+      threshold: 5
+    - name: moderate_fail_rate
+      threshold: 0.02
+    - name: high_creator_holdings
+      threshold: 0.10
+`,
+	// Early, thin tokens: volume counts more than depth and only outright
+	// honeypots are rejected
+	"degen": `
+min_liquidity_usd: 10000
+min_volume_24h: 1000
+max_top10_holder_concentration: 97
+min_pair_age_days: 1
+liquidity_weight: 0.15
+volume_weight: 0.40
+holder_weight: 0.15
+fragmentation_weight: 0.10
+slippage_weight: 0.05
+contract_weight: 0.05
+fraud_risk_weight: 0.10
+featured_threshold: 60
+visible_threshold: 40
+fraud:
+  rules:
+    - name: excessive_tax
+      threshold: 25
+    - name: high_tax
+      threshold: 15
+`,
+	// Manual listing review: on-chain holders and simulated slippage in the score
+	"listing-review": `
+min_liquidity_usd: 50000
+min_volume_24h: 5000
+holder_source: onchain
+liquidity_weight: 0.25
+volume_weight: 0.20
+holder_weight: 0.15
+fragmentation_weight: 0.05
+slippage_weight: 0.15
+contract_weight: 0.10
+fraud_risk_weight: 0.10
+featured_threshold: 75
+visible_threshold: 50
+`,
+}
+
+// ProfileNames lists the built-in profiles
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyFile applies the config file at path (if any) and then profile (if
+// any), looked up in the file's profiles and the built-in ones
+func (c *Config) applyFile(path, profile string) error {
+	fileProfiles := map[string]*yaml.Node{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading config file: %w", err)
+		}
+		root, err := parseMapping(data)
+		if err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+		if root != nil {
+			if err := c.applyNode(root, fileProfiles); err != nil {
+				return fmt.Errorf("config file %s: %w", path, err)
+			}
+		}
+		c.ConfigFile = path
+	}
+
+	if profile == "" {
+		return nil
+	}
+	builtin, isBuiltin := Profiles[profile]
+	custom, isCustom := fileProfiles[profile]
+	if !isBuiltin && !isCustom {
+		names := ProfileNames()
+		for name := range fileProfiles {
+			names = append(names, name)
+		}
+		return fmt.Errorf("unknown profile %q (available: %s)", profile, strings.Join(names, ", "))
+	}
+	if isBuiltin {
+		root, err := parseMapping([]byte(builtin))
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+		if err := c.applyNode(root, nil); err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+	if isCustom {
+		if err := c.applyNode(custom, nil); err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+	c.Profile = profile
+	return nil
+}
+
+// parseMapping parses a YAML document whose root is a mapping; an empty
+// document returns nil
+func parseMapping(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of settings", root.Line)
+	}
+	return root, nil
+}
+
+// applyNode overrides the settings present in a mapping node. Profiles are
+// collected into profiles; a nil map means they are not allowed here.
+func (c *Config) applyNode(node *yaml.Node, profiles map[string]*yaml.Node) error {
+	known := yamlKeys(reflect.TypeOf(*c))
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		switch key.Value {
+		case "profiles":
+			if profiles == nil {
+				return fmt.Errorf("line %d: profiles cannot be nested", key.Line)
+			}
+			if value.Kind != yaml.MappingNode {
+				return fmt.Errorf("line %d: profiles must map names to settings", key.Line)
+			}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				if value.Content[j+1].Kind != yaml.MappingNode {
+					return fmt.Errorf("line %d: profile %s must be a mapping of settings", value.Content[j].Line, value.Content[j].Value)
+				}
+				profiles[value.Content[j].Value] = value.Content[j+1]
+			}
+
+		case "fraud":
+			data, err := yaml.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if c.Fraud, err = c.Fraud.Overlay(data); err != nil {
+				return fmt.Errorf("line %d: fraud: %w", key.Line, err)
+			}
+
+		case "provider_limits":
+			if err := c.applyLimits(value); err != nil {
+				return err
+			}
+
+		default:
+			if !known[key.Value] {
+				return fmt.Errorf("line %d: unknown setting %q", key.Line, key.Value)
+			}
+			// Decoding a one-entry mapping leaves every other field as it is
+			single := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, value}}
+			if err := single.Decode(c); err != nil {
+				return fmt.Errorf("line %d: %s: %w", key.Line, key.Value, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyLimits overrides the limits of the providers listed in node, keeping
+// the fields that are not set
+func (c *Config) applyLimits(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: provider_limits must map providers to limits", node.Line)
+	}
+	known := yamlKeys(reflect.TypeOf(transport.Limits{}))
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		l, ok := c.ProviderLimits[key.Value]
+		if !ok {
+			return fmt.Errorf("line %d: unknown provider %q (known: %s)", key.Line, key.Value, strings.Join(Providers, ", "))
+		}
+		if value.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: %s limits must be a mapping", key.Line, key.Value)
+		}
+		for j := 0; j < len(value.Content); j += 2 {
+			if field := value.Content[j]; !known[field.Value] {
+				return fmt.Errorf("line %d: unknown %s limit %q", field.Line, key.Value, field.Value)
+			}
+		}
+		if err := value.Decode(&l); err != nil {
+			return fmt.Errorf("line %d: %s limits: %w", key.Line, key.Value, err)
+		}
+		c.ProviderLimits[key.Value] = l
+	}
+	return nil
+}
+
+// yamlKeys returns the yaml field names of a struct type
+func yamlKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// weightTolerance absorbs float rounding when checking that weights sum to 1
+const weightTolerance = 1e-6
+
+// Validate checks that every setting is usable and reports all problems at once
+func (c *Config) Validate() error {
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if c.MinLiquidityUSD < 0 {
+		fail("min_liquidity_usd %g is negative", c.MinLiquidityUSD)
+	}
+	if c.MinVolume24h < 0 {
+		fail("min_volume_24h %g is negative", c.MinVolume24h)
+	}
+	if c.MaxTop10HolderConcentration <= 0 || c.MaxTop10HolderConcentration > 100 {
+		fail("max_top10_holder_concentration %g is outside (0, 100]", c.MaxTop10HolderConcentration)
+	}
+	if c.MinPairAgeDays < 0 {
+		fail("min_pair_age_days %g is negative", c.MinPairAgeDays)
+	}
+
+	weights := []struct {
+		name  string
+		value float64
+	}{
+		{"liquidity_weight", c.LiquidityWeight},
+		{"volume_weight", c.VolumeWeight},
+		{"holder_weight", c.HolderWeight},
+		{"fragmentation_weight", c.FragmentationWeight},
+		{"slippage_weight", c.SlippageWeight},
+		{"contract_weight", c.ContractWeight},
+		{"fraud_risk_weight", c.FraudRiskWeight},
+	}
+	var sum float64
+	for _, w := range weights {
+		if w.value < 0 || w.value > 1 {
+			fail("%s %g is outside [0, 1]", w.name, w.value)
+		}
+		sum += w.value
+	}
+	if math.Abs(sum-1) > weightTolerance {
+		fail("scoring weights sum to %g, want 1", sum)
+	}
+
+	if c.FeaturedThreshold < 0 || c.FeaturedThreshold > 100 {
+		fail("featured_threshold %g is outside [0, 100]", c.FeaturedThreshold)
+	}
+	if c.VisibleThreshold < 0 || c.VisibleThreshold > 100 {
+		fail("visible_threshold %g is outside [0, 100]", c.VisibleThreshold)
+	}
+	if c.VisibleThreshold > c.FeaturedThreshold {
+		fail("visible_threshold %g is above featured_threshold %g", c.VisibleThreshold, c.FeaturedThreshold)
+	}
+
+	if _, err := chain.Lookup(c.Chain); err != nil {
+		fail("chain: %v", err)
+	}
+	if c.Workers < 1 {
+		fail("workers %d must be at least 1", c.Workers)
+	}
+	if c.RPCURL != "" && c.RPCURL != RPCDisabled {
+		if u, err := url.Parse(c.RPCURL); err != nil || u.Scheme == "" || u.Host == "" {
+			fail("rpc_url %q is not a URL or %q", c.RPCURL, RPCDisabled)
+		}
+	}
+
+	if err := c.Fraud.Validate(); err != nil {
+		fail("fraud: %v", err)
+	}
+
+	if err := c.LiquidityDrain.Validate(); err != nil {
+		fail("liquidity_drain: %v", err)
+	}
+
+	for _, w := range []struct {
+		name     string
+		interval time.Duration
+	}{
+		{"featured", c.Watch.Featured},
+		{"visible", c.Watch.Visible},
+		{"hidden", c.Watch.Hidden},
+		{"error", c.Watch.Error},
+	} {
+		if w.interval <= 0 {
+			fail("watch.%s interval must be positive", w.name)
+		}
+	}
+
+	if err := c.Notify.Validate(); err != nil {
+		fail("notify: %v", err)
+	}
+
+	if err := c.Discovery.Validate(); err != nil {
+		fail("discovery: %v", err)
+	}
+
+	if err := c.Cache.Validate(); err != nil {
+		fail("cache: %v", err)
+	}
+
+	if c.HolderSource != HolderSourceAPI && c.HolderSource != HolderSourceOnChain {
+		fail("holder_source %q is not %q or %q", c.HolderSource, HolderSourceAPI, HolderSourceOnChain)
+	}
+	if c.HolderTopN < 1 {
+		fail("holder_top_n %d must be at least 1", c.HolderTopN)
+	}
+	if c.LogBlockRange < 1 {
+		fail("log_block_range %d must be at least 1", c.LogBlockRange)
+	}
+
+	for _, provider := range Providers {
+		l, ok := c.ProviderLimits[provider]
+		if !ok {
+			fail("provider_limits: %s missing", provider)
+			continue
+		}
+		if l.RequestsPerSecond < 0 {
+			fail("provider_limits.%s.requests_per_second %g is negative", provider, l.RequestsPerSecond)
+		}
+		if l.RequestsPerSecond > 0 && l.Burst < 1 {
+			fail("provider_limits.%s.burst %d must be at least 1 when rate limited", provider, l.Burst)
+		}
+		if l.MaxRetries < 0 {
+			fail("provider_limits.%s.max_retries %d is negative", provider, l.MaxRetries)
+		}
+		if l.BaseBackoff < 0 || l.MaxBackoff < l.BaseBackoff {
+			fail("provider_limits.%s: backoff %s-%s is not a valid range", provider, l.BaseBackoff, l.MaxBackoff)
+		}
+		if l.Timeout <= 0 {
+			fail("provider_limits.%s.timeout must be positive", provider)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New("invalid config:\n  " + strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// PrintEffective writes the configuration in config file format, with
+// secrets masked
+func (c *Config) PrintEffective(w io.Writer) error {
+	source := "defaults"
+	if c.ConfigFile != "" {
+		source = c.ConfigFile
+	}
+	profile := c.Profile
+	if profile == "" {
+		profile = "none"
+	}
+	fmt.Fprintf(w, "# Effective config (file: %s, profile: %s)\n", source, profile)
+
+	masked := *c
+	masked.BscScanAPIKey = mask(c.BscScanAPIKey)
+	masked.Notify = c.Notify.Masked()
+	if u, err := url.Parse(c.DatabaseURL); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "****")
+			masked.DatabaseURL = u.String()
+		}
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(masked); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// mask keeps the last four characters of a secret
+func mask(secret string) string {
+	if len(secret) <= 4 {
+		return strings.Repeat("*", len(secret))
+	}
+	return "****" + secret[len(secret)-4:]
+}
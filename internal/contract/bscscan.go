@@ -2,29 +2,43 @@
 package contract
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
 )
 
 type BscScanClient struct {
+	chain      chain.Chain
 	apikey     string
 	baseURL    string
-	httpClient *http.Client
+	httpClient *transport.Client
+	cache      *cache.Cache
+}
+
+// ContractSource is one entry of a getsourcecode response
+type ContractSource struct {
+	SourceCode     string `json:"SourceCode"`
+	ABI            string `json:"ABI"`
+	ContractName   string `json:"ContractName"`
+	Proxy          string `json:"Proxy"`
+	Implementation string `json:"Implementation"`
+}
+
+// IsVerified reports whether the source and ABI were published
+func (s *ContractSource) IsVerified() bool {
+	return s.SourceCode != "" && s.ABI != "" && s.ABI != "Contract source code not verified"
 }
 
 type ContractSourceResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Result  []struct {
-		SourceCode   string `json:"SourceCode"`
-		ABI          string `json:"ABI"`
-		ContractName string `json:"ContractName"`
-		Proxy        string `json:"Proxy"`
-	} `json:"result"`
+	Status  string           `json:"status"`
+	Message string           `json:"message"`
+	Result  []ContractSource `json:"result"`
 }
 
 type TokenCreationResponse struct {
@@ -49,49 +63,102 @@ type TokenTotalSupplyResponse struct {
 	Result string `json:"result"`
 }
 
-func NewBscScanClient(apiKey string) *BscScanClient {
+// NewBscScanClient creates an Etherscan v2 client for contracts on ch. A nil
+// httpClient uses the shared default transport.
+func NewBscScanClient(ch chain.Chain, apiKey string, httpClient *transport.Client) *BscScanClient {
+	if httpClient == nil {
+		httpClient = transport.Default.For(transport.ProviderEtherscan)
+	}
 	return &BscScanClient{
+		chain:      ch,
 		apikey:     apiKey,
 		baseURL:    "https://api.etherscan.io/v2/api",
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		httpClient: httpClient,
 	}
 }
 
-// IsContractVerified checks if the contract has source code and ABI and proxy is not set
-func (c *BscScanClient) IsContractVerified(contractAddress string) (bool, error) {
-	url := fmt.Sprintf("%s?chainid=56&module=contract&action=getsourcecode&address=%s&apikey=%s",
-		c.baseURL, contractAddress, c.apikey)
+// WithBaseURL points the client at a different API host (e.g. a test server)
+func (c *BscScanClient) WithBaseURL(baseURL string) *BscScanClient {
+	if baseURL != "" {
+		c.baseURL = baseURL
+	}
+	return c
+}
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		fmt.Println("Error fetching contract source:", err)
-		return false, err
+// WithCache serves source, creation and supply lookups from rc while they are fresh
+func (c *BscScanClient) WithCache(rc *cache.Cache) *BscScanClient {
+	c.cache = rc
+	return c
+}
+
+// get fetches url through the cache under endpoint. Error envelopes (bad
+// key, rate limit, no data) are returned but not kept.
+func (c *BscScanClient) get(ctx context.Context, endpoint, address, url string) ([]byte, error) {
+	key := cache.Key{Provider: transport.ProviderEtherscan, BaseURL: c.baseURL, Endpoint: endpoint, Chain: c.chain.ID, Address: address}
+	return c.cache.Get(ctx, c.httpClient, key, url, func(body []byte) cache.Retention {
+		var resp struct {
+			Status string `json:"status"`
+		}
+		if json.Unmarshal(body, &resp) != nil || resp.Status != "1" {
+			return cache.Discard
+		}
+		switch endpoint {
+		case cache.SourceCode:
+			return sourceRetention(body)
+		case cache.ContractCreation:
+			// A contract is deployed once; its creation record never changes
+			return cache.Forever
+		}
+		return cache.Expire
+	})
+}
+
+// sourceRetention keeps verified source for good unless the contract is a
+// proxy, whose implementation can be upgraded. Unverified source may be
+// verified later, so it expires like everything else.
+func sourceRetention(body []byte) cache.Retention {
+	var resp ContractSourceResponse
+	if json.Unmarshal(body, &resp) != nil || len(resp.Result) == 0 {
+		return cache.Expire
+	}
+	s := resp.Result[0]
+	if !s.IsVerified() || s.Proxy == "1" || s.Implementation != "" {
+		return cache.Expire
 	}
+	return cache.Forever
+}
 
-	defer resp.Body.Close()
+// GetSourceCode fetches the verified source, ABI and proxy details of a contract
+func (c *BscScanClient) GetSourceCode(ctx context.Context, contractAddress string) (*ContractSource, error) {
+	url := fmt.Sprintf("%s?chainid=%d&module=contract&action=getsourcecode&address=%s&apikey=%s",
+		c.baseURL, c.chain.ID, contractAddress, c.apikey)
 
-	body, _ := io.ReadAll(resp.Body)
+	body, err := c.get(ctx, cache.SourceCode, contractAddress, url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching contract source: %w", err)
+	}
 
 	// First check if it's an error response
 	var errResp APIErrorResponse
 	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Status == "0" {
-		return false, fmt.Errorf("API error: %s", errResp.Result)
+		return nil, fmt.Errorf("API error: %s", errResp.Result)
 	}
 
 	var result ContractSourceResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		fmt.Println("Error unmarshaling response:", err)
-		return false, err
+		return nil, fmt.Errorf("decoding contract source: %w", err)
 	}
 
-	return result.Status == "1" && len(result.Result) > 0 && result.Result[0].SourceCode != "" && result.Result[0].ABI != "" && result.Result[0].Proxy == "0", nil
+	if result.Status != "1" || len(result.Result) == 0 {
+		return &ContractSource{}, nil
+	}
+	return &result.Result[0], nil
 }
 
 // IscontractOldEnough checks if the contract is older than 7 days
-func (c *BscScanClient) IsContractOldEnough(contractAddress string) (bool, error) {
-	deplodAt, err := c.GetContractAge(contractAddress)
+func (c *BscScanClient) IsContractOldEnough(ctx context.Context, contractAddress string) (bool, error) {
+	deplodAt, err := c.GetContractAge(ctx, contractAddress)
 	if err != nil {
-		fmt.Println("Error calling GetContractAge funtion:", err)
 		return false, err
 	}
 
@@ -104,21 +171,15 @@ func (c *BscScanClient) IsContractOldEnough(contractAddress string) (bool, error
 	return true, nil
 }
 
-func (c *BscScanClient) GetContractAge(contractAddress string) (time.Time, error) {
-	url := fmt.Sprintf("%s?chainid=56&module=contract&action=getcontractcreation&contractaddresses=%s&apikey=%s",
-		c.baseURL, contractAddress, c.apikey)
+func (c *BscScanClient) GetContractAge(ctx context.Context, contractAddress string) (time.Time, error) {
+	url := fmt.Sprintf("%s?chainid=%d&module=contract&action=getcontractcreation&contractaddresses=%s&apikey=%s",
+		c.baseURL, c.chain.ID, contractAddress, c.apikey)
 
-	resp, err := c.httpClient.Get(url)
+	body, err := c.get(ctx, cache.ContractCreation, contractAddress, url)
 	if err != nil {
-		fmt.Println("Error fetching contract creation:", err)
-		return time.Time{}, err
+		return time.Time{}, fmt.Errorf("fetching contract creation: %w", err)
 	}
 
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	fmt.Println("GetContractAge raw response:", string(body))
-
 	// First check if it's an error response
 	var errResp APIErrorResponse
 	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Status == "0" {
@@ -127,8 +188,7 @@ func (c *BscScanClient) GetContractAge(contractAddress string) (time.Time, error
 
 	var result TokenCreationResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		fmt.Println("Error unmarshaling TokenCreationResponse response:", err)
-		return time.Time{}, err
+		return time.Time{}, fmt.Errorf("decoding contract creation: %w", err)
 	}
 
 	if len(result.Result) == 0 {
@@ -137,8 +197,7 @@ func (c *BscScanClient) GetContractAge(contractAddress string) (time.Time, error
 
 	timestamp, err := strconv.ParseInt(result.Result[0].TimeStamp, 10, 64)
 	if err != nil {
-		fmt.Println("Error parsing timestamp:", err)
-		return time.Time{}, err
+		return time.Time{}, fmt.Errorf("parsing creation timestamp: %w", err)
 	}
 
 	deployTime := time.Unix(timestamp, 0)
@@ -146,24 +205,44 @@ func (c *BscScanClient) GetContractAge(contractAddress string) (time.Time, error
 	return deployTime, nil
 }
 
-func (c *BscScanClient) GetTotalSupply(contractAddress string) (float64, error) {
-	url := fmt.Sprintf("%s?chainid=56&module=stats&action=tokensupply&contractaddress=%s&apikey=%s",
-		c.baseURL, contractAddress, c.apikey)
+// GetCreationBlock returns the block the contract was deployed in
+func (c *BscScanClient) GetCreationBlock(ctx context.Context, contractAddress string) (uint64, error) {
+	url := fmt.Sprintf("%s?chainid=%d&module=contract&action=getcontractcreation&contractaddresses=%s&apikey=%s",
+		c.baseURL, c.chain.ID, contractAddress, c.apikey)
 
-	resp, err := c.httpClient.Get(url)
+	body, err := c.get(ctx, cache.ContractCreation, contractAddress, url)
 	if err != nil {
-		fmt.Println("Error fetching token holders:", err)
 		return 0, err
 	}
 
-	defer resp.Body.Close()
+	var errResp APIErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Status == "0" {
+		return 0, fmt.Errorf("API error: %s", errResp.Message)
+	}
 
-	body, _ := io.ReadAll(resp.Body)
+	var result TokenCreationResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if len(result.Result) == 0 {
+		return 0, fmt.Errorf("no creation record for %s", contractAddress)
+	}
+
+	return strconv.ParseUint(result.Result[0].BlockNumber, 10, 64)
+}
+
+func (c *BscScanClient) GetTotalSupply(ctx context.Context, contractAddress string) (float64, error) {
+	url := fmt.Sprintf("%s?chainid=%d&module=stats&action=tokensupply&contractaddress=%s&apikey=%s",
+		c.baseURL, c.chain.ID, contractAddress, c.apikey)
+
+	body, err := c.get(ctx, cache.TokenSupply, contractAddress, url)
+	if err != nil {
+		return 0, fmt.Errorf("fetching token supply: %w", err)
+	}
 
 	var result TokenTotalSupplyResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		fmt.Println("Error unmarshaling TokenTotalSupplyResponse response:", err)
-		return 0, err
+		return 0, fmt.Errorf("decoding token supply: %w", err)
 	}
 
 	if len(result.Result) == 0 {
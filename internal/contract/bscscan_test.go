@@ -0,0 +1,72 @@
+package contract_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+)
+
+func TestSourceCodeCacheRetention(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rc := cache.New(map[string]time.Duration{cache.SourceCode: time.Hour}).
+		WithClock(func() time.Time { return now })
+	client := newTestClient(srv).WithCache(rc)
+
+	addresses := []string{
+		"0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82", // CAKE: verified, not a proxy
+		"0x39702843a6733932ec7ce0dde404e5a6dbd8c989", // AVAIL: a proxy
+		"0x0000000000000000000000000000000000000bad", // not verified
+	}
+	for range 2 {
+		for _, a := range addresses {
+			if _, err := client.GetSourceCode(context.Background(), a); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if got := srv.Hits("etherscan/getsourcecode"); got != 3 {
+		t.Fatalf("getsourcecode called %d times within the TTL, want 3", got)
+	}
+
+	// Only the verified, non-proxy source outlives the TTL
+	now = now.Add(2 * time.Hour)
+	for _, a := range addresses {
+		client.GetSourceCode(context.Background(), a)
+	}
+	if got := srv.Hits("etherscan/getsourcecode"); got != 5 {
+		t.Errorf("getsourcecode called %d times after the TTL, want 5", got)
+	}
+}
+
+func TestContractCreationCachedForever(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rc := cache.New(cache.DefaultConfig().TTL).WithClock(func() time.Time { return now })
+	client := newTestClient(srv).WithCache(rc)
+
+	const (
+		cake = "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"
+		doge = "0xba2ae424d960c26247dd6c32edc70b295c744c43" // no creation record
+	)
+	for range 2 {
+		if _, err := client.GetCreationBlock(context.Background(), cake); err != nil {
+			t.Fatal(err)
+		}
+		client.GetCreationBlock(context.Background(), doge)
+		now = now.Add(365 * 24 * time.Hour)
+	}
+	if _, err := client.GetContractAge(context.Background(), cake); err != nil {
+		t.Fatal(err)
+	}
+
+	// CAKE's record is fetched once; the missing record is asked for again
+	if got := srv.Hits("etherscan/getcontractcreation"); got != 3 {
+		t.Errorf("getcontractcreation called %d times, want 3", got)
+	}
+}
@@ -0,0 +1,246 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// EIP-1967 storage slots (keccak256("eip1967.proxy.<name>") - 1)
+const (
+	ImplementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	AdminSlot          = "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"
+	BeaconSlot         = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+)
+
+// Function selectors used to follow beacons and identify upgraders
+const (
+	selectorImplementation = "0x5c60da1b" // implementation()
+	selectorOwner          = "0x8da5cb5b" // owner()
+)
+
+// Where a proxy's implementation address came from
+const (
+	ResolvedBySlot      = "eip1967"   // EIP-1967 implementation slot
+	ResolvedByBeacon    = "beacon"    // EIP-1967 beacon slot + implementation()
+	ResolvedByEtherscan = "etherscan" // getsourcecode Implementation field, when no slot is set
+)
+
+// ProxyInfo describes an upgradeable proxy and who can upgrade it
+type ProxyInfo struct {
+	Implementation string `json:"implementation"`
+	ResolvedBy     string `json:"resolved_by"`
+	Admin          string `json:"admin,omitempty"`       // EIP-1967 admin (often a ProxyAdmin contract)
+	Beacon         string `json:"beacon,omitempty"`      // EIP-1967 beacon, for beacon proxies
+	AdminOwner     string `json:"admin_owner,omitempty"` // owner() of the admin contract
+	Upgrader       string `json:"upgrader,omitempty"`    // Account that can ultimately upgrade the proxy
+
+	ImplementationName     string `json:"implementation_name,omitempty"`
+	ImplementationVerified bool   `json:"implementation_verified"`
+}
+
+// ZeroAddress is returned by storage slots and calls that hold no address
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
+// ProxyRPCResponse is the JSON-RPC envelope returned by the Etherscan proxy module
+type ProxyRPCResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// RPCError is a JSON-RPC error from the node, such as a reverted call
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+// A proxy forwards unknown calls from its fallback function with an
+// assembly delegatecall(gas(), ...). Tokens that only use a library's
+// target.delegatecall(data) do not match.
+var (
+	fallbackFunction = regexp.MustCompile(`\bfallback\s*\(\s*\)|\bfunction\s*\(\s*\)`)
+	assemblyDelegate = regexp.MustCompile(`\bdelegatecall\s*\(\s*gas\b`)
+)
+
+// LooksLikeProxy reports whether a contract might delegate to an
+// implementation. Etherscan only sets Proxy for contracts someone has marked
+// as proxies, so sources with a delegating fallback are worth a storage-slot
+// check too.
+func (s *ContractSource) LooksLikeProxy() bool {
+	if s.Proxy == "1" || s.Implementation != "" {
+		return true
+	}
+	return fallbackFunction.MatchString(s.SourceCode) && assemblyDelegate.MatchString(s.SourceCode)
+}
+
+// GetStorageAt reads a 32-byte storage slot through the Etherscan proxy module
+func (c *BscScanClient) GetStorageAt(ctx context.Context, contractAddress, slot string) (string, error) {
+	url := fmt.Sprintf("%s?chainid=%d&module=proxy&action=eth_getStorageAt&address=%s&position=%s&tag=latest&apikey=%s",
+		c.baseURL, c.chain.ID, contractAddress, slot, c.apikey)
+	return c.proxyCall(ctx, url)
+}
+
+// Call performs a read-only eth_call through the Etherscan proxy module
+func (c *BscScanClient) Call(ctx context.Context, to, data string) (string, error) {
+	url := fmt.Sprintf("%s?chainid=%d&module=proxy&action=eth_call&to=%s&data=%s&tag=latest&apikey=%s",
+		c.baseURL, c.chain.ID, to, data, c.apikey)
+	return c.proxyCall(ctx, url)
+}
+
+func (c *BscScanClient) proxyCall(ctx context.Context, url string) (string, error) {
+	resp, err := c.httpClient.Get(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading proxy response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("proxy call: HTTP %d", resp.StatusCode)
+	}
+
+	// API-level errors (bad key, rate limit) use the regular envelope
+	var errResp APIErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Status == "0" {
+		return "", fmt.Errorf("API error: %s", errResp.Result)
+	}
+
+	var result ProxyRPCResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", &RPCError{Code: result.Error.Code, Message: result.Error.Message}
+	}
+
+	return result.Result, nil
+}
+
+// ResolveProxy finds the implementation behind a proxy and the accounts
+// that can upgrade it. It returns nil when source is not a proxy. The live
+// EIP-1967 slots come first: Etherscan's Implementation field is only
+// refreshed when someone re-verifies the proxy, so it can lag an upgrade.
+func (c *BscScanClient) ResolveProxy(ctx context.Context, contractAddress string, source *ContractSource) (*ProxyInfo, error) {
+	if !source.LooksLikeProxy() {
+		return nil, nil
+	}
+
+	info := &ProxyInfo{}
+
+	admin, err := c.readAddressSlot(ctx, contractAddress, AdminSlot)
+	if err != nil {
+		return nil, fmt.Errorf("reading admin slot: %w", err)
+	}
+	info.Admin = admin
+
+	impl, err := c.readAddressSlot(ctx, contractAddress, ImplementationSlot)
+	if err != nil {
+		return nil, fmt.Errorf("reading implementation slot: %w", err)
+	}
+	info.Implementation = impl
+	info.ResolvedBy = ResolvedBySlot
+
+	if impl == "" {
+		beacon, err := c.readAddressSlot(ctx, contractAddress, BeaconSlot)
+		if err != nil {
+			return nil, fmt.Errorf("reading beacon slot: %w", err)
+		}
+		if beacon != "" {
+			ret, err := c.Call(ctx, beacon, selectorImplementation)
+			if err != nil {
+				return nil, fmt.Errorf("calling beacon implementation(): %w", err)
+			}
+			info.Beacon = beacon
+			info.Implementation = wordToAddress(ret)
+			info.ResolvedBy = ResolvedByBeacon
+		}
+	}
+
+	// Non-standard proxies keep their implementation elsewhere; Etherscan
+	// may still know it
+	if info.Implementation == "" && source.Implementation != "" {
+		info.Implementation = strings.ToLower(source.Implementation)
+		info.ResolvedBy = ResolvedByEtherscan
+	}
+
+	if info.Implementation == "" {
+		// Marked as a proxy (or has a delegating fallback) but no standard slot is set
+		if source.Proxy == "1" {
+			return nil, fmt.Errorf("proxy implementation not found")
+		}
+		return nil, nil
+	}
+
+	// The upgrader is the owner of the ProxyAdmin/beacon if there is one,
+	// otherwise the admin itself, otherwise (UUPS) the proxy's own owner
+	switch {
+	case info.Beacon != "":
+		info.Upgrader, err = c.ownerOf(ctx, info.Beacon)
+	case info.Admin != "":
+		info.AdminOwner, err = c.ownerOf(ctx, info.Admin)
+		info.Upgrader = info.Admin
+		if info.AdminOwner != "" {
+			info.Upgrader = info.AdminOwner
+		}
+	default:
+		info.Upgrader, err = c.ownerOf(ctx, contractAddress)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("calling owner(): %w", err)
+	}
+
+	return info, nil
+}
+
+// readAddressSlot reads an address-valued slot, returning "" when unset
+func (c *BscScanClient) readAddressSlot(ctx context.Context, contractAddress, slot string) (string, error) {
+	word, err := c.GetStorageAt(ctx, contractAddress, slot)
+	if err != nil {
+		return "", err
+	}
+	return wordToAddress(word), nil
+}
+
+// ownerOf calls owner() on a contract, returning "" if it has none. A
+// contract without owner() reverts; any other failure is an error.
+func (c *BscScanClient) ownerOf(ctx context.Context, contractAddress string) (string, error) {
+	ret, err := c.Call(ctx, contractAddress, selectorOwner)
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return wordToAddress(ret), nil
+}
+
+// wordToAddress extracts the low 20 bytes of a 32-byte hex word. Empty,
+// malformed and zero words yield "".
+func wordToAddress(word string) string {
+	word = strings.TrimPrefix(strings.ToLower(word), "0x")
+	if len(word) < 40 {
+		return ""
+	}
+	addr := "0x" + word[len(word)-40:]
+	if addr == ZeroAddress {
+		return ""
+	}
+	return addr
+}
@@ -0,0 +1,191 @@
+package contract_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/contract"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// newTestClient talks to the fixture server without rate limiting
+func newTestClient(srv *mockapi.Server) *contract.BscScanClient {
+	httpClient := transport.NewClient(transport.ProviderEtherscan, transport.Limits{Timeout: 5 * time.Second})
+	return contract.NewBscScanClient(chain.BSC, "test", httpClient).WithBaseURL(srv.EtherscanURL())
+}
+
+func TestResolveProxy(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	tests := []struct {
+		name           string
+		address        string
+		implementation string
+		resolvedBy     string
+		upgrader       string
+	}{
+		// Recorded AVAIL responses: no storage fixture, so Etherscan's implementation is used
+		{"etherscan", "0x39702843a6733932ec7ce0dde404e5a6dbd8c989", "0x37f7359b9b033e3aed775d7d53e340c3e94e7129", contract.ResolvedByEtherscan, ""},
+		// AVL: marked as a proxy without an implementation, so the EIP-1967 slots are read
+		{"eip1967", "0x9beee89723ceec27d7c2834bec6834208ffdc202", "0x00000000000000000000000000000000000000a1", contract.ResolvedBySlot, "0x00000000000000000000000000000000000000a3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := client.GetSourceCode(context.Background(), tt.address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := client.ResolveProxy(context.Background(), tt.address, source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info == nil {
+				t.Fatal("proxy not detected")
+			}
+			if info.Implementation != tt.implementation || info.ResolvedBy != tt.resolvedBy {
+				t.Errorf("implementation = %s via %s, want %s via %s",
+					info.Implementation, info.ResolvedBy, tt.implementation, tt.resolvedBy)
+			}
+			if info.Upgrader != tt.upgrader {
+				t.Errorf("upgrader = %q, want %q", info.Upgrader, tt.upgrader)
+			}
+
+			impl, err := client.GetSourceCode(context.Background(), info.Implementation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !impl.IsVerified() {
+				t.Errorf("implementation %s not verified", info.Implementation)
+			}
+		})
+	}
+
+	// A plain token is not a proxy and costs no storage reads
+	cake := "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"
+	source, err := client.GetSourceCode(context.Background(), cake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := client.ResolveProxy(context.Background(), cake, source); info != nil || err != nil {
+		t.Errorf("CAKE resolved as proxy: %+v, %v", info, err)
+	}
+}
+
+func TestResolveProxyMissingImplementation(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	// Marked as a proxy, but every slot is empty
+	source := &contract.ContractSource{SourceCode: "contract P {}", ABI: "[]", Proxy: "1"}
+	if _, err := client.ResolveProxy(context.Background(), "0x0000000000000000000000000000000000000bad", source); err == nil {
+		t.Error("expected an error for a proxy without an implementation")
+	}
+}
+
+func TestResolveProxyPrefersLiveSlot(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	// Etherscan still reports the implementation from before an upgrade
+	source := &contract.ContractSource{SourceCode: "contract P {}", ABI: "[]", Proxy: "1",
+		Implementation: "0x00000000000000000000000000000000000000ee"}
+	info, err := client.ResolveProxy(context.Background(), "0x9beee89723ceec27d7c2834bec6834208ffdc202", source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Implementation != "0x00000000000000000000000000000000000000a1" || info.ResolvedBy != contract.ResolvedBySlot {
+		t.Errorf("implementation = %s via %s, want the slot's", info.Implementation, info.ResolvedBy)
+	}
+}
+
+func TestResolveProxyReportsFailedCalls(t *testing.T) {
+	// The implementation slot is set; the admin slot and owner() answer as configured
+	const implWord = "0x00000000000000000000000000000000000000000000000000000000000000a1"
+	tests := []struct {
+		name  string
+		slots int    // HTTP status of storage reads
+		owner string // eth_call response body, or "" for HTTP 403
+		err   string // Expected error, "" for none
+	}{
+		{"storage read rejected", http.StatusForbidden, "", "reading admin slot: proxy call: HTTP 403"},
+		{"owner() reverts", http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`, ""},
+		{"owner() rejected", http.StatusOK, "", "calling owner(): proxy call: HTTP 403"},
+		{"owner() rate limited", http.StatusOK, `{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`, "calling owner(): API error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				switch {
+				case q.Get("action") == "eth_getStorageAt" && tt.slots != http.StatusOK:
+					w.WriteHeader(tt.slots)
+				case q.Get("action") == "eth_getStorageAt" && q.Get("position") == contract.ImplementationSlot:
+					fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"%s"}`, implWord)
+				case q.Get("action") == "eth_getStorageAt":
+					fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%064x"}`, 0)
+				case tt.owner == "":
+					w.WriteHeader(http.StatusForbidden)
+				default:
+					fmt.Fprint(w, tt.owner)
+				}
+			}))
+			defer srv.Close()
+			httpClient := transport.NewClient(transport.ProviderEtherscan, transport.Limits{Timeout: 5 * time.Second})
+			client := contract.NewBscScanClient(chain.BSC, "test", httpClient).WithBaseURL(srv.URL)
+
+			source := &contract.ContractSource{SourceCode: "contract P {}", ABI: "[]", Proxy: "1"}
+			info, err := client.ResolveProxy(context.Background(), "0x0000000000000000000000000000000000000bad", source)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatal(err)
+			case tt.err == "" && (info == nil || info.Upgrader != ""):
+				t.Errorf("info = %+v, want a proxy without an upgrader", info)
+			case tt.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.err)):
+				t.Errorf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLooksLikeProxy(t *testing.T) {
+	tests := []struct {
+		name   string
+		source contract.ContractSource
+		want   bool
+	}{
+		{"marked by etherscan", contract.ContractSource{Proxy: "1"}, true},
+		{"implementation known", contract.ContractSource{Implementation: "0x00000000000000000000000000000000000000a1"}, true},
+		{"delegating fallback", contract.ContractSource{SourceCode: `
+			fallback() external payable virtual { _fallback(); }
+			function _delegate(address implementation) internal virtual {
+				assembly { let result := delegatecall(gas(), implementation, 0, calldatasize(), 0, 0) }
+			}`}, true},
+		{"pre-0.6 fallback", contract.ContractSource{SourceCode: `
+			function () payable external {
+				assembly { let result := delegatecall(gas, impl, ptr, calldatasize, 0, 0) }
+			}`}, true},
+		{"library delegatecall", contract.ContractSource{SourceCode: `
+			receive() external payable {}
+			function functionDelegateCall(address target, bytes memory data) internal returns (bytes memory) {
+				(bool success, bytes memory returndata) = target.delegatecall(data);
+			}`}, false},
+		{"delegatecall in a comment", contract.ContractSource{SourceCode: "// never uses delegatecall\ncontract Token {}"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.source.LooksLikeProxy(); got != tt.want {
+			t.Errorf("%s: LooksLikeProxy = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
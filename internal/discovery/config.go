@@ -0,0 +1,52 @@
+package discovery
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Config configures the discovery feed
+type Config struct {
+	Cooldown      time.Duration `yaml:"cooldown"`      // Wait after a pool is created before its token is screened
+	PollInterval  time.Duration `yaml:"poll_interval"` // How often new blocks are scanned
+	Confirmations uint64        `yaml:"confirmations"` // Blocks kept behind the head, so reorged pools are not picked up
+	StartBlocks   uint64        `yaml:"start_blocks"`  // How far behind the head the first scan starts
+}
+
+// MarshalYAML writes durations as "30m0s" rather than nanoseconds
+func (c Config) MarshalYAML() (any, error) {
+	return struct {
+		Cooldown      string `yaml:"cooldown"`
+		PollInterval  string `yaml:"poll_interval"`
+		Confirmations uint64 `yaml:"confirmations"`
+		StartBlocks   uint64 `yaml:"start_blocks"`
+	}{c.Cooldown.String(), c.PollInterval.String(), c.Confirmations, c.StartBlocks}, nil
+}
+
+// DefaultConfig releases a token 30 minutes after its first quote pool
+// appears, scanning every 15s and starting 1200 blocks back (an hour of
+// 3s blocks)
+func DefaultConfig() Config {
+	return Config{
+		Cooldown:      30 * time.Minute,
+		PollInterval:  15 * time.Second,
+		Confirmations: 3,
+		StartBlocks:   1200,
+	}
+}
+
+// Validate checks the intervals
+func (c Config) Validate() error {
+	var errs []string
+	if c.Cooldown < 0 {
+		errs = append(errs, "cooldown must not be negative")
+	}
+	if c.PollInterval <= 0 {
+		errs = append(errs, "poll_interval must be positive")
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package discovery_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/discovery"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+const (
+	newt      = "0x00000000000000000000000000000000000000f1" // WBNB pair, then a USDT pair
+	v3Token   = "0x00000000000000000000000000000000000000f5" // USDT V3 pool
+	firstPool = 0x29ffc00
+)
+
+var at = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newScanner(t *testing.T) *discovery.Scanner {
+	t.Helper()
+	srv := mockapi.New()
+	t.Cleanup(srv.Close)
+
+	httpClient := transport.NewClient(transport.ProviderRPC, transport.Limits{Timeout: 5 * time.Second})
+	return discovery.NewScanner(chain.BSC, rpc.NewClient(srv.RPCURL(), httpClient), chain.BSC.DefaultQuoteAssets()).
+		WithClock(func() time.Time { return at })
+}
+
+func TestScanExtractsNonQuoteToken(t *testing.T) {
+	// Wider than the mock node accepts, so the scanner has to shrink its pages
+	s := newScanner(t).WithBlockRange(4 * mockapi.MaxLogRange)
+
+	pairs, skipped, err := s.Scan(context.Background(), mockapi.LatestBlock-20_000, mockapi.LatestBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The USDT/WBNB and token/token pairs are skipped, the reorged one dropped
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want 2", skipped)
+	}
+	want := []discovery.Pair{
+		{Token: newt, Quote: "WBNB", Pool: "0x00000000000000000000000000000000000000a1", Version: discovery.VersionV2, Block: firstPool},
+		{Token: v3Token, Quote: "USDT", Pool: "0x00000000000000000000000000000000000000b1", Version: discovery.VersionV3, Block: firstPool + 0x08},
+		{Token: newt, Quote: "USDT", Pool: "0x00000000000000000000000000000000000000a4", Version: discovery.VersionV2, Block: firstPool + 0x30},
+	}
+	if len(pairs) != len(want) {
+		t.Fatalf("got %d pairs, want %d: %+v", len(pairs), len(want), pairs)
+	}
+	for i, w := range want {
+		p := pairs[i]
+		if p.Token != w.Token || p.Quote != w.Quote || p.Pool != w.Pool || p.Version != w.Version || p.Block != w.Block {
+			t.Errorf("pair %d = %+v, want %+v", i, p, w)
+		}
+		if p.Chain != "bsc" || p.SeenAt != at || !strings.HasPrefix(p.TxHash, "0x") {
+			t.Errorf("pair %d = %+v", i, p)
+		}
+	}
+
+	if got := s.Symbol(context.Background(), newt); got != "NEWT" {
+		t.Errorf("Symbol = %q, want NEWT", got)
+	}
+	if got := s.Symbol(context.Background(), v3Token); got != "" {
+		t.Errorf("Symbol of a token without symbol() = %q", got)
+	}
+}
+
+func TestFeedReleasesTokensAfterCooldown(t *testing.T) {
+	now := at
+	cfg := discovery.DefaultConfig()
+	cfg.Cooldown = 10 * time.Minute
+	feed := discovery.NewFeed(newScanner(t), cfg).
+		WithClock(func() time.Time { return now }).
+		StartAt(firstPool)
+
+	queued, ready, err := feed.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The token's second pool does not queue it again
+	if len(queued) != 2 || queued[0].Token != newt || queued[1].Token != v3Token {
+		t.Errorf("queued %+v, want %s and %s", queued, newt, v3Token)
+	}
+	if len(ready) != 0 || feed.Queue().Len() != 2 {
+		t.Errorf("released %d and kept %d before the cooldown", len(ready), feed.Queue().Len())
+	}
+
+	now = now.Add(10 * time.Minute)
+	queued, ready, err = feed.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 0 {
+		t.Errorf("rescanned blocks queued %+v", queued)
+	}
+	if len(ready) != 2 || ready[0].Symbol != "NEWT" || ready[1].Token != v3Token {
+		t.Errorf("ready = %+v", ready)
+	}
+	if want := at.Add(10 * time.Minute); ready[0].ReadyAt != want {
+		t.Errorf("ReadyAt = %s, want %s", ready[0].ReadyAt, want)
+	}
+	if feed.Queue().Len() != 0 {
+		t.Errorf("%d tokens left in the queue", feed.Queue().Len())
+	}
+}
+
+func TestQueueSkipsKnownTokens(t *testing.T) {
+	q := discovery.NewQueue(time.Minute)
+	q.MarkSeen("0x" + strings.ToUpper(newt[2:]))
+
+	if q.Add(discovery.Pair{Token: newt, SeenAt: at}) {
+		t.Error("token already in the list was queued")
+	}
+	if !q.Add(discovery.Pair{Token: v3Token, SeenAt: at}) {
+		t.Fatal("new token was not queued")
+	}
+	if ready := q.Ready(at.Add(59 * time.Second)); len(ready) != 0 {
+		t.Errorf("released before the cooldown: %+v", ready)
+	}
+	if ready := q.Ready(at.Add(time.Minute)); len(ready) != 1 {
+		t.Errorf("released %+v, want %s", ready, v3Token)
+	}
+	// Released tokens are not queued again by later pools
+	if q.Add(discovery.Pair{Token: v3Token, SeenAt: at.Add(time.Hour)}) {
+		t.Error("released token was queued again")
+	}
+}
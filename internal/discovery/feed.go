@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Feed scans new blocks on a timer and releases tokens from the queue
+type Feed struct {
+	scanner *Scanner
+	queue   *Queue
+	cfg     Config
+	now     func() time.Time
+
+	next uint64 // First block of the next scan; 0 until the first poll
+}
+
+func NewFeed(scanner *Scanner, cfg Config) *Feed {
+	return &Feed{
+		scanner: scanner,
+		queue:   NewQueue(cfg.Cooldown),
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// WithClock replaces time.Now, for tests
+func (f *Feed) WithClock(now func() time.Time) *Feed {
+	f.now = now
+	return f
+}
+
+// StartAt makes the first scan begin at block instead of StartBlocks
+// behind the head
+func (f *Feed) StartAt(block uint64) *Feed {
+	f.next = block
+	return f
+}
+
+// Queue returns the cooldown queue, e.g. to mark known tokens as seen
+func (f *Feed) Queue() *Queue {
+	return f.queue
+}
+
+// Poll scans the blocks confirmed since the last poll, queues new tokens
+// and returns the ones that became ready. Symbols are looked up for ready
+// tokens only.
+func (f *Feed) Poll(ctx context.Context) (queued []Pair, ready []Candidate, err error) {
+	latest, err := f.scanner.LatestBlock(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("latest block: %w", err)
+	}
+	head := latest - min(latest, f.cfg.Confirmations)
+	if f.next == 0 {
+		f.next = head - min(head, f.cfg.StartBlocks)
+	}
+
+	if f.next <= head {
+		pairs, _, err := f.scanner.Scan(ctx, f.next, head)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.next = head + 1
+		for _, p := range pairs {
+			if f.queue.Add(p) {
+				queued = append(queued, p)
+			}
+		}
+	}
+
+	ready = f.queue.Ready(f.now())
+	for i := range ready {
+		ready[i].Symbol = f.scanner.Symbol(ctx, ready[i].Token)
+	}
+	return queued, ready, nil
+}
+
+// Run polls every PollInterval until ctx is cancelled, passing newly
+// queued pools to onQueued and released tokens to onReady. Either may be
+// nil. Poll errors go to onError, if non-nil, and the next poll retries
+// the same blocks.
+func (f *Feed) Run(ctx context.Context, onQueued func(Pair), onReady func([]Candidate), onError func(error)) error {
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		queued, ready, err := f.Poll(ctx)
+		if err != nil && onError != nil {
+			onError(err)
+		}
+		if onQueued != nil {
+			for _, p := range queued {
+				onQueued(p)
+			}
+		}
+		if onReady != nil && len(ready) > 0 {
+			onReady(ready)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package discovery
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Candidate is a discovered token waiting out its cooldown
+type Candidate struct {
+	Pair
+	ReadyAt time.Time `json:"ready_at"`
+}
+
+// Queue holds discovered tokens until their cooldown has passed. A token
+// is queued once, for its first pool; later pools of the same token and
+// tokens already released are ignored.
+type Queue struct {
+	cooldown time.Duration
+
+	mu      sync.Mutex
+	pending map[string]Candidate // Keyed by lowercase token address
+	seen    map[string]bool      // Queued or released
+}
+
+// NewQueue creates an empty queue releasing tokens cooldown after they
+// were first seen
+func NewQueue(cooldown time.Duration) *Queue {
+	return &Queue{
+		cooldown: cooldown,
+		pending:  make(map[string]Candidate),
+		seen:     make(map[string]bool),
+	}
+}
+
+// MarkSeen stops tokens from being queued, e.g. ones already in the token list
+func (q *Queue) MarkSeen(tokens ...string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, token := range tokens {
+		q.seen[strings.ToLower(token)] = true
+	}
+}
+
+// Add queues p's token, ready a cooldown after p.SeenAt. It reports whether
+// the token was new.
+func (q *Queue) Add(p Pair) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	token := strings.ToLower(p.Token)
+	if q.seen[token] {
+		return false
+	}
+	q.seen[token] = true
+	q.pending[token] = Candidate{Pair: p, ReadyAt: p.SeenAt.Add(q.cooldown)}
+	return true
+}
+
+// Ready removes and returns the tokens whose cooldown has passed by now, in
+// discovery order
+func (q *Queue) Ready(now time.Time) []Candidate {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var ready []Candidate
+	for token, c := range q.pending {
+		if !c.ReadyAt.After(now) {
+			ready = append(ready, c)
+			delete(q.pending, token)
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].Block != ready[j].Block {
+			return ready[i].Block < ready[j].Block
+		}
+		return ready[i].Token < ready[j].Token
+	})
+	return ready
+}
+
+// Len is the number of tokens waiting out their cooldown
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
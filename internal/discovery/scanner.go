@@ -0,0 +1,241 @@
+// Package discovery builds the token list from new liquidity pools instead
+// of a hand-curated file (plan.txt: the pipeline starts with a feed of new
+// pairs). It reads PairCreated and PoolCreated logs of the chain's V2 and V3
+// factories over JSON-RPC, keeps the side of each pool that is not a quote
+// asset, and holds it in a queue until a cooldown has passed, so liquidity
+// and trading have time to appear before the token is screened.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+)
+
+// Factory event topics
+const (
+	// PairCreatedTopic is keccak256("PairCreated(address,address,address,uint256)")
+	PairCreatedTopic = "0x0d3648bd0f6ba80134a33ba9275ac585d9d315f0ad8355cddefde31afa28d0e9"
+	// PoolCreatedTopic is keccak256("PoolCreated(address,address,uint24,int24,address)")
+	PoolCreatedTopic = "0x783cca1c0412dd0d695e784568c96da2e9c22ff989357a2e8b1d9b2b4e6b7118"
+)
+
+// DEX versions of a discovered pool
+const (
+	VersionV2 = "v2"
+	VersionV3 = "v3"
+)
+
+// DefaultBlockRange is the eth_getLogs page size
+const DefaultBlockRange = 5000
+
+// selectorSymbol is symbol()
+const selectorSymbol = "0x95d89b41"
+
+// Pair is a newly created pool of a token against a quote asset
+type Pair struct {
+	Chain   string    `json:"chain"`
+	Token   string    `json:"token"` // Lowercase address of the non-quote side
+	Symbol  string    `json:"symbol"`
+	Quote   string    `json:"quote"` // Quote asset symbol
+	Pool    string    `json:"pool"`  // Lowercase pair or pool address
+	Version string    `json:"version"`
+	Block   uint64    `json:"block"`
+	TxHash  string    `json:"tx_hash"`
+	SeenAt  time.Time `json:"seen_at"`
+}
+
+// Scanner reads new pools from factory logs
+type Scanner struct {
+	chain      chain.Chain
+	client     *rpc.Client
+	quotes     map[string]string // Lowercase address -> symbol
+	v2, v3     []string          // Factory addresses
+	blockRange uint64
+	now        func() time.Time
+}
+
+// NewScanner creates a scanner over ch's factories. Pools are kept when
+// exactly one side is one of quotes.
+func NewScanner(ch chain.Chain, client *rpc.Client, quotes []chain.Token) *Scanner {
+	s := &Scanner{
+		chain:      ch,
+		client:     client,
+		quotes:     make(map[string]string, len(quotes)),
+		blockRange: DefaultBlockRange,
+		now:        time.Now,
+	}
+	for _, q := range quotes {
+		s.quotes[strings.ToLower(q.Address)] = q.Symbol
+	}
+	if ch.V2Factory != "" {
+		s.v2 = []string{ch.V2Factory}
+	}
+	if ch.V3Factory != "" {
+		s.v3 = []string{ch.V3Factory}
+	}
+	return s
+}
+
+// WithFactories replaces the chain's factories, e.g. to add another DEX
+// with the same events
+func (s *Scanner) WithFactories(v2, v3 []string) *Scanner {
+	s.v2, s.v3 = v2, v3
+	return s
+}
+
+// WithBlockRange sets the initial eth_getLogs page size in blocks
+func (s *Scanner) WithBlockRange(blocks uint64) *Scanner {
+	if blocks > 0 {
+		s.blockRange = blocks
+	}
+	return s
+}
+
+// WithClock replaces time.Now, for tests
+func (s *Scanner) WithClock(now func() time.Time) *Scanner {
+	s.now = now
+	return s
+}
+
+// LatestBlock returns the chain head
+func (s *Scanner) LatestBlock(ctx context.Context) (uint64, error) {
+	return s.client.BlockNumber(ctx)
+}
+
+// Scan returns the quote-asset pools created from fromBlock to toBlock, in
+// block order. Pools of two quote assets or of two other tokens are
+// skipped; skipped is how many.
+func (s *Scanner) Scan(ctx context.Context, fromBlock, toBlock uint64) (pairs []Pair, skipped int, err error) {
+	if len(s.v2) == 0 && len(s.v3) == 0 {
+		return nil, 0, fmt.Errorf("no factories configured for %s", s.chain.Name)
+	}
+
+	pageSize := s.blockRange
+	for from := fromBlock; from <= toBlock; {
+		to := min(from+pageSize-1, toBlock)
+
+		logs, err := s.factoryLogs(ctx, from, to)
+		if err != nil {
+			// Nodes reject ranges (or result sets) that are too large: halve and retry
+			var rpcErr *rpc.Error
+			if errors.As(err, &rpcErr) && pageSize > 1 {
+				pageSize /= 2
+				continue
+			}
+			return nil, 0, fmt.Errorf("eth_getLogs %d-%d: %w", from, to, err)
+		}
+
+		for _, l := range logs {
+			if l.Removed {
+				continue
+			}
+			p, ok := s.decode(l)
+			if !ok {
+				skipped++
+				continue
+			}
+			pairs = append(pairs, p)
+		}
+		from = to + 1
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Block < pairs[j].Block })
+	return pairs, skipped, nil
+}
+
+// factoryLogs returns the PairCreated logs of the V2 factories and the
+// PoolCreated logs of the V3 factories in one block range
+func (s *Scanner) factoryLogs(ctx context.Context, from, to uint64) ([]rpc.Log, error) {
+	var logs []rpc.Log
+	for _, source := range []struct {
+		factories []string
+		topic     string
+	}{
+		{s.v2, PairCreatedTopic},
+		{s.v3, PoolCreatedTopic},
+	} {
+		if len(source.factories) == 0 {
+			continue
+		}
+		page, err := s.client.GetLogs(ctx, rpc.LogFilter{
+			FromBlock: from,
+			ToBlock:   to,
+			Addresses: source.factories,
+			Topics:    []string{source.topic},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, page...)
+	}
+	return logs, nil
+}
+
+// decode extracts the non-quote token of a PairCreated or PoolCreated log
+func (s *Scanner) decode(l rpc.Log) (Pair, bool) {
+	if len(l.Topics) < 3 {
+		return Pair{}, false
+	}
+	words, err := rpc.Words(l.Data)
+	if err != nil {
+		return Pair{}, false
+	}
+
+	var version, pool string
+	switch {
+	case strings.EqualFold(l.Topics[0], PairCreatedTopic) && len(words) >= 1:
+		version, pool = VersionV2, rpc.DecodeAddress(words[0]) // pair, allPairsLength
+	case strings.EqualFold(l.Topics[0], PoolCreatedTopic) && len(words) >= 2:
+		version, pool = VersionV3, rpc.DecodeAddress(words[1]) // tickSpacing, pool
+	default:
+		return Pair{}, false
+	}
+
+	token0, token1 := rpc.DecodeAddress(l.Topics[1]), rpc.DecodeAddress(l.Topics[2])
+	quote0, isQuote0 := s.quotes[token0]
+	quote1, isQuote1 := s.quotes[token1]
+	if isQuote0 == isQuote1 {
+		return Pair{}, false
+	}
+	token, quote := token1, quote0
+	if isQuote1 {
+		token, quote = token0, quote1
+	}
+
+	block, _ := rpc.ParseQuantity(l.BlockNumber)
+	return Pair{
+		Chain:   s.chain.Name,
+		Token:   token,
+		Quote:   quote,
+		Pool:    pool,
+		Version: version,
+		Block:   block,
+		TxHash:  l.TransactionHash,
+		SeenAt:  s.now(),
+	}, true
+}
+
+// Symbol reads a token's symbol(); tokens without one get ""
+func (s *Scanner) Symbol(ctx context.Context, token string) string {
+	data, err := s.client.EthCall(ctx, token, selectorSymbol)
+	if err != nil {
+		return ""
+	}
+	symbol, err := rpc.DecodeString(data)
+	if err != nil {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(symbol))
+}
@@ -0,0 +1,288 @@
+// Package evaluation measures screener detection quality against a labelled
+// dataset: precision, recall, confusion matrix and per-rule attribution
+package evaluation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+)
+
+// Labels used in datasets and predictions. A rejected token is predicted scam.
+const (
+	LabelScam  = "scam"
+	LabelLegit = "legit"
+	LabelError = "error" // Prediction only: the pipeline could not decide
+)
+
+// Sample is one labelled token
+type Sample struct {
+	Address string `json:"address"`
+	Symbol  string `json:"symbol"`
+	Label   string `json:"label"`
+	Note    string `json:"note,omitempty"`
+}
+
+// LoadDataset reads a JSON array of samples or a CSV with
+// address,label[,symbol] columns (an optional header row is skipped)
+func LoadDataset(path string) ([]Sample, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []Sample
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &samples); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	} else {
+		r := csv.NewReader(bytes.NewReader(trimmed))
+		r.FieldsPerRecord = -1
+		r.Comment = '#'
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		for i, rec := range records {
+			if i == 0 && len(rec) > 0 && strings.EqualFold(strings.TrimSpace(rec[0]), "address") {
+				continue
+			}
+			if len(rec) < 2 {
+				return nil, fmt.Errorf("%s:%d: want address,label[,symbol]", path, i+1)
+			}
+			s := Sample{Address: strings.TrimSpace(rec[0]), Label: strings.TrimSpace(rec[1])}
+			if len(rec) > 2 {
+				s.Symbol = strings.TrimSpace(rec[2])
+			}
+			samples = append(samples, s)
+		}
+	}
+
+	for i := range samples {
+		samples[i].Label = strings.ToLower(samples[i].Label)
+		if !models.IsAddress(samples[i].Address) {
+			return nil, fmt.Errorf("%s: sample %d: invalid address %q", path, i+1, samples[i].Address)
+		}
+		if samples[i].Label != LabelScam && samples[i].Label != LabelLegit {
+			return nil, fmt.Errorf("%s: sample %d: label must be %q or %q, got %q",
+				path, i+1, LabelScam, LabelLegit, samples[i].Label)
+		}
+	}
+	return samples, nil
+}
+
+// TokenInfos converts samples into pipeline input
+func TokenInfos(samples []Sample) []models.BasicTokenInfo {
+	tokens := make([]models.BasicTokenInfo, len(samples))
+	for i, s := range samples {
+		tokens[i] = models.BasicTokenInfo{Address: s.Address, Symbol: s.Symbol, Decimals: 18}
+	}
+	return tokens
+}
+
+// Outcome pairs a sample with the screener's verdict
+type Outcome struct {
+	Sample    Sample `json:"sample"`
+	Predicted string `json:"predicted"`
+	Rule      string `json:"rule,omitempty"` // Rule that rejected the token
+	Reason    string `json:"reason,omitempty"`
+}
+
+// RuleStats counts how often a rule rejected scams (TP) and legit tokens (FP)
+type RuleStats struct {
+	Rule           string `json:"rule"`
+	TruePositives  int    `json:"true_positives"`
+	FalsePositives int    `json:"false_positives"`
+}
+
+// Report is the evaluation result
+type Report struct {
+	Total          int `json:"total"`
+	Errors         int `json:"errors"` // Excluded from the metrics below
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	TrueNegatives  int `json:"true_negatives"`
+	FalseNegatives int `json:"false_negatives"`
+
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Accuracy  float64 `json:"accuracy"`
+
+	Rules    []RuleStats `json:"rules"`
+	Outcomes []Outcome   `json:"outcomes"`
+}
+
+// Evaluate compares results (in sample order) against the labels
+func Evaluate(samples []Sample, results []pipeline.TokenResult) Report {
+	report := Report{Total: len(samples)}
+	rules := map[string]*RuleStats{}
+
+	for i, sample := range samples {
+		r := results[i]
+		outcome := Outcome{Sample: sample}
+
+		switch r.Status {
+		case pipeline.StatusError:
+			outcome.Predicted = LabelError
+			outcome.Reason = r.ErrorReason
+			report.Errors++
+			report.Outcomes = append(report.Outcomes, outcome)
+			continue
+		case pipeline.StatusFailed:
+			outcome.Predicted = LabelScam
+			outcome.Rule = RuleOf(r)
+			if len(r.FailureReasons) > 0 {
+				outcome.Reason = r.FailureReasons[0]
+			}
+		default:
+			outcome.Predicted = LabelLegit
+		}
+		report.Outcomes = append(report.Outcomes, outcome)
+
+		switch {
+		case outcome.Predicted == LabelScam && sample.Label == LabelScam:
+			report.TruePositives++
+		case outcome.Predicted == LabelScam && sample.Label == LabelLegit:
+			report.FalsePositives++
+		case outcome.Predicted == LabelLegit && sample.Label == LabelLegit:
+			report.TrueNegatives++
+		default:
+			report.FalseNegatives++
+		}
+
+		if outcome.Rule != "" {
+			stats, ok := rules[outcome.Rule]
+			if !ok {
+				stats = &RuleStats{Rule: outcome.Rule}
+				rules[outcome.Rule] = stats
+			}
+			if sample.Label == LabelScam {
+				stats.TruePositives++
+			} else {
+				stats.FalsePositives++
+			}
+		}
+	}
+
+	report.Precision = ratio(report.TruePositives, report.TruePositives+report.FalsePositives)
+	report.Recall = ratio(report.TruePositives, report.TruePositives+report.FalseNegatives)
+	if report.Precision+report.Recall > 0 {
+		report.F1 = 2 * report.Precision * report.Recall / (report.Precision + report.Recall)
+	}
+	decided := report.Total - report.Errors
+	report.Accuracy = ratio(report.TruePositives+report.TrueNegatives, decided)
+
+	for _, stats := range rules {
+		report.Rules = append(report.Rules, *stats)
+	}
+	sort.Slice(report.Rules, func(i, j int) bool {
+		a, b := report.Rules[i], report.Rules[j]
+		if a.TruePositives+a.FalsePositives != b.TruePositives+b.FalsePositives {
+			return a.TruePositives+a.FalsePositives > b.TruePositives+b.FalsePositives
+		}
+		return a.Rule < b.Rule
+	})
+
+	return report
+}
+
+// ruleReasons maps rejection reason prefixes to rule names
+var ruleReasons = []struct {
+	prefix string
+	rule   string
+}{
+	{"Honeypot detected", "holder_fail_rate"},
+	{"Cannot buy", "cannot_buy"},
+	{"Cannot sell all", "cannot_sell_all"},
+	{"High holder fail rate", "holder_fail_rate"},
+	{"Excessive tax", "excessive_tax"},
+	{"Creator has deployed", "creator_honeypot_history"},
+	{"Contract not verified", "contract_not_verified"},
+	{"Proxy implementation not verified", "implementation_not_verified"},
+	{"Liquidity too low", "min_liquidity"},
+	{"Volume too low", "min_volume"},
+	{"Holder concentration too high", "max_top10_holders"},
+	{"Pair too new", "min_pair_age"},
+	{"Liquidity drained", "liquidity_drain"},
+}
+
+// RuleOf names the rule that rejected a failed result. Fraud rejections
+// and hard filters carry their rule names; other stages are matched by reason.
+func RuleOf(r pipeline.TokenResult) string {
+	if r.Stage == pipeline.StageThresholds {
+		return "min_liquidity_volume"
+	}
+	if r.Fraud != nil {
+		if hits := r.Fraud.Rejections(); len(hits) > 0 {
+			return hits[0].Rule
+		}
+	}
+	if r.TokenScore != nil {
+		if failed := r.TokenScore.FailedFilters(); len(failed) > 0 {
+			return failed[0].Name
+		}
+	}
+	if len(r.FailureReasons) == 0 {
+		return r.Stage
+	}
+	for _, rr := range ruleReasons {
+		if strings.HasPrefix(r.FailureReasons[0], rr.prefix) {
+			return rr.rule
+		}
+	}
+	return r.Stage
+}
+
+// String renders the report as text
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Samples: %d (errors excluded from metrics: %d)\n\n", r.Total, r.Errors)
+
+	b.WriteString("Confusion matrix (rows = label, columns = prediction):\n")
+	fmt.Fprintf(&b, "  %-8s | %8s | %8s\n", "", "scam", "legit")
+	fmt.Fprintf(&b, "  %-8s | %8d | %8d\n", "scam", r.TruePositives, r.FalseNegatives)
+	fmt.Fprintf(&b, "  %-8s | %8d | %8d\n\n", "legit", r.FalsePositives, r.TrueNegatives)
+
+	fmt.Fprintf(&b, "Precision: %.3f\nRecall:    %.3f\nF1:        %.3f\nAccuracy:  %.3f\n\n",
+		r.Precision, r.Recall, r.F1, r.Accuracy)
+
+	b.WriteString("Rejections by rule:\n")
+	fmt.Fprintf(&b, "  %-28s | %4s | %4s\n", "Rule", "TP", "FP")
+	for _, rule := range r.Rules {
+		fmt.Fprintf(&b, "  %-28s | %4d | %4d\n", rule.Rule, rule.TruePositives, rule.FalsePositives)
+	}
+
+	b.WriteString("\nMisclassified and undecided:\n")
+	for _, o := range r.Outcomes {
+		if o.Predicted == o.Sample.Label {
+			continue
+		}
+		fmt.Fprintf(&b, "  %-8s %s label=%s predicted=%s", o.Sample.Symbol, o.Sample.Address, o.Sample.Label, o.Predicted)
+		if o.Rule != "" {
+			fmt.Fprintf(&b, " rule=%s", o.Rule)
+		}
+		if o.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", o.Reason)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
@@ -0,0 +1,76 @@
+package evaluation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+)
+
+func TestEvaluate(t *testing.T) {
+	samples := []Sample{
+		{Address: "0x01", Label: LabelScam},
+		{Address: "0x02", Label: LabelScam},
+		{Address: "0x03", Label: LabelLegit},
+		{Address: "0x04", Label: LabelLegit},
+		{Address: "0x05", Label: LabelLegit},
+		{Address: "0x06", Label: LabelScam},
+	}
+	results := []pipeline.TokenResult{
+		{Status: pipeline.StatusFailed, Stage: pipeline.StageFraud, FailureReasons: []string{"High holder fail rate: 30.6%"}},
+		{Status: pipeline.StatusPassed},
+		{Status: pipeline.StatusFailed, Stage: pipeline.StageBscScan, FailureReasons: []string{"Contract not verified"}},
+		{Status: pipeline.StatusPassed},
+		{Status: pipeline.StatusError, ErrorReason: "timeout"},
+		{Status: pipeline.StatusFailed, Stage: pipeline.StageBscScan, FailureReasons: []string{"Contract not verified"}},
+	}
+
+	report := Evaluate(samples, results)
+
+	if report.TruePositives != 2 || report.FalseNegatives != 1 || report.FalsePositives != 1 || report.TrueNegatives != 1 {
+		t.Fatalf("confusion matrix TP=%d FN=%d FP=%d TN=%d, want 2/1/1/1",
+			report.TruePositives, report.FalseNegatives, report.FalsePositives, report.TrueNegatives)
+	}
+	if report.Errors != 1 {
+		t.Errorf("Errors = %d, want 1", report.Errors)
+	}
+	if report.Precision != 2.0/3 || report.Recall != 2.0/3 {
+		t.Errorf("precision/recall = %.3f/%.3f, want 0.667/0.667", report.Precision, report.Recall)
+	}
+	if report.Accuracy != 0.6 {
+		t.Errorf("Accuracy = %.3f, want 0.6", report.Accuracy)
+	}
+
+	want := []RuleStats{
+		{Rule: "contract_not_verified", TruePositives: 1, FalsePositives: 1},
+		{Rule: "holder_fail_rate", TruePositives: 1},
+	}
+	if len(report.Rules) != len(want) {
+		t.Fatalf("Rules = %+v, want %+v", report.Rules, want)
+	}
+	for i := range want {
+		if report.Rules[i] != want[i] {
+			t.Errorf("Rules[%d] = %+v, want %+v", i, report.Rules[i], want[i])
+		}
+	}
+}
+
+func TestLoadDatasetCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.csv")
+	data := "address,label,symbol\n" +
+		"0x9beee89723ceec27d7c2834bec6834208ffdc202,SCAM,AVL\n" +
+		"# comment\n" +
+		"0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82,legit\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := LoadDataset(path)
+	if err != nil {
+		t.Fatalf("LoadDataset: %v", err)
+	}
+	if len(samples) != 2 || samples[0].Label != LabelScam || samples[0].Symbol != "AVL" || samples[1].Label != LabelLegit {
+		t.Errorf("LoadDataset = %+v", samples)
+	}
+}
@@ -0,0 +1,70 @@
+package fraud_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/fraud"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+const avl = "0x9beee89723ceec27d7c2834bec6834208ffdc202"
+
+// TestAggregateFraudCheckAVL replays the recorded Honeypot.is and GoPlus
+// responses for the AVL scam, which GoPlus alone reports as clean
+func TestAggregateFraudCheckAVL(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+
+	limits := transport.Limits{Timeout: 5 * time.Second}
+	honeypot := fraud.NewHoneypotClient(chain.BSC, transport.NewClient(transport.ProviderHoneypot, limits)).
+		WithBaseURL(srv.HoneypotURL())
+	goplus := fraud.NewGoPlusClient(chain.BSC, transport.NewClient(transport.ProviderGoPlus, limits)).
+		WithBaseURL(srv.GoPlusURL())
+
+	honeypotData, err := honeypot.CheckToken(context.Background(), avl)
+	if err != nil {
+		t.Fatalf("honeypot CheckToken: %v", err)
+	}
+	goplusData, err := goplus.CheckToken(context.Background(), avl)
+	if err != nil {
+		t.Fatalf("goplus CheckToken: %v", err)
+	}
+
+	if goplusData.CannotBuy || goplusData.CannotSellAll || goplusData.HoneypotWithCreator {
+		t.Fatalf("recorded GoPlus data unexpectedly flags AVL: %+v", goplusData)
+	}
+
+	// 0xdead (flagged locked by GoPlus) is burned supply and the PancakeV3
+	// tag marks the pair: neither counts towards concentration
+	b := goplusData.HolderBreakdown
+	if b == nil || len(b.Classes) != 3 || b.Classes[0].Label != holders.LabelBurn || b.Classes[1].Label != holders.LabelPair {
+		t.Fatalf("holder breakdown = %+v", b)
+	}
+	if math.Abs(b.RawTop10Concentration-95.24) > 0.01 || math.Abs(goplusData.Top10Concentration-91.63) > 0.01 {
+		t.Errorf("top 10 = %.2f%% (raw %.2f%%), want 91.63%% (raw 95.24%%)", goplusData.Top10Concentration, b.RawTop10Concentration)
+	}
+
+	result := fraud.AggregateFraudCheck(honeypotData, goplusData)
+	if result.IsSafe || !result.IsHoneypot {
+		t.Fatalf("AVL verdict: safe=%t honeypot=%t, want rejected honeypot", result.IsSafe, result.IsHoneypot)
+	}
+	if !strings.Contains(result.RejectionReason, "255/833") {
+		t.Errorf("rejection reason %q should cite the 255/833 failed sells", result.RejectionReason)
+	}
+
+	// One fail-rate rejection, with its evidence
+	hits := result.Rejections()
+	if len(hits) != 1 || hits[0].Rule != "holder_fail_rate" {
+		t.Fatalf("rejections = %+v", hits)
+	}
+	if hits[0].Evidence["failed_sells"] != 255 || hits[0].Evidence["is_honeypot"] != true {
+		t.Errorf("holder_fail_rate evidence = %v", hits[0].Evidence)
+	}
+}
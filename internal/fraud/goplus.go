@@ -1,17 +1,22 @@
 package fraud
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
-	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
 )
 
 type GoPlusClient struct {
+	chain      chain.Chain
 	baseURL    string
-	httpClient *http.Client
+	httpClient *transport.Client
+	cache      *cache.Cache
 }
 
 // GoPlusAPIResponse represents the full API response structure
@@ -29,10 +34,12 @@ type GoPlusAPIResponse struct {
 		CreatorPercent string `json:"creator_percent"`
 		HolderCount    string `json:"holder_count"`
 		Holders        []struct {
-			Address  string `json:"address"`
-			Balance  string `json:"balance"`
-			Percent  string `json:"percent"`
-			IsLocked int    `json:"is_locked"`
+			Address    string `json:"address"`
+			Tag        string `json:"tag"`
+			IsContract int    `json:"is_contract"`
+			Balance    string `json:"balance"`
+			Percent    string `json:"percent"`
+			IsLocked   int    `json:"is_locked"`
 		} `json:"holders"`
 		HoneypotWithCreator string `json:"honeypot_with_same_creator"`
 		IsInDex             string `json:"is_in_dex"`
@@ -49,53 +56,80 @@ type GoPlusAPIResponse struct {
 // GoPlusData represents the extracted fraud-relevant data
 type GoPlusData struct {
 	// Core detection
-	BuyTax        float64
-	SellTax       float64
-	TransferTax   float64
-	CannotBuy     bool
-	CannotSellAll bool
+	BuyTax        float64 `json:"buy_tax"`
+	SellTax       float64 `json:"sell_tax"`
+	TransferTax   float64 `json:"transfer_tax"`
+	CannotBuy     bool    `json:"cannot_buy"`
+	CannotSellAll bool    `json:"cannot_sell_all"`
 
 	// Creator risk
-	CreatorPercent      float64
-	HoneypotWithCreator bool
+	CreatorPercent      float64 `json:"creator_percent"`
+	HoneypotWithCreator bool    `json:"honeypot_with_creator"`
 
 	// Holder data
-	HolderCount        int
-	Top10Concentration float64 // Calculated from holders array
+	HolderCount        int                      `json:"holder_count"`
+	Top10Concentration float64                  `json:"top10_concentration"` // Genuine holders only, see HolderBreakdown
+	Holders            []holders.ReportedHolder `json:"-"`
+	TotalSupply        string                   `json:"-"`
+	HolderBreakdown    *holders.Breakdown       `json:"holder_breakdown,omitempty"`
 
 	// Contract info
-	IsProxy      bool
-	IsOpenSource bool
-	HasOwner     bool // True if owner_address exists and not null
+	IsProxy      bool `json:"is_proxy"`
+	IsOpenSource bool `json:"is_open_source"`
+	HasOwner     bool `json:"has_owner"` // True if owner_address exists and not null
 
 	// LP info
-	LPHolderCount int
+	LPHolderCount int `json:"lp_holder_count"`
+
+	// Raw API response (persisted for auditing)
+	Raw []byte `json:"-"`
 }
 
-func NewGoPlusClient() *GoPlusClient {
+// NewGoPlusClient creates a client for tokens on ch. A nil httpClient uses the shared default transport.
+func NewGoPlusClient(ch chain.Chain, httpClient *transport.Client) *GoPlusClient {
+	if httpClient == nil {
+		httpClient = transport.Default.For(transport.ProviderGoPlus)
+	}
 	return &GoPlusClient{
+		chain:      ch,
 		baseURL:    "https://api.gopluslabs.io",
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		httpClient: httpClient,
 	}
 }
 
-// CheckToken performs security analysis on a token address
-func (g *GoPlusClient) CheckToken(address string) (*GoPlusData, error) {
-	url := fmt.Sprintf("%s/api/v1/token_security/56?contract_addresses=%s", g.baseURL, address)
+// WithBaseURL points the client at a different API host (e.g. a test server)
+func (g *GoPlusClient) WithBaseURL(baseURL string) *GoPlusClient {
+	if baseURL != "" {
+		g.baseURL = baseURL
+	}
+	return g
+}
+
+// WithCache serves security checks from c while they are fresh
+func (g *GoPlusClient) WithCache(c *cache.Cache) *GoPlusClient {
+	g.cache = c
+	return g
+}
 
-	resp, err := g.httpClient.Get(url)
+// CheckToken performs security analysis on a token address
+func (g *GoPlusClient) CheckToken(ctx context.Context, address string) (*GoPlusData, error) {
+	url := fmt.Sprintf("%s/api/v1/token_security/%d?contract_addresses=%s", g.baseURL, g.chain.ID, address)
+
+	key := cache.Key{Provider: transport.ProviderGoPlus, BaseURL: g.baseURL, Endpoint: cache.TokenSecurity, Chain: g.chain.ID, Address: address}
+	body, err := g.cache.Get(ctx, g.httpClient, key, url, func(body []byte) cache.Retention {
+		var resp GoPlusAPIResponse
+		if json.Unmarshal(body, &resp) != nil || resp.Code != 1 {
+			return cache.Discard
+		}
+		return cache.Expire
+	})
 	if err != nil {
-		fmt.Println("Error fetching GoPlus data:", err)
-		return nil, err
+		return nil, fmt.Errorf("fetching GoPlus data: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
 	var apiResp GoPlusAPIResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		fmt.Println("Error unmarshaling GoPlus response:", err)
-		return nil, err
+		return nil, fmt.Errorf("decoding GoPlus response: %w", err)
 	}
 
 	// Check if we got a valid response
@@ -117,14 +151,14 @@ func (g *GoPlusClient) CheckToken(address string) (*GoPlusData, error) {
 	holderCount, _ := strconv.Atoi(tokenData.HolderCount)
 	lpHolderCount, _ := strconv.Atoi(tokenData.LPHolderCount)
 
-	// Calculate top 10 holder concentration
-	var top10Concentration float64
-	if len(tokenData.Holders) > 0 {
-		for _, holder := range tokenData.Holders {
-			percent, _ := strconv.ParseFloat(holder.Percent, 64)
-			top10Concentration += percent
-		}
-		top10Concentration *= 100 // Convert to percentage
+	reported := make([]holders.ReportedHolder, 0, len(tokenData.Holders))
+	for _, holder := range tokenData.Holders {
+		reported = append(reported, holders.ReportedHolder{
+			Address: holder.Address,
+			Balance: holder.Balance,
+			Alias:   holder.Tag,
+			Locked:  holder.IsLocked == 1,
+		})
 	}
 
 	// Determine if owner exists (not renounced)
@@ -141,12 +175,27 @@ func (g *GoPlusClient) CheckToken(address string) (*GoPlusData, error) {
 		CreatorPercent:      creatorPercent,
 		HoneypotWithCreator: tokenData.HoneypotWithCreator == "1",
 		HolderCount:         holderCount,
-		Top10Concentration:  top10Concentration,
+		Holders:             reported,
+		TotalSupply:         tokenData.TotalSupply,
 		IsProxy:             tokenData.IsProxy == "1",
 		IsOpenSource:        tokenData.IsOpenSource == "1",
 		HasOwner:            hasOwner,
 		LPHolderCount:       lpHolderCount,
+		Raw:                 body,
 	}
 
+	// Without the token's pairs, only burn, locker and exchange holders are
+	// recognised; ClassifyHolders refines this once pairs are known
+	data.ClassifyHolders(holders.NewClassifier(g.chain, nil))
+
 	return data, nil
 }
+
+// ClassifyHolders recomputes the holder breakdown and top 10 concentration
+// with classifier
+func (d *GoPlusData) ClassifyHolders(classifier *holders.Classifier) {
+	d.HolderBreakdown = classifier.Breakdown(holders.SourceGoPlus, d.Holders, d.TotalSupply)
+	if d.HolderBreakdown != nil {
+		d.Top10Concentration = d.HolderBreakdown.Top10Concentration
+	}
+}
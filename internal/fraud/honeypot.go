@@ -2,17 +2,21 @@
 package fraud
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
-	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
 )
 
 type HoneypotClient struct {
+	chain      chain.Chain
 	baseURL    string
-	httpClient *http.Client
+	httpClient *transport.Client
+	cache      *cache.Cache
 }
 
 // HoneypotAPIResponse represents the full API response structure
@@ -63,54 +67,72 @@ type HoneypotAPIResponse struct {
 // HoneypotData represents the extracted fraud-relevant data
 type HoneypotData struct {
 	// Core detection
-	IsHoneypot     bool
-	HoneypotReason string
-	RiskLevel      int // 0-100
+	IsHoneypot     bool   `json:"is_honeypot"`
+	HoneypotReason string `json:"honeypot_reason"`
+	RiskLevel      int    `json:"risk_level"` // 0-100
 
 	// Taxes
-	BuyTax      float64
-	SellTax     float64
-	TransferTax float64
+	BuyTax      float64 `json:"buy_tax"`
+	SellTax     float64 `json:"sell_tax"`
+	TransferTax float64 `json:"transfer_tax"`
 
 	// Holder analysis (CRITICAL - catches sophisticated honeypots)
-	TotalHolders    int
-	SuccessfulSells int
-	FailedSells     int
-	FailRate        float64 // Calculated: failed/total
+	TotalHolders    int     `json:"total_holders"`
+	SuccessfulSells int     `json:"successful_sells"`
+	FailedSells     int     `json:"failed_sells"`
+	FailRate        float64 `json:"fail_rate"` // Calculated: failed/total
 
 	// Contract info
-	IsOpenSource  bool
-	IsProxy       bool
-	HasProxyCalls bool
+	IsOpenSource  bool `json:"is_open_source"`
+	IsProxy       bool `json:"is_proxy"`
+	HasProxyCalls bool `json:"has_proxy_calls"`
 
 	// Flags
-	Flags []string
+	Flags []string `json:"flags"`
+
+	// Raw API response (persisted for auditing)
+	Raw []byte `json:"-"`
 }
 
-func NewHoneypotClient() *HoneypotClient {
+// NewHoneypotClient creates a client for tokens on ch. A nil httpClient uses the shared default transport.
+func NewHoneypotClient(ch chain.Chain, httpClient *transport.Client) *HoneypotClient {
+	if httpClient == nil {
+		httpClient = transport.Default.For(transport.ProviderHoneypot)
+	}
 	return &HoneypotClient{
+		chain:      ch,
 		baseURL:    "https://api.honeypot.is",
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		httpClient: httpClient,
 	}
 }
 
+// WithBaseURL points the client at a different API host (e.g. a test server)
+func (h *HoneypotClient) WithBaseURL(baseURL string) *HoneypotClient {
+	if baseURL != "" {
+		h.baseURL = baseURL
+	}
+	return h
+}
+
+// WithCache serves simulations from c while they are fresh
+func (h *HoneypotClient) WithCache(c *cache.Cache) *HoneypotClient {
+	h.cache = c
+	return h
+}
+
 // CheckToken performs honeypot analysis on a token address
-func (h *HoneypotClient) CheckToken(address string) (*HoneypotData, error) {
-	url := fmt.Sprintf("%s/v2/IsHoneypot?address=%s&chainID=56", h.baseURL, address)
+func (h *HoneypotClient) CheckToken(ctx context.Context, address string) (*HoneypotData, error) {
+	url := fmt.Sprintf("%s/v2/IsHoneypot?address=%s&chainID=%d", h.baseURL, address, h.chain.ID)
 
-	resp, err := h.httpClient.Get(url)
+	key := cache.Key{Provider: transport.ProviderHoneypot, BaseURL: h.baseURL, Endpoint: cache.Honeypot, Chain: h.chain.ID, Address: address}
+	body, err := h.cache.Get(ctx, h.httpClient, key, url, cache.ValidJSON)
 	if err != nil {
-		fmt.Println("Error fetching honeypot data:", err)
-		return nil, err
+		return nil, fmt.Errorf("fetching honeypot data: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
 	var apiResp HoneypotAPIResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		fmt.Println("Error unmarshaling honeypot response:", err)
-		return nil, err
+		return nil, fmt.Errorf("decoding honeypot response: %w", err)
 	}
 
 	// Parse holder analysis strings to ints
@@ -140,6 +162,7 @@ func (h *HoneypotClient) CheckToken(address string) (*HoneypotData, error) {
 		IsProxy:         apiResp.ContractCode.IsProxy,
 		HasProxyCalls:   apiResp.ContractCode.HasProxyCalls,
 		Flags:           apiResp.Flags,
+		Raw:             body,
 	}
 
 	return data, nil
@@ -0,0 +1,227 @@
+package fraud
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMinHolderSample is the fewest simulated holders for Honeypot.is
+// fail rates to be trusted
+const DefaultMinHolderSample = 100
+
+// Rule is one rule's policy
+type Rule struct {
+	Name      string   `json:"name" yaml:"name"`
+	Severity  Severity `json:"severity" yaml:"severity"`
+	Weight    int      `json:"weight" yaml:"weight"`                           // Risk score points when triggered
+	Threshold float64  `json:"threshold,omitempty" yaml:"threshold,omitempty"` // Rule-specific, see RuleDescriptions
+	Enabled   bool     `json:"enabled" yaml:"enabled"`
+}
+
+// Policy configures the fraud rules
+type Policy struct {
+	MinHolderSample int    `json:"min_holder_sample" yaml:"min_holder_sample"`
+	Rules           []Rule `json:"rules" yaml:"rules"`
+}
+
+// DefaultPolicy enables every built-in rule with its default severity,
+// weight and threshold
+func DefaultPolicy() Policy {
+	p := Policy{MinHolderSample: DefaultMinHolderSample}
+	for _, def := range builtinRules {
+		r := def.defaults
+		r.Name, r.Enabled = def.name, true
+		p.Rules = append(p.Rules, r)
+	}
+	return p
+}
+
+// RuleDescriptions maps rule names to what they check
+func RuleDescriptions() map[string]string {
+	descriptions := make(map[string]string, len(builtinRules))
+	for _, def := range builtinRules {
+		descriptions[def.name] = def.description
+	}
+	return descriptions
+}
+
+// policyFile is the on-disk policy. Omitted fields keep their defaults, so
+// a file only lists what it changes.
+type policyFile struct {
+	MinHolderSample *int `yaml:"min_holder_sample"`
+	Rules           []struct {
+		Name      string    `yaml:"name"`
+		Severity  *Severity `yaml:"severity"`
+		Weight    *int      `yaml:"weight"`
+		Threshold *float64  `yaml:"threshold"`
+		Enabled   *bool     `yaml:"enabled"`
+	} `yaml:"rules"`
+}
+
+// LoadPolicy reads a YAML or JSON policy file on top of DefaultPolicy. An
+// empty path returns the default policy.
+func LoadPolicy(path string) (Policy, error) {
+	return DefaultPolicy().OverlayFile(path)
+}
+
+// OverlayFile applies a YAML or JSON policy file on top of p. An empty path
+// returns p unchanged.
+func (p Policy) OverlayFile(path string) (Policy, error) {
+	if path == "" {
+		return p, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("reading fraud policy: %w", err)
+	}
+	p, err = p.Overlay(data)
+	if err != nil {
+		return Policy{}, fmt.Errorf("fraud policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ParsePolicy parses a YAML (or JSON, a subset of YAML) policy on top of
+// DefaultPolicy. Unknown rules and fields are errors.
+func ParsePolicy(data []byte) (Policy, error) {
+	return DefaultPolicy().Overlay(data)
+}
+
+// Overlay applies a YAML or JSON policy on top of p
+func (p Policy) Overlay(data []byte) (Policy, error) {
+	var file policyFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return Policy{}, err
+	}
+
+	p.Rules = append([]Rule(nil), p.Rules...)
+	if file.MinHolderSample != nil {
+		p.MinHolderSample = *file.MinHolderSample
+	}
+	index := make(map[string]int, len(p.Rules))
+	for i, r := range p.Rules {
+		index[r.Name] = i
+	}
+	for _, fr := range file.Rules {
+		i, ok := index[fr.Name]
+		if !ok {
+			return Policy{}, fmt.Errorf("unknown rule %q", fr.Name)
+		}
+		r := &p.Rules[i]
+		if fr.Severity != nil {
+			r.Severity = *fr.Severity
+		}
+		if fr.Weight != nil {
+			r.Weight = *fr.Weight
+		}
+		if fr.Threshold != nil {
+			r.Threshold = *fr.Threshold
+		}
+		if fr.Enabled != nil {
+			r.Enabled = *fr.Enabled
+		}
+	}
+	return p, p.Validate()
+}
+
+// WithThresholds returns p with the thresholds of the named rules replaced
+func (p Policy) WithThresholds(thresholds map[string]float64) (Policy, error) {
+	p.Rules = append([]Rule(nil), p.Rules...)
+	known := make(map[string]bool, len(p.Rules))
+	for i := range p.Rules {
+		known[p.Rules[i].Name] = true
+		if t, ok := thresholds[p.Rules[i].Name]; ok {
+			p.Rules[i].Threshold = t
+		}
+	}
+	for name := range thresholds {
+		if !known[name] {
+			return Policy{}, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return p, p.Validate()
+}
+
+// Validate checks rule names, severities and weights
+func (p Policy) Validate() error {
+	var errs []string
+	if p.MinHolderSample < 0 {
+		errs = append(errs, fmt.Sprintf("min_holder_sample %d is negative", p.MinHolderSample))
+	}
+	known := RuleDescriptions()
+	seen := map[string]bool{}
+	for _, r := range p.Rules {
+		switch {
+		case known[r.Name] == "":
+			errs = append(errs, fmt.Sprintf("unknown rule %q", r.Name))
+		case seen[r.Name]:
+			errs = append(errs, fmt.Sprintf("rule %q listed twice", r.Name))
+		}
+		seen[r.Name] = true
+		if r.Severity != SeverityReject && r.Severity != SeverityWarn {
+			errs = append(errs, fmt.Sprintf("rule %q: severity %q is not %q or %q", r.Name, r.Severity, SeverityReject, SeverityWarn))
+		}
+		if r.Weight < 0 || r.Weight > 100 {
+			errs = append(errs, fmt.Sprintf("rule %q: weight %d is outside 0-100", r.Name, r.Weight))
+		}
+		if r.Threshold < 0 {
+			errs = append(errs, fmt.Sprintf("rule %q: threshold %g is negative", r.Name, r.Threshold))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// RuleHit is a triggered rule and the data that triggered it
+type RuleHit struct {
+	Rule     string         `json:"rule"`
+	Severity Severity       `json:"severity"`
+	Weight   int            `json:"weight"`
+	Message  string         `json:"message"`
+	Evidence map[string]any `json:"evidence,omitempty"`
+}
+
+// Engine evaluates a policy's enabled rules
+type Engine struct {
+	policy Policy
+	rules  []boundRule
+}
+
+type boundRule struct {
+	Rule
+	def ruleDef
+}
+
+// NewEngine validates policy and binds its rules to their checks
+func NewEngine(policy Policy) (*Engine, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	defs := make(map[string]ruleDef, len(builtinRules))
+	for _, def := range builtinRules {
+		defs[def.name] = def
+	}
+	e := &Engine{policy: policy}
+	for _, r := range policy.Rules {
+		if r.Enabled {
+			e.rules = append(e.rules, boundRule{Rule: r, def: defs[r.Name]})
+		}
+	}
+	return e, nil
+}
+
+// Policy returns the engine's policy
+func (e *Engine) Policy() Policy { return e.policy }
+
+// defaultEngine runs DefaultPolicy, which always validates
+var defaultEngine, _ = NewEngine(DefaultPolicy())
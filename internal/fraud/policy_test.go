@@ -0,0 +1,145 @@
+package fraud_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/fraud"
+)
+
+func TestEngineReportsEveryTriggeredRule(t *testing.T) {
+	honeypot := &fraud.HoneypotData{BuyTax: 10, SellTax: 12, IsOpenSource: true, TotalHolders: 40}
+	goplus := &fraud.GoPlusData{CannotBuy: true, IsOpenSource: true, LPHolderCount: 1}
+
+	result := fraud.AggregateFraudCheck(honeypot, goplus)
+	if result.IsSafe || !result.IsHoneypot {
+		t.Fatalf("safe=%t honeypot=%t, want rejected honeypot", result.IsSafe, result.IsHoneypot)
+	}
+
+	var rules []string
+	for _, hit := range result.TriggeredRules {
+		rules = append(rules, hit.Rule)
+	}
+	if got, want := strings.Join(rules, ","), "cannot_buy,excessive_tax,centralized_liquidity,high_tax"; got != want {
+		t.Errorf("triggered %s, want %s", got, want)
+	}
+	if len(result.Rejections()) != 2 || result.RejectionReason != "Cannot buy token (GoPlus)" {
+		t.Errorf("rejections = %+v, reason %q", result.Rejections(), result.RejectionReason)
+	}
+	if tax := result.TriggeredRules[1].Evidence["total_tax"]; tax != 22.0 {
+		t.Errorf("excessive_tax evidence total_tax = %v, want 22", tax)
+	}
+	if result.RiskScore != 100 {
+		t.Errorf("RiskScore = %d, want capped at 100", result.RiskScore)
+	}
+}
+
+func TestUnlistedWarningsStillScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		honeypot fraud.HoneypotData
+		goplus   fraud.GoPlusData
+		factors  string
+		score    int
+	}{
+		{"owned small token", fraud.HoneypotData{}, fraud.GoPlusData{HasOwner: true, HolderCount: 1000},
+			"owner_not_renounced", 10},
+		{"owned major token", fraud.HoneypotData{}, fraud.GoPlusData{HasOwner: true, HolderCount: 80000},
+			"", 10},
+		{"flagged moderate fail rate", fraud.HoneypotData{IsHoneypot: true, TotalHolders: 20, FailRate: 0.07}, fraud.GoPlusData{},
+			"honeypot_flagged_moderate_fail_rate_7.0%", 20},
+		{"unflagged moderate fail rate", fraud.HoneypotData{TotalHolders: 200, FailRate: 0.07}, fraud.GoPlusData{},
+			"", 20},
+	}
+	for _, tt := range tests {
+		tt.honeypot.IsOpenSource, tt.goplus.IsOpenSource = true, true
+		result := fraud.AggregateFraudCheck(&tt.honeypot, &tt.goplus)
+		if got := strings.Join(result.RiskFactors, ","); !result.IsSafe || got != tt.factors || result.RiskScore != tt.score {
+			t.Errorf("%s: safe=%t, factors %q, score %d, want %q and %d", tt.name, result.IsSafe, got, result.RiskScore, tt.factors, tt.score)
+		}
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := fraud.ParsePolicy([]byte(`
+min_holder_sample: 50
+rules:
+  - name: excessive_tax
+    severity: warn
+    threshold: 25
+  - name: centralized_liquidity
+    enabled: false
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := fraud.NewEngine(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 22% tax is now only noted, and 60 holders are enough to trust the fail rate
+	honeypot := &fraud.HoneypotData{BuyTax: 10, SellTax: 12, IsOpenSource: true, TotalHolders: 60, FailRate: 0.5}
+	goplus := &fraud.GoPlusData{IsOpenSource: true, LPHolderCount: 1}
+	result := engine.Evaluate(honeypot, goplus)
+
+	if len(result.Rejections()) != 1 || result.Rejections()[0].Message != "High holder fail rate: 50.0% (0/60 holders cannot sell)" {
+		t.Errorf("rejections = %+v, want holder_fail_rate only", result.Rejections())
+	}
+	// Honeypot.is did not flag it, so the moderate fail rate is scored but not listed
+	if got := strings.Join(result.RiskFactors, ","); got != "high_tax_22.0%" {
+		t.Errorf("RiskFactors = %s", got)
+	}
+
+	// JSON is accepted as well
+	if _, err := fraud.ParsePolicy([]byte(`{"rules": [{"name": "high_tax", "weight": 5}]}`)); err != nil {
+		t.Errorf("JSON policy: %v", err)
+	}
+}
+
+func TestWithThresholds(t *testing.T) {
+	policy, err := fraud.ParsePolicy([]byte("rules:\n  - name: excessive_tax\n    threshold: 30\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	overridden, err := policy.WithThresholds(map[string]float64{"excessive_tax": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	thresholdsOf := func(p fraud.Policy) map[string]float64 {
+		thresholds := map[string]float64{}
+		for _, r := range p.Rules {
+			thresholds[r.Name] = r.Threshold
+		}
+		return thresholds
+	}
+	if got := thresholdsOf(overridden); got["excessive_tax"] != 20 || got["high_tax"] != 10 {
+		t.Errorf("overridden thresholds = %v", got)
+	}
+	if got := thresholdsOf(policy); got["excessive_tax"] != 30 {
+		t.Errorf("original excessive_tax changed to %g", got["excessive_tax"])
+	}
+
+	for name, thresholds := range map[string]map[string]float64{
+		"unknown rule":       {"moon_soon": 1},
+		"negative threshold": {"high_tax": -1},
+	} {
+		if _, err := policy.WithThresholds(thresholds); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown rule":  "rules:\n  - name: moon_soon\n",
+		"bad severity":  "rules:\n  - name: high_tax\n    severity: block\n",
+		"bad weight":    "rules:\n  - name: high_tax\n    weight: 150\n",
+		"unknown field": "rules:\n  - name: high_tax\n    treshold: 5\n",
+	}
+	for name, policy := range tests {
+		if _, err := fraud.ParsePolicy([]byte(policy)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package fraud
+
+import "fmt"
+
+// Severity decides what a triggered rule does to the verdict
+type Severity string
+
+const (
+	SeverityReject Severity = "reject" // Token fails the fraud stage
+	SeverityWarn   Severity = "warn"   // Reported as a risk factor and added to the risk score
+)
+
+// Input is what rules are evaluated against
+type Input struct {
+	Honeypot *HoneypotData
+	GoPlus   *GoPlusData
+
+	// Derived once per evaluation
+	MaxBuyTax       float64
+	MaxSellTax      float64
+	TotalTax        float64
+	TrustedFailRate bool // Honeypot.is simulated enough holders for FailRate to count
+}
+
+// check reports whether a rule triggers. A non-empty message means it did;
+// for warn rules the message is the risk factor.
+type check func(in Input, threshold float64) (message string, evidence map[string]any)
+
+// ruleDef is a built-in rule and its default policy
+type ruleDef struct {
+	name        string
+	description string
+	honeypot    bool // Triggering marks the token as a honeypot
+	defaults    Rule
+	check       check
+	// listed decides whether a triggered warn rule is reported as a risk
+	// factor; its weight counts either way. nil lists every trigger.
+	listed func(in Input, threshold float64) bool
+}
+
+// builtinRules in evaluation and reporting order
+var builtinRules = []ruleDef{
+	{
+		name:        "cannot_buy",
+		description: "GoPlus reports the token cannot be bought",
+		honeypot:    true,
+		defaults:    Rule{Severity: SeverityReject, Weight: 100},
+		check: func(in Input, _ float64) (string, map[string]any) {
+			if !in.GoPlus.CannotBuy {
+				return "", nil
+			}
+			return "Cannot buy token (GoPlus)", map[string]any{"goplus_cannot_buy": true}
+		},
+	},
+	{
+		name:        "cannot_sell_all",
+		description: "GoPlus reports holders cannot sell their whole balance",
+		honeypot:    true,
+		defaults:    Rule{Severity: SeverityReject, Weight: 100},
+		check: func(in Input, _ float64) (string, map[string]any) {
+			if !in.GoPlus.CannotSellAll {
+				return "", nil
+			}
+			return "Cannot sell all tokens - partial honeypot (GoPlus)", map[string]any{"goplus_cannot_sell_all": true}
+		},
+	},
+	{
+		name:        "holder_fail_rate",
+		description: "Too many simulated holder sells fail, whether or not Honeypot.is flags a honeypot (threshold: fail rate)",
+		honeypot:    true,
+		defaults:    Rule{Severity: SeverityReject, Weight: 100, Threshold: 0.10},
+		check: func(in Input, threshold float64) (string, map[string]any) {
+			h := in.Honeypot
+			if !in.TrustedFailRate || h.FailRate <= threshold {
+				return "", nil
+			}
+			reason := "High holder fail rate"
+			if h.IsHoneypot {
+				reason = "Honeypot detected with high fail rate"
+			}
+			return fmt.Sprintf("%s: %.1f%% (%d/%d holders cannot sell)",
+				reason, h.FailRate*100, h.FailedSells, h.TotalHolders), failRateEvidence(h)
+		},
+	},
+	{
+		name:        "excessive_tax",
+		description: "Buy plus sell tax, the higher of both providers (threshold: percent)",
+		defaults:    Rule{Severity: SeverityReject, Weight: 100, Threshold: 15},
+		check: func(in Input, threshold float64) (string, map[string]any) {
+			if in.TotalTax <= threshold {
+				return "", nil
+			}
+			return fmt.Sprintf("Excessive tax: %.1f%% (buy: %.1f%%, sell: %.1f%%)", in.TotalTax, in.MaxBuyTax, in.MaxSellTax),
+				taxEvidence(in)
+		},
+	},
+	{
+		name:        "creator_honeypot_history",
+		description: "GoPlus reports the creator deployed other honeypots",
+		honeypot:    true,
+		defaults:    Rule{Severity: SeverityReject, Weight: 100},
+		check: func(in Input, _ float64) (string, map[string]any) {
+			if !in.GoPlus.HoneypotWithCreator {
+				return "", nil
+			}
+			return "Creator has deployed other honeypot tokens (GoPlus)", map[string]any{"goplus_honeypot_with_same_creator": true}
+		},
+	},
+	{
+		name:        "moderate_fail_rate",
+		description: "Some simulated holder sells fail; a risk factor only when Honeypot.is flags a honeypot (threshold: fail rate)",
+		defaults:    Rule{Severity: SeverityWarn, Weight: 20, Threshold: 0.05},
+		check: func(in Input, threshold float64) (string, map[string]any) {
+			h := in.Honeypot
+			// A honeypot flag makes even a small sample worth reporting
+			if (!h.IsHoneypot && !in.TrustedFailRate) || h.FailRate <= threshold {
+				return "", nil
+			}
+			return fmt.Sprintf("honeypot_flagged_moderate_fail_rate_%.1f%%", h.FailRate*100), failRateEvidence(h)
+		},
+		listed: func(in Input, _ float64) bool { return in.Honeypot.IsHoneypot },
+	},
+	{
+		name:        "proxy_contract",
+		description: "Upgradeable proxy: the owner can replace the code",
+		defaults:    Rule{Severity: SeverityWarn, Weight: 15},
+		check: func(in Input, _ float64) (string, map[string]any) {
+			if !in.Honeypot.IsProxy && !in.GoPlus.IsProxy {
+				return "", nil
+			}
+			return "proxy_contract", map[string]any{"honeypot_is_proxy": in.Honeypot.IsProxy, "goplus_is_proxy": in.GoPlus.IsProxy}
+		},
+	},
+	{
+		name:        "not_open_source",
+		description: "Either provider cannot see verified source",
+		defaults:    Rule{Severity: SeverityWarn, Weight: 10},
+		check: func(in Input, _ float64) (string, map[string]any) {
+			if in.Honeypot.IsOpenSource && in.GoPlus.IsOpenSource {
+				return "", nil
+			}
+			return "not_open_source", map[string]any{"honeypot_open_source": in.Honeypot.IsOpenSource, "goplus_open_source": in.GoPlus.IsOpenSource}
+		},
+	},
+	{
+		name:        "owner_not_renounced",
+		description: "Ownership not renounced; not a risk factor for major tokens, though still scored (threshold: holder count of a major token)",
+		defaults:    Rule{Severity: SeverityWarn, Weight: 10, Threshold: 50000},
+		check: func(in Input, _ float64) (string, map[string]any) {
+			if !in.GoPlus.HasOwner {
+				return "", nil
+			}
+			return "owner_not_renounced", map[string]any{"holder_count": in.GoPlus.HolderCount}
+		},
+		listed: func(in Input, threshold float64) bool { return float64(in.GoPlus.HolderCount) < threshold },
+	},
+	{
+		name:        "high_creator_holdings",
+		description: "The creator still holds a large share of supply (threshold: fraction of supply)",
+		defaults:    Rule{Severity: SeverityWarn, Weight: 20, Threshold: 0.20},
+		check: func(in Input, threshold float64) (string, map[string]any) {
+			if in.GoPlus.CreatorPercent <= threshold {
+				return "", nil
+			}
+			return fmt.Sprintf("high_creator_holdings_%.1f%%", in.GoPlus.CreatorPercent*100),
+				map[string]any{"creator_percent": in.GoPlus.CreatorPercent}
+		},
+	},
+	{
+		name:        "centralized_liquidity",
+		description: "A single address holds all LP tokens",
+		defaults:    Rule{Severity: SeverityWarn, Weight: 0},
+		check: func(in Input, _ float64) (string, map[string]any) {
+			if in.GoPlus.LPHolderCount != 1 {
+				return "", nil
+			}
+			return "centralized_liquidity", map[string]any{"lp_holder_count": in.GoPlus.LPHolderCount}
+		},
+	},
+	{
+		name:        "high_tax",
+		description: "Buy plus sell tax worth noting below the reject level (threshold: percent)",
+		defaults:    Rule{Severity: SeverityWarn, Weight: 15, Threshold: 10},
+		check: func(in Input, threshold float64) (string, map[string]any) {
+			if in.TotalTax <= threshold {
+				return "", nil
+			}
+			return fmt.Sprintf("high_tax_%.1f%%", in.TotalTax), taxEvidence(in)
+		},
+	},
+}
+
+func failRateEvidence(h *HoneypotData) map[string]any {
+	return map[string]any{
+		"fail_rate":     h.FailRate,
+		"failed_sells":  h.FailedSells,
+		"total_holders": h.TotalHolders,
+		"is_honeypot":   h.IsHoneypot,
+	}
+}
+
+func taxEvidence(in Input) map[string]any {
+	return map[string]any{
+		"total_tax":        in.TotalTax,
+		"buy_tax":          in.MaxBuyTax,
+		"sell_tax":         in.MaxSellTax,
+		"honeypot_buy_tax": in.Honeypot.BuyTax, "honeypot_sell_tax": in.Honeypot.SellTax,
+		"goplus_buy_tax": in.GoPlus.BuyTax, "goplus_sell_tax": in.GoPlus.SellTax,
+	}
+}
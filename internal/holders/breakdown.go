@@ -0,0 +1,79 @@
+package holders
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Top-holder list providers
+const (
+	SourceHoneypot = "honeypot"
+	SourceGoPlus   = "goplus"
+)
+
+// ReportedHolder is one entry of a provider's top-holder list. Balance is
+// a decimal amount in whole tokens, as the provider reports it.
+type ReportedHolder struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+	Alias   string `json:"alias,omitempty"` // Provider label (Honeypot.is alias, GoPlus tag)
+	Locked  bool   `json:"locked,omitempty"`
+}
+
+// Breakdown classifies a provider's top-holder list. Providers only list
+// the largest holders, so circulating supply is the total supply minus the
+// listed holders that are not genuine wallets.
+type Breakdown struct {
+	Source                string       `json:"source"`
+	Top10Concentration    float64      `json:"top10_concentration"`     // Genuine top 10, percent of circulating supply
+	RawTop10Concentration float64      `json:"raw_top10_concentration"` // Every listed holder, percent of total supply
+	Classes               []ClassShare `json:"classes"`
+	Holders               []Holder     `json:"holders"` // Percent of total supply, largest first
+}
+
+// Breakdown classifies reported against totalSupply (same unit as the
+// balances). It returns nil when the list or the supply is empty.
+func (c *Classifier) Breakdown(source string, reported []ReportedHolder, totalSupply string) *Breakdown {
+	total, _ := strconv.ParseFloat(totalSupply, 64)
+	if len(reported) == 0 || total <= 0 {
+		return nil
+	}
+
+	b := &Breakdown{Source: source}
+	var excludedSum, rawSum float64
+	var genuine []float64
+	for _, r := range reported {
+		balance, _ := strconv.ParseFloat(r.Balance, 64)
+		label, name := c.Classify(r.Address, r.Alias, r.Locked)
+		b.Holders = append(b.Holders, Holder{
+			Address: r.Address,
+			Balance: r.Balance,
+			Percent: balance / total * 100,
+			Label:   label,
+			Name:    name,
+		})
+		rawSum += balance
+		if label == LabelWallet {
+			genuine = append(genuine, balance)
+		} else {
+			excludedSum += balance
+		}
+	}
+	sort.SliceStable(b.Holders, func(i, j int) bool { return b.Holders[i].Percent > b.Holders[j].Percent })
+	sort.Sort(sort.Reverse(sort.Float64Slice(genuine)))
+
+	var top10 float64
+	for i, balance := range genuine {
+		if i == 10 {
+			break
+		}
+		top10 += balance
+	}
+	if circulating := total - excludedSum; circulating > 0 {
+		b.Top10Concentration = top10 / circulating * 100
+	}
+	b.RawTop10Concentration = rawSum / total * 100
+
+	b.Classes = classShares(b.Holders, 0, 0)
+	return b
+}
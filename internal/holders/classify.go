@@ -0,0 +1,138 @@
+// Package holders classifies token holders (burn, DEX pair, locker, CEX or
+// genuine wallet) and computes concentration metrics over genuine holders,
+// either from a provider's top-holder list or from an on-chain index of the
+// token's ERC-20 Transfer logs
+package holders
+
+import (
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+)
+
+// Holder classes. Everything but LabelWallet is excluded from concentration.
+const (
+	LabelBurn   = "burn"
+	LabelPair   = "pair"
+	LabelLocker = "locker"
+	LabelCEX    = "cex"
+	LabelWallet = "wallet"
+)
+
+// Labels lists the classes in reporting order
+var Labels = []string{LabelBurn, LabelPair, LabelLocker, LabelCEX, LabelWallet}
+
+// burnAddresses hold tokens nobody can move
+var burnAddresses = []string{
+	"0x0000000000000000000000000000000000000000",
+	"0x0000000000000000000000000000000000000001",
+	"0x000000000000000000000000000000000000dead",
+	"0xdead000000000000000042069420694206942069",
+}
+
+// knownLockers are token/LP lock contracts per chain ID
+var knownLockers = map[int64]map[string]string{
+	56: {
+		"0x407993575c91ce7643a4d4ccacc9a98c36ee1bbe": "PinkLock",
+		"0xc765bddb93b0d1c1a88282ba0fa6b2d00e3e0c83": "Unicrypt",
+		"0x0c89c0407775dd89b12918b9c0aa42bf96518820": "Team.Finance",
+	},
+	1: {
+		"0x71b5759d73262fbb223956913ecf4ecc51057641": "PinkLock",
+		"0x663a5c229c09b049e36dcc11a9b0d4a8eb9db214": "Unicrypt",
+		"0xe2fe530c047f2d85298b07d9333c05737f1435fb": "Team.Finance",
+	},
+}
+
+// knownCEX are exchange hot wallets per chain ID
+var knownCEX = map[int64]map[string]string{
+	56: {
+		"0xf977814e90da44bfa03b6295a0616a897441acec": "Binance",
+		"0x8894e0a0c962cb723c1976a4421c95949be2d4e3": "Binance",
+		"0x5a52e96bacdabb82fd05763e25335261b270efcb": "Binance",
+		"0xe2fc31f816a9b94326492132018c3aecc4a93ae1": "Binance",
+		"0x0d0707963952f2fba59dd06f2b425ace40b492fe": "Gate.io",
+	},
+	1: {
+		"0xf977814e90da44bfa03b6295a0616a897441acec": "Binance",
+		"0x28c6c06298d514db089934071355e5743bf21d60": "Binance",
+		"0x21a31ee1afc51d94c2efccaa2092ad1028285549": "Binance",
+		"0xdfd5293d8e347dfe59e90efd55b2956a1343963d": "Binance",
+		"0x71660c4005ba85c37ccec55d0c4493e66fe775d3": "Coinbase",
+		"0x503828976d22510aad0201ac7ec88293211d23da": "Coinbase",
+		"0x0d0707963952f2fba59dd06f2b425ace40b492fe": "Gate.io",
+	},
+}
+
+// Provider labels (Honeypot.is aliases, GoPlus tags) that identify a class
+var (
+	lockerAliases = []string{"pinklock", "pinksale", "unicrypt", "uncx", "team.finance", "team finance", "mudra"}
+	cexAliases    = []string{"binance", "okx", "okex", "bybit", "gate.io", "kucoin", "mexc", "bitget", "coinbase", "kraken", "huobi", "htx", "crypto.com", "bitmart"}
+	pairTags      = []string{"pancakev2", "pancakev3", "pancakeswapv2", "pancakeswapv3", "uniswapv2", "uniswapv3", "sushiswap", "aerodrome"}
+)
+
+// Classifier assigns holder classes for one token on one chain
+type Classifier struct {
+	chain chain.Chain
+	pairs map[string]bool
+}
+
+// NewClassifier creates a classifier for a token whose DEX pairs (from
+// DexScreener) are pairs
+func NewClassifier(ch chain.Chain, pairs []string) *Classifier {
+	c := &Classifier{chain: ch, pairs: make(map[string]bool, len(pairs))}
+	for _, p := range pairs {
+		c.pairs[strings.ToLower(p)] = true
+	}
+	return c
+}
+
+// Classify returns the class of address and, for lockers and exchanges, the
+// service's name. alias is the provider's label for the address ("" if none)
+// and locked the provider's lock flag. A nil classifier treats every
+// address as a wallet.
+func (c *Classifier) Classify(address, alias string, locked bool) (label, name string) {
+	if c == nil {
+		return LabelWallet, ""
+	}
+	address = strings.ToLower(address)
+	alias = strings.ToLower(alias)
+
+	for _, burn := range burnAddresses {
+		if address == burn {
+			return LabelBurn, ""
+		}
+	}
+	if c.pairs[address] || containsAny(strings.ReplaceAll(alias, " ", ""), pairTags) {
+		return LabelPair, ""
+	}
+	if name, ok := knownLockers[c.chain.ID][address]; ok {
+		return LabelLocker, name
+	}
+	if kw := firstMatch(alias, lockerAliases); kw != "" || locked {
+		return LabelLocker, kw
+	}
+	if name, ok := knownCEX[c.chain.ID][address]; ok {
+		return LabelCEX, name
+	}
+	if kw := firstMatch(alias, cexAliases); kw != "" {
+		return LabelCEX, kw
+	}
+	return LabelWallet, ""
+}
+
+func containsAny(s string, keywords []string) bool {
+	return firstMatch(s, keywords) != ""
+}
+
+func firstMatch(s string, keywords []string) string {
+	if s == "" {
+		return ""
+	}
+	for _, kw := range keywords {
+		if strings.Contains(s, kw) {
+			return kw
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package holders_test
+
+import (
+	"testing"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+)
+
+func TestClassify(t *testing.T) {
+	c := holders.NewClassifier(chain.BSC, []string{"0x7192966c6d3AB630eE60dBD4c1B39E9e8267F8cF"})
+
+	tests := []struct {
+		address, alias string
+		locked         bool
+		label, name    string
+	}{
+		// Burn wins over the lock flag GoPlus sets on 0xdead
+		{"0x000000000000000000000000000000000000dEaD", "", true, holders.LabelBurn, ""},
+		{"0x7192966c6d3ab630ee60dbd4c1b39e9e8267f8cf", "", false, holders.LabelPair, ""},
+		{"0x0000000000000000000000000000000000000abc", "PancakeSwap V2: CAKE-BNB", false, holders.LabelPair, ""},
+		{"0x407993575c91ce7643a4d4ccacc9a98c36ee1bbe", "", false, holders.LabelLocker, "PinkLock"},
+		{"0x0000000000000000000000000000000000000abc", "UNCX Network Security: Token Vesting", false, holders.LabelLocker, "uncx"},
+		{"0x0000000000000000000000000000000000000abc", "", true, holders.LabelLocker, ""},
+		{"0xf977814e90da44bfa03b6295a0616a897441acec", "", false, holders.LabelCEX, "Binance"},
+		{"0x0000000000000000000000000000000000000abc", "Bybit Hot Wallet", false, holders.LabelCEX, "bybit"},
+		{"0x45c54210128a065de780c4b0df3d16664f7f859e", "PancakeSwap: Cake Pool", false, holders.LabelWallet, ""},
+	}
+	for _, tt := range tests {
+		label, name := c.Classify(tt.address, tt.alias, tt.locked)
+		if label != tt.label || name != tt.name {
+			t.Errorf("Classify(%s, %q, %t) = %s %q, want %s %q", tt.address, tt.alias, tt.locked, label, name, tt.label, tt.name)
+		}
+	}
+
+	// Exchange wallets are per chain
+	if label, _ := holders.NewClassifier(chain.Base, nil).Classify("0x8894e0a0c962cb723c1976a4421c95949be2d4e3", "", false); label != holders.LabelWallet {
+		t.Errorf("BSC hot wallet on Base classified as %s", label)
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	reported := []holders.ReportedHolder{
+		{Address: "0x000000000000000000000000000000000000dead", Balance: "400"},
+		{Address: thinPair, Balance: "100"},
+		{Address: "0x00000000000000000000000000000000000000f1", Balance: "60"},
+		{Address: "0x00000000000000000000000000000000000000f2", Balance: "30", Alias: "Team Finance: Lock"},
+		{Address: "0x00000000000000000000000000000000000000f3", Balance: "40"},
+	}
+
+	b := holders.NewClassifier(chain.BSC, []string{thinPair}).Breakdown(holders.SourceGoPlus, reported, "1000")
+	if b == nil {
+		t.Fatal("Breakdown returned nil")
+	}
+	// 100 in genuine wallets out of 1000 - 400 - 100 - 30 circulating
+	if !near(b.Top10Concentration, 100.0/470*100) || !near(b.RawTop10Concentration, 63) {
+		t.Errorf("Top10 = %.2f%%, raw = %.2f%%", b.Top10Concentration, b.RawTop10Concentration)
+	}
+
+	want := []holders.ClassShare{
+		{Label: holders.LabelBurn, Holders: 1, Percent: 40},
+		{Label: holders.LabelPair, Holders: 1, Percent: 10},
+		{Label: holders.LabelLocker, Holders: 1, Percent: 3},
+		{Label: holders.LabelWallet, Holders: 2, Percent: 10},
+	}
+	if len(b.Classes) != len(want) {
+		t.Fatalf("Classes = %+v", b.Classes)
+	}
+	for i, w := range want {
+		if got := b.Classes[i]; got.Label != w.Label || got.Holders != w.Holders || !near(got.Percent, w.Percent) {
+			t.Errorf("Classes[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	if b.Holders[0].Label != holders.LabelBurn || b.Holders[2].Address != "0x00000000000000000000000000000000000000f1" {
+		t.Errorf("Holders not sorted largest first: %+v", b.Holders)
+	}
+
+	if holders.NewClassifier(chain.BSC, nil).Breakdown(holders.SourceGoPlus, nil, "1000") != nil {
+		t.Error("empty list should have no breakdown")
+	}
+}
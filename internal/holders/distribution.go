@@ -0,0 +1,157 @@
+package holders
+
+import (
+	"math/big"
+	"sort"
+)
+
+// DefaultTopN is the number of largest holders reported by default
+const DefaultTopN = 10
+
+// Holder is one address and its balance in base units
+type Holder struct {
+	Address string  `json:"address"`
+	Balance string  `json:"balance"`
+	Percent float64 `json:"percent"`         // Of circulating supply (excluded holders: of total supply)
+	Label   string  `json:"label,omitempty"` // Holder class (omitted for genuine wallets in top lists)
+	Name    string  `json:"name,omitempty"`  // Locker or exchange name, when known
+}
+
+// ClassShare is how much of the supply one holder class holds
+type ClassShare struct {
+	Label   string  `json:"label"`
+	Holders int     `json:"holders"`
+	Percent float64 `json:"percent"` // Of total supply
+}
+
+// Distribution summarises who holds a token
+type Distribution struct {
+	HolderCount        int          `json:"holder_count"`       // Genuine holders with a non-zero balance
+	TotalSupply        string       `json:"total_supply"`       // Sum of all balances
+	CirculatingSupply  string       `json:"circulating_supply"` // Total minus excluded balances
+	TopN               int          `json:"top_n"`
+	TopNConcentration  float64      `json:"top_n_concentration"` // Percent of circulating supply held by the TopN largest holders
+	Top10Concentration float64      `json:"top10_concentration"`
+	Gini               float64      `json:"gini"` // 0 = equal balances, 1 = one holder owns everything
+	TopHolders         []Holder     `json:"top_holders"`
+	Excluded           []Holder     `json:"excluded"`
+	Classes            []ClassShare `json:"classes"`
+
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+	Transfers int    `json:"transfers"`
+}
+
+// Compute builds a distribution from balances, leaving every address the
+// classifier does not consider a genuine wallet out of the metrics
+func Compute(balances map[string]*big.Int, classifier *Classifier, topN int) *Distribution {
+	if topN < 1 {
+		topN = DefaultTopN
+	}
+
+	total, circulating := new(big.Int), new(big.Int)
+	var genuine, excluded []Holder
+	var amounts []*big.Int
+	for addr, balance := range balances {
+		if balance.Sign() <= 0 {
+			continue
+		}
+		total.Add(total, balance)
+		if label, name := classifier.Classify(addr, "", false); label != LabelWallet {
+			excluded = append(excluded, Holder{Address: addr, Balance: balance.String(), Label: label, Name: name})
+			continue
+		}
+		circulating.Add(circulating, balance)
+		genuine = append(genuine, Holder{Address: addr, Balance: balance.String()})
+		amounts = append(amounts, balance)
+	}
+
+	// Largest first, ties broken by address for stable output
+	order := make([]int, len(genuine))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		if c := amounts[order[a]].Cmp(amounts[order[b]]); c != 0 {
+			return c > 0
+		}
+		return genuine[order[a]].Address < genuine[order[b]].Address
+	})
+
+	d := &Distribution{
+		HolderCount:       len(genuine),
+		TotalSupply:       total.String(),
+		CirculatingSupply: circulating.String(),
+		TopN:              topN,
+	}
+
+	topNSum, top10Sum := new(big.Int), new(big.Int)
+	for rank, i := range order {
+		if rank < topN {
+			h := genuine[i]
+			h.Percent = percent(amounts[i], circulating)
+			d.TopHolders = append(d.TopHolders, h)
+			topNSum.Add(topNSum, amounts[i])
+		}
+		if rank < 10 {
+			top10Sum.Add(top10Sum, amounts[i])
+		}
+	}
+	d.TopNConcentration = percent(topNSum, circulating)
+	d.Top10Concentration = percent(top10Sum, circulating)
+
+	for i := range excluded {
+		b, _ := new(big.Int).SetString(excluded[i].Balance, 10)
+		excluded[i].Percent = percent(b, total)
+	}
+	sort.Slice(excluded, func(a, b int) bool { return excluded[a].Percent > excluded[b].Percent })
+	d.Excluded = excluded
+	d.Classes = classShares(excluded, len(genuine), percent(circulating, total))
+
+	// Gini over ascending balances: 2*sum(i*x_i)/(n*sum(x)) - (n+1)/n
+	n := len(order)
+	if n > 0 && circulating.Sign() > 0 {
+		weighted := new(big.Int)
+		for rank, i := range order {
+			// order is descending, so the ascending index is n - rank
+			weighted.Add(weighted, new(big.Int).Mul(amounts[i], big.NewInt(int64(n-rank))))
+		}
+		num := new(big.Float).Mul(new(big.Float).SetInt(weighted), big.NewFloat(2))
+		den := new(big.Float).Mul(new(big.Float).SetInt(circulating), big.NewFloat(float64(n)))
+		g, _ := new(big.Float).Quo(num, den).Float64()
+		d.Gini = g - float64(n+1)/float64(n)
+	}
+
+	return d
+}
+
+// percent returns part/whole*100
+func percent(part, whole *big.Int) float64 {
+	if whole.Sign() == 0 {
+		return 0
+	}
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(part), new(big.Float).SetInt(whole)).Float64()
+	return f * 100
+}
+
+// classShares totals labelled holders per class. wallets and walletPercent
+// add genuine holders that are not in the list.
+func classShares(labelled []Holder, wallets int, walletPercent float64) []ClassShare {
+	var shares []ClassShare
+	for _, label := range Labels {
+		share := ClassShare{Label: label}
+		if label == LabelWallet {
+			share.Holders, share.Percent = wallets, walletPercent
+		}
+		for _, h := range labelled {
+			if h.Label == label {
+				share.Holders++
+				share.Percent += h.Percent
+			}
+		}
+		if share.Holders > 0 {
+			shares = append(shares, share)
+		}
+	}
+	return shares
+}
@@ -0,0 +1,115 @@
+package holders_test
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+const (
+	thin      = "0x00000000000000000000000000000000000000dd"
+	thinPair  = "0x00000000000000000000000000000000000000d1"
+	deployer  = "0x00000000000000000000000000000000000000e1"
+	createdAt = 44030000
+)
+
+func TestIndexerDistribution(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+
+	httpClient := transport.NewClient(transport.ProviderRPC, transport.Limits{Timeout: 5 * time.Second})
+	// Wider than the mock node accepts, so the indexer has to shrink its pages
+	ix := holders.NewIndexer(chain.BSC, rpc.NewClient(srv.RPCURL(), httpClient)).
+		WithBlockRange(4 * mockapi.MaxLogRange).
+		WithTopN(3)
+
+	d, err := ix.Distribution(context.Background(), thin, createdAt, []string{thinPair})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1M minted to the deployer: 600K to the pair (10K bought back out),
+	// 100K burned, 50K to PinkLock and 50K each to four wallets
+	if d.Transfers != 9 || d.ToBlock != mockapi.LatestBlock {
+		t.Errorf("indexed %d transfers up to block %d", d.Transfers, d.ToBlock)
+	}
+	if d.HolderCount != 6 {
+		t.Errorf("HolderCount = %d, want 6", d.HolderCount)
+	}
+	if want := tokens(260_000); d.CirculatingSupply != want {
+		t.Errorf("CirculatingSupply = %s, want %s", d.CirculatingSupply, want)
+	}
+	if want := tokens(1_000_000); d.TotalSupply != want {
+		t.Errorf("TotalSupply = %s, want %s", d.TotalSupply, want)
+	}
+	if !near(d.Top10Concentration, 100) || !near(d.TopNConcentration, 150.0/260*100) {
+		t.Errorf("Top10 = %.2f%%, Top3 = %.2f%%", d.Top10Concentration, d.TopNConcentration)
+	}
+	if len(d.TopHolders) != 3 || d.TopHolders[0].Address != deployer {
+		t.Errorf("TopHolders = %+v", d.TopHolders)
+	}
+	// Ascending balances 10, 50, 50, 50, 50, 50: 2*1010/(6*260) - 7/6
+	if !near(d.Gini, 2*1010.0/(6*260)-7.0/6) {
+		t.Errorf("Gini = %.4f", d.Gini)
+	}
+
+	wantExcluded := []struct {
+		label   string
+		percent float64
+	}{
+		{holders.LabelPair, 59},
+		{holders.LabelBurn, 10},
+		{holders.LabelLocker, 5},
+	}
+	if len(d.Excluded) != len(wantExcluded) {
+		t.Fatalf("Excluded = %+v", d.Excluded)
+	}
+	for i, want := range wantExcluded {
+		if got := d.Excluded[i]; got.Label != want.label || !near(got.Percent, want.percent) {
+			t.Errorf("Excluded[%d] = %s %.2f%%, want %s %.2f%%", i, got.Label, got.Percent, want.label, want.percent)
+		}
+	}
+	if name := d.Excluded[2].Name; name != "PinkLock" {
+		t.Errorf("locker name = %q, want PinkLock", name)
+	}
+
+	// Classes in reporting order, genuine wallets last
+	if len(d.Classes) != 4 || d.Classes[0].Label != holders.LabelBurn ||
+		d.Classes[3].Label != holders.LabelWallet || d.Classes[3].Holders != 6 || !near(d.Classes[3].Percent, 26) {
+		t.Errorf("Classes = %+v", d.Classes)
+	}
+}
+
+func TestComputeGini(t *testing.T) {
+	equal := map[string]*big.Int{
+		"0x01": big.NewInt(100), "0x02": big.NewInt(100), "0x03": big.NewInt(100), "0x04": big.NewInt(100),
+	}
+	if d := holders.Compute(equal, nil, 10); !near(d.Gini, 0) || !near(d.Top10Concentration, 100) {
+		t.Errorf("equal balances: Gini = %.4f, Top10 = %.2f%%", d.Gini, d.Top10Concentration)
+	}
+
+	// One whale among 99 dust holders
+	skewed := map[string]*big.Int{"0xwhale": big.NewInt(1_000_000)}
+	for i := range 99 {
+		skewed[big.NewInt(int64(i)).String()] = big.NewInt(1)
+	}
+	if d := holders.Compute(skewed, nil, 1); d.Gini < 0.95 || d.TopNConcentration < 99.9 {
+		t.Errorf("whale: Gini = %.4f, Top1 = %.2f%%", d.Gini, d.TopNConcentration)
+	}
+}
+
+func tokens(n int64) string {
+	return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)).String()
+}
+
+func near(got, want float64) bool {
+	return math.Abs(got-want) < 0.01
+}
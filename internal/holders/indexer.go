@@ -0,0 +1,157 @@
+package holders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+)
+
+// TransferTopic is keccak256("Transfer(address,address,uint256)")
+const TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+// DefaultBlockRange is the eth_getLogs page size; public BSC and Ethereum
+// endpoints accept at least this many blocks per request
+const DefaultBlockRange = 5000
+
+// Indexer replays a token's Transfer logs over JSON-RPC
+type Indexer struct {
+	chain      chain.Chain
+	client     *rpc.Client
+	blockRange uint64
+	topN       int
+}
+
+// NewIndexer creates an indexer for tokens on ch
+func NewIndexer(ch chain.Chain, client *rpc.Client) *Indexer {
+	return &Indexer{
+		chain:      ch,
+		client:     client,
+		blockRange: DefaultBlockRange,
+		topN:       DefaultTopN,
+	}
+}
+
+// WithBlockRange sets the initial eth_getLogs page size in blocks
+func (ix *Indexer) WithBlockRange(blocks uint64) *Indexer {
+	if blocks > 0 {
+		ix.blockRange = blocks
+	}
+	return ix
+}
+
+// WithTopN sets how many of the largest holders are reported
+func (ix *Indexer) WithTopN(n int) *Indexer {
+	if n > 0 {
+		ix.topN = n
+	}
+	return ix
+}
+
+// Distribution indexes token from fromBlock (its creation block) to the
+// latest block and computes the distribution, excluding burn and locker
+// addresses and the given DEX pairs
+func (ix *Indexer) Distribution(ctx context.Context, token string, fromBlock uint64, pairs []string) (*Distribution, error) {
+	balances, toBlock, transfers, err := ix.Balances(ctx, token, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	d := Compute(balances, NewClassifier(ix.chain, pairs), ix.topN)
+	d.FromBlock, d.ToBlock, d.Transfers = fromBlock, toBlock, transfers
+	return d, nil
+}
+
+// Balances replays every Transfer of token from fromBlock to the latest
+// block. It returns the resulting balances, the last indexed block and the
+// number of transfers applied.
+func (ix *Indexer) Balances(ctx context.Context, token string, fromBlock uint64) (map[string]*big.Int, uint64, int, error) {
+	latest, err := ix.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("latest block: %w", err)
+	}
+	if fromBlock > latest {
+		return nil, 0, 0, fmt.Errorf("start block %d is after latest block %d", fromBlock, latest)
+	}
+
+	balances := map[string]*big.Int{}
+	transfers := 0
+	pageSize := ix.blockRange
+
+	for from := fromBlock; from <= latest; {
+		to := min(from+pageSize-1, latest)
+
+		logs, err := ix.client.GetLogs(ctx, rpc.LogFilter{
+			FromBlock: from,
+			ToBlock:   to,
+			Addresses: []string{token},
+			Topics:    []string{TransferTopic},
+		})
+		if err != nil {
+			// Nodes reject ranges (or result sets) that are too large: halve and retry
+			var rpcErr *rpc.Error
+			if errors.As(err, &rpcErr) && pageSize > 1 {
+				pageSize /= 2
+				continue
+			}
+			return nil, 0, 0, fmt.Errorf("eth_getLogs %d-%d: %w", from, to, err)
+		}
+
+		for _, l := range logs {
+			if l.Removed {
+				continue
+			}
+			fromAddr, toAddr, value, ok := decodeTransfer(l)
+			if !ok {
+				continue
+			}
+			debit(balances, fromAddr, value)
+			credit(balances, toAddr, value)
+			transfers++
+		}
+
+		from = to + 1
+	}
+
+	// Mints come from the zero address; its negative balance is not a holding
+	delete(balances, "0x0000000000000000000000000000000000000000")
+	return balances, latest, transfers, nil
+}
+
+// decodeTransfer extracts from, to and value. ERC-20 puts the value in data;
+// some tokens index it as a third topic.
+func decodeTransfer(l rpc.Log) (from, to string, value *big.Int, ok bool) {
+	if len(l.Topics) < 3 || !strings.EqualFold(l.Topics[0], TransferTopic) {
+		return "", "", nil, false
+	}
+	raw := l.Data
+	if len(l.Topics) == 4 {
+		raw = l.Topics[3]
+	}
+	words, err := rpc.Words(raw)
+	if err != nil || len(words) == 0 {
+		return "", "", nil, false
+	}
+	value, err = rpc.DecodeUint(words[0])
+	if err != nil {
+		return "", "", nil, false
+	}
+	return rpc.DecodeAddress(l.Topics[1]), rpc.DecodeAddress(l.Topics[2]), value, true
+}
+
+func credit(balances map[string]*big.Int, addr string, value *big.Int) {
+	b, ok := balances[addr]
+	if !ok {
+		b = new(big.Int)
+		balances[addr] = b
+	}
+	b.Add(b, value)
+}
+
+func debit(balances map[string]*big.Int, addr string, value *big.Int) {
+	credit(balances, addr, new(big.Int).Neg(value))
+}
@@ -0,0 +1,184 @@
+package liquidity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RiskFactor is raised when a window's drop reaches its warn level
+const RiskFactor = "liquidity_drain"
+
+// Window is a lookback period and the liquidity drops that trigger over it
+type Window struct {
+	Period    time.Duration `yaml:"period" json:"period"`
+	WarnPct   float64       `yaml:"warn_pct" json:"warn_pct"`     // Drop that raises RiskFactor; 0 = never
+	RejectPct float64       `yaml:"reject_pct" json:"reject_pct"` // Drop that rejects the token; 0 = never
+}
+
+// MarshalYAML writes the period as "24h" rather than nanoseconds
+func (w Window) MarshalYAML() (any, error) {
+	return struct {
+		Period    string  `yaml:"period"`
+		WarnPct   float64 `yaml:"warn_pct"`
+		RejectPct float64 `yaml:"reject_pct"`
+	}{w.Period.String(), w.WarnPct, w.RejectPct}, nil
+}
+
+// Policy configures drain detection
+type Policy struct {
+	Enabled bool     `yaml:"enabled"`
+	Windows []Window `yaml:"windows"`
+}
+
+// DefaultPolicy watches 1h, 24h and 7d drops. The 24h warn level is
+// plan.txt's 30%.
+func DefaultPolicy() Policy {
+	return Policy{
+		Enabled: true,
+		Windows: []Window{
+			{Period: time.Hour, WarnPct: 20, RejectPct: 50},
+			{Period: 24 * time.Hour, WarnPct: 30, RejectPct: 60},
+			{Period: 7 * 24 * time.Hour, WarnPct: 50, RejectPct: 80},
+		},
+	}
+}
+
+// Validate checks periods and drop levels
+func (p Policy) Validate() error {
+	var errs []string
+	for _, w := range p.Windows {
+		label := formatPeriod(w.Period)
+		if w.Period <= 0 {
+			errs = append(errs, fmt.Sprintf("window %s: period must be positive", label))
+		}
+		if w.WarnPct < 0 || w.WarnPct > 100 || w.RejectPct < 0 || w.RejectPct > 100 {
+			errs = append(errs, fmt.Sprintf("window %s: drop levels must be within 0-100", label))
+		}
+		if w.WarnPct > 0 && w.RejectPct > 0 && w.WarnPct > w.RejectPct {
+			errs = append(errs, fmt.Sprintf("window %s: warn_pct %g is above reject_pct %g", label, w.WarnPct, w.RejectPct))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Lookback is the history Assess can use: twice the longest window
+func (p Policy) Lookback() time.Duration {
+	var longest time.Duration
+	for _, w := range p.Windows {
+		longest = max(longest, w.Period)
+	}
+	return 2 * longest
+}
+
+// Delta is the change in a token's total liquidity over one window
+type Delta struct {
+	Window    string    `json:"window"` // "1h", "24h", "7d"
+	FromUSD   float64   `json:"from_usd"`
+	ToUSD     float64   `json:"to_usd"`
+	ChangePct float64   `json:"change_pct"` // Negative for a drop
+	Since     time.Time `json:"since"`      // Cycle the change is measured from
+}
+
+// Assessment is the drain verdict over every window with enough history
+type Assessment struct {
+	Deltas  []Delta `json:"deltas"`
+	Warn    bool    `json:"warn"`
+	Reject  bool    `json:"reject"`
+	Message string  `json:"message,omitempty"` // The worst triggered drop
+}
+
+// Detector assesses liquidity history against a policy
+type Detector struct {
+	policy Policy
+}
+
+// NewDetector creates a detector applying policy's windows
+func NewDetector(policy Policy) *Detector {
+	return &Detector{policy: policy}
+}
+
+// cycle is a token's total liquidity at one screening cycle
+type cycle struct {
+	at           time.Time
+	liquidityUSD float64
+}
+
+// Assess measures each window from the latest cycle back to the newest
+// cycle at least a window old. Windows without such a cycle, or whose
+// cycle is more than twice the window old, are skipped: a 1h window over
+// daily cycles would really be measuring a day.
+func (d *Detector) Assess(history []Snapshot) *Assessment {
+	cycles := totals(history)
+	a := &Assessment{}
+	if len(cycles) < 2 {
+		return a
+	}
+	current := cycles[len(cycles)-1]
+
+	var worst float64
+	for _, w := range d.policy.Windows {
+		cutoff := current.at.Add(-w.Period)
+		i := sort.Search(len(cycles), func(i int) bool { return cycles[i].at.After(cutoff) }) - 1
+		if i < 0 || cycles[i].liquidityUSD <= 0 || current.at.Sub(cycles[i].at) > 2*w.Period {
+			continue
+		}
+		base := cycles[i]
+		delta := Delta{
+			Window:    formatPeriod(w.Period),
+			FromUSD:   base.liquidityUSD,
+			ToUSD:     current.liquidityUSD,
+			ChangePct: (current.liquidityUSD - base.liquidityUSD) / base.liquidityUSD * 100,
+			Since:     base.at,
+		}
+		a.Deltas = append(a.Deltas, delta)
+
+		drop := -delta.ChangePct
+		reject := w.RejectPct > 0 && drop >= w.RejectPct
+		warn := w.WarnPct > 0 && drop >= w.WarnPct
+		if !warn && !reject {
+			continue
+		}
+		// A reject outranks any warning; otherwise the larger drop wins
+		if (reject && !a.Reject) || (reject == a.Reject && drop > worst) {
+			worst = drop
+			a.Message = fmt.Sprintf("Liquidity drained: %.1f%% over %s ($%.0f -> $%.0f)",
+				delta.ChangePct, delta.Window, delta.FromUSD, delta.ToUSD)
+		}
+		a.Reject = a.Reject || reject
+		a.Warn = true
+	}
+	return a
+}
+
+// totals sums each cycle's pairs, oldest first
+func totals(history []Snapshot) []cycle {
+	byTime := map[time.Time]float64{}
+	for _, s := range history {
+		byTime[s.At.UTC()] += s.LiquidityUSD
+	}
+	cycles := make([]cycle, 0, len(byTime))
+	for at, liquidity := range byTime {
+		cycles = append(cycles, cycle{at: at, liquidityUSD: liquidity})
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i].at.Before(cycles[j].at) })
+	return cycles
+}
+
+// formatPeriod renders multi-day periods as "7d", whole hours as "24h" and
+// other periods as time.Duration does
+func formatPeriod(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d > 0 && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return d.String()
+	}
+}
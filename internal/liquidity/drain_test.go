@@ -0,0 +1,116 @@
+package liquidity_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/liquidity"
+)
+
+const token = "0x00000000000000000000000000000000000000aa"
+
+var now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// series records one cycle per entry, hoursAgo before now, with the
+// liquidity split over two pairs
+func series(t *testing.T, points map[float64]float64) []liquidity.Snapshot {
+	t.Helper()
+	store := liquidity.NewMemoryStore()
+	for hoursAgo, liquidityUSD := range points {
+		at := now.Add(-time.Duration(hoursAgo * float64(time.Hour)))
+		err := store.SaveSnapshots(context.Background(), []liquidity.Snapshot{
+			{Chain: "bsc", Token: token, Pair: "0x01", LiquidityUSD: liquidityUSD * 0.75, At: at},
+			{Chain: "bsc", Token: token, Pair: "0x02", LiquidityUSD: liquidityUSD * 0.25, At: at},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err := store.History(context.Background(), "bsc", strings.ToUpper(token), now.Add(-liquidity.DefaultPolicy().Lookback()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return history
+}
+
+func TestAssess(t *testing.T) {
+	detector := liquidity.NewDetector(liquidity.DefaultPolicy())
+
+	tests := []struct {
+		name    string
+		points  map[float64]float64 // Hours ago -> total liquidity
+		windows []string
+		warn    bool
+		reject  bool
+		message string
+	}{
+		{
+			name:    "steady",
+			points:  map[float64]float64{0: 1_000_000, 1: 1_010_000, 24: 990_000, 168: 950_000},
+			windows: []string{"1h", "24h", "7d"},
+		},
+		{
+			name:    "24h drain warns",
+			points:  map[float64]float64{0: 650_000, 1: 660_000, 24: 1_000_000},
+			windows: []string{"1h", "24h"},
+			warn:    true,
+			message: "Liquidity drained: -35.0% over 24h ($1000000 -> $650000)",
+		},
+		{
+			name:    "pulled LP rejects",
+			points:  map[float64]float64{0: 200_000, 1.5: 1_000_000, 24: 1_000_000},
+			windows: []string{"1h", "24h"},
+			warn:    true,
+			reject:  true,
+			message: "Liquidity drained: -80.0% over 1h ($1000000 -> $200000)",
+		},
+		{
+			name:    "slow 7d bleed",
+			points:  map[float64]float64{0: 400_000, 30: 420_000, 170: 1_000_000},
+			windows: []string{"24h", "7d"},
+			warn:    true,
+			message: "Liquidity drained: -60.0% over 7d ($1000000 -> $400000)",
+		},
+		{
+			// Daily cycles say nothing about the last hour
+			name:    "stale cycles skipped",
+			points:  map[float64]float64{0: 1_000_000, 30: 1_000_000},
+			windows: []string{"24h"},
+		},
+		{
+			name:   "single cycle",
+			points: map[float64]float64{0: 1_000_000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := detector.Assess(series(t, tt.points))
+
+			var windows []string
+			for _, d := range a.Deltas {
+				windows = append(windows, d.Window)
+			}
+			if strings.Join(windows, ",") != strings.Join(tt.windows, ",") {
+				t.Errorf("windows = %v, want %v", windows, tt.windows)
+			}
+			if a.Warn != tt.warn || a.Reject != tt.reject || a.Message != tt.message {
+				t.Errorf("assessment = warn %t reject %t %q, want warn %t reject %t %q",
+					a.Warn, a.Reject, a.Message, tt.warn, tt.reject, tt.message)
+			}
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	if err := liquidity.DefaultPolicy().Validate(); err != nil {
+		t.Fatalf("default policy: %v", err)
+	}
+	bad := liquidity.Policy{Windows: []liquidity.Window{{Period: 0, WarnPct: 10}, {Period: time.Hour, WarnPct: 60, RejectPct: 40}}}
+	err := bad.Validate()
+	if err == nil || !strings.Contains(err.Error(), "period must be positive") || !strings.Contains(err.Error(), "warn_pct 60 is above reject_pct 40") {
+		t.Errorf("Validate = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Package liquidity records per-pair liquidity, volume and price on every
+// screening cycle and detects liquidity being pulled from a token's pairs
+// (plan.txt: flag a >30% drop in 24h).
+package liquidity
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Snapshot is one pair's market state at one screening cycle. Every pair
+// of a token recorded in the same cycle shares At.
+type Snapshot struct {
+	Chain        string    `json:"chain"`
+	Token        string    `json:"token"` // Lowercase token address
+	Pair         string    `json:"pair"`  // Lowercase pair address
+	DexID        string    `json:"dex_id"`
+	Quote        string    `json:"quote"` // Quote asset symbol
+	LiquidityUSD float64   `json:"liquidity_usd"`
+	Volume24hUSD float64   `json:"volume_24h"`
+	PriceUSD     float64   `json:"price_usd"`
+	At           time.Time `json:"at"`
+}
+
+// Store persists snapshots. storage.Store implements it on Postgres.
+type Store interface {
+	SaveSnapshots(ctx context.Context, snapshots []Snapshot) error
+	// History returns a token's snapshots taken at or after since, oldest first
+	History(ctx context.Context, chain, token string, since time.Time) ([]Snapshot, error)
+}
+
+// MemoryStore keeps snapshots in process, for runs without a database and
+// for tests with synthetic series
+type MemoryStore struct {
+	mu        sync.RWMutex
+	snapshots map[string][]Snapshot // Keyed by "chain/token"
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: make(map[string][]Snapshot)}
+}
+
+func (m *MemoryStore) SaveSnapshots(ctx context.Context, snapshots []Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range snapshots {
+		key := s.Chain + "/" + strings.ToLower(s.Token)
+		m.snapshots[key] = append(m.snapshots[key], s)
+	}
+	return nil
+}
+
+func (m *MemoryStore) History(ctx context.Context, chain, token string, since time.Time) ([]Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var history []Snapshot
+	for _, s := range m.snapshots[chain+"/"+strings.ToLower(token)] {
+		if !s.At.Before(since) {
+			history = append(history, s)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].At.Before(history[j].At) })
+	return history, nil
+}
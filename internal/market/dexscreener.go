@@ -2,82 +2,180 @@
 package market
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
 	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
 )
 
 type DexScreenerClient struct {
-	baseURL    string
-	httpClient *http.Client
+	chain       chain.Chain
+	baseURL     string
+	httpClient  *transport.Client
+	quoteAssets []chain.Token
+	cache       *cache.Cache
 }
 
-func NewDexScreenerClient() *DexScreenerClient {
+// NewDexScreenerClient creates a client for pairs on ch, aggregating the
+// chain's default quote assets. A nil httpClient uses the shared default transport.
+func NewDexScreenerClient(ch chain.Chain, httpClient *transport.Client) *DexScreenerClient {
+	if httpClient == nil {
+		httpClient = transport.Default.For(transport.ProviderDexScreener)
+	}
 	return &DexScreenerClient{
-		baseURL:    "https://api.dexscreener.com",
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		chain:       ch,
+		baseURL:     "https://api.dexscreener.com",
+		httpClient:  httpClient,
+		quoteAssets: ch.DefaultQuoteAssets(),
 	}
 }
 
-// GetPairMetrics fetches liquidity and volume data
-func (d *DexScreenerClient) GetPairMetrics(address string) (liquidity, volume float64, isFragmentationSafe bool, largestSingleLiquidityPoolAgeDays float64, err error) {
-	url := fmt.Sprintf("%s/token-pairs/v1/bsc/%s", d.baseURL, address)
+// WithBaseURL points the client at a different API host (e.g. a test server)
+func (d *DexScreenerClient) WithBaseURL(baseURL string) *DexScreenerClient {
+	if baseURL != "" {
+		d.baseURL = baseURL
+	}
+	return d
+}
 
-	resp, err := d.httpClient.Get(url)
-	if err != nil {
-		return 0, 0, false, 0, err
+// WithCache serves pair lookups from c while they are fresh
+func (d *DexScreenerClient) WithCache(c *cache.Cache) *DexScreenerClient {
+	d.cache = c
+	return d
+}
+
+// PairMetrics is the market data aggregated across all supported quote pairs
+type PairMetrics struct {
+	LiquidityUSD        float64          // Sum of USD liquidity across supported pairs
+	Volume24h           float64          // Sum of 24h volume across supported pairs
+	IsFragmentationSafe bool             // Liquidity concentrated in one pool (or deep enough not to matter)
+	FragmentationBasis  string           // Why IsFragmentationSafe was decided as it was
+	LargestPoolAgeDays  float64          // Age of the largest single liquidity pool
+	LargestPoolQuote    string           // Quote symbol of the largest pool
+	Quotes              []QuoteLiquidity // Per-quote breakdown, in configured quote order
+	PairAddresses       []string         // Every pair holding the token, any quote (lowercase)
+	Pairs               []PairMarket     // The pairs counted in LiquidityUSD
+}
+
+// WithQuoteAssets sets the quote assets whose pairs are aggregated. An
+// empty list keeps the defaults.
+func (d *DexScreenerClient) WithQuoteAssets(assets []chain.Token) *DexScreenerClient {
+	if len(assets) > 0 {
+		d.quoteAssets = assets
 	}
-	defer resp.Body.Close()
+	return d
+}
 
-	body, _ := io.ReadAll(resp.Body)
+// GetMarket fetches the token's pairs and aggregates those quoted in any of
+// the configured quote assets, keeping a per-quote breakdown
+func (d *DexScreenerClient) GetMarket(ctx context.Context, address string) (*PairMetrics, error) {
+	url := fmt.Sprintf("%s/token-pairs/v1/%s/%s", d.baseURL, d.chain.DexScreenerSlug, address)
+
+	key := cache.Key{Provider: transport.ProviderDexScreener, BaseURL: d.baseURL, Endpoint: cache.Liquidity, Chain: d.chain.ID, Address: address}
+	body, err := d.cache.Get(ctx, d.httpClient, key, url, cache.ValidJSON)
+	if err != nil {
+		return nil, err
+	}
 
 	var pairs []models.DexScreenerPair
 	if err := json.Unmarshal(body, &pairs); err != nil {
-		return 0, 0, false, 0, err
+		return nil, err
 	}
 
 	if len(pairs) == 0 {
-		return 0, 0, false, 0, fmt.Errorf("no DEXScreener pairs found for token %s", address)
+		return nil, fmt.Errorf("no DEXScreener pairs found for token %s", address)
+	}
+
+	// Bucket pairs by quote asset (case-insensitive address comparison)
+	quotes := make([]QuoteLiquidity, len(d.quoteAssets))
+	for i, asset := range d.quoteAssets {
+		quotes[i] = QuoteLiquidity{Symbol: asset.Symbol, Address: asset.Address}
 	}
 
-	// Filter to keep only USDT pairs (case-insensitive address comparison)
-	const usdtAddress = "0x55d398326f99059ff775485246999027b3197955"
-	usdtPairs := make([]models.DexScreenerPair, 0)
+	m := &PairMetrics{}
+	var largestSingleLiquidityPool models.DexScreenerPair
+	largestLiquidity := 0.0
+	matched := 0
 	for _, pair := range pairs {
-		if strings.EqualFold(pair.QuoteToken.Address, usdtAddress) {
-			usdtPairs = append(usdtPairs, pair)
+		m.PairAddresses = append(m.PairAddresses, strings.ToLower(pair.PairAddress))
+
+		i := d.quoteIndex(pair.QuoteToken.Address)
+		if i < 0 {
+			continue
 		}
+		matched++
+
+		liquidityUSD := pairLiquidityUSD(pair)
+		quotes[i].Pairs++
+		quotes[i].LiquidityUSD += liquidityUSD
+		quotes[i].Volume24h += pair.Volume.H24
+
+		if liquidityUSD > largestLiquidity {
+			largestLiquidity = liquidityUSD
+			largestSingleLiquidityPool = pair
+			m.LargestPoolQuote = quotes[i].Symbol
+		}
+		m.LiquidityUSD += liquidityUSD
+		m.Volume24h += pair.Volume.H24
+
+		priceUSD, _ := strconv.ParseFloat(pair.PriceUSD, 64)
+		m.Pairs = append(m.Pairs, PairMarket{
+			Address:      strings.ToLower(pair.PairAddress),
+			DexID:        pair.DexID,
+			Quote:        quotes[i].Symbol,
+			LiquidityUSD: liquidityUSD,
+			Volume24h:    pair.Volume.H24,
+			PriceUSD:     priceUSD,
+		})
 	}
 
-	if len(usdtPairs) == 0 {
-		return 0, 0, false, 0, fmt.Errorf("no USDT pairs found for token %s", address)
+	if matched == 0 {
+		return nil, fmt.Errorf("no supported quote pairs (%s) found for token %s", d.quoteSymbols(), address)
 	}
 
-	largestSingleLiquidityPool := models.DexScreenerPair{}
-	// Aggregate liquidity and volume across all pairs
-	for _, pair := range usdtPairs {
-		if pair.Liquidity.USD > largestSingleLiquidityPool.Liquidity.USD {
-			largestSingleLiquidityPool = pair
+	for _, q := range quotes {
+		if q.Pairs > 0 {
+			m.Quotes = append(m.Quotes, q)
 		}
-		liquidity += pair.Liquidity.USD
-		volume += pair.Volume.H24
 	}
 
-	if liquidity > 2_000_000 { // If >$10M total, fragmentation OK
-		isFragmentationSafe = true
-	} else if largestSingleLiquidityPool.Liquidity.USD >= 0.5*liquidity {
-		isFragmentationSafe = true
+	share := largestLiquidity / m.LiquidityUSD * 100
+	if m.LiquidityUSD > 2_000_000 { // If >$2M total, fragmentation OK
+		m.IsFragmentationSafe = true
+		m.FragmentationBasis = fmt.Sprintf("total liquidity $%.0f > $2M", m.LiquidityUSD)
+	} else if largestLiquidity >= 0.5*m.LiquidityUSD {
+		m.IsFragmentationSafe = true
+		m.FragmentationBasis = fmt.Sprintf("largest pair holds %.0f%% of liquidity", share)
+	} else {
+		m.FragmentationBasis = fmt.Sprintf("largest pair holds only %.0f%% of liquidity", share)
 	}
 
-	// fmt.Printf("DEBUG: PairCreatedAt raw: %d\n", largestSingleLiquidityPool.PairCreatedAt)
-	// fmt.Printf("DEBUG: Converted to time: %v\n", time.Unix(largestSingleLiquidityPool.PairCreatedAt/1000, 0))
 	// Age of largest single liquidity pool in days
-	largestSingleLiquidityPoolAgeDays = time.Since(time.Unix(largestSingleLiquidityPool.PairCreatedAt/1000, 0)).Hours() / 24
+	m.LargestPoolAgeDays = time.Since(time.Unix(largestSingleLiquidityPool.PairCreatedAt/1000, 0)).Hours() / 24
 
-	return liquidity, volume, isFragmentationSafe, largestSingleLiquidityPoolAgeDays, nil
+	return m, nil
+}
+
+func (d *DexScreenerClient) quoteIndex(address string) int {
+	for i, asset := range d.quoteAssets {
+		if strings.EqualFold(asset.Address, address) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *DexScreenerClient) quoteSymbols() string {
+	symbols := make([]string, len(d.quoteAssets))
+	for i, asset := range d.quoteAssets {
+		symbols[i] = asset.Symbol
+	}
+	return strings.Join(symbols, "/")
 }
@@ -0,0 +1,126 @@
+package market_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/market"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+func newTestClient(srv *mockapi.Server) *market.DexScreenerClient {
+	httpClient := transport.NewClient(transport.ProviderDexScreener, transport.Limits{Timeout: 5 * time.Second})
+	return market.NewDexScreenerClient(chain.BSC, httpClient).WithBaseURL(srv.DexScreenerURL())
+}
+
+func TestGetMarketAggregatesQuoteAssets(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	// USDT pair ($300k) + WBNB pair without liquidity.usd, normalized from
+	// priceUsd/priceNative to $600k. The XYZ-quoted pair is ignored.
+	m, err := client.GetMarket(context.Background(), "0x00000000000000000000000000000000000000bb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if math.Abs(m.LiquidityUSD-900_000) > 1e-6 || m.Volume24h != 130_000 {
+		t.Errorf("liquidity = %.2f, volume = %.2f, want 900000 and 130000", m.LiquidityUSD, m.Volume24h)
+	}
+	if m.LargestPoolQuote != "WBNB" || !m.IsFragmentationSafe || m.FragmentationBasis != "largest pair holds 67% of liquidity" {
+		t.Errorf("largest pool = %s, fragmentation safe = %t (%s)", m.LargestPoolQuote, m.IsFragmentationSafe, m.FragmentationBasis)
+	}
+
+	want := []market.QuoteLiquidity{
+		{Symbol: "WBNB", Address: "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c", Pairs: 1, LiquidityUSD: 600_000, Volume24h: 80_000},
+		{Symbol: "USDT", Address: "0x55d398326f99059ff775485246999027b3197955", Pairs: 1, LiquidityUSD: 300_000, Volume24h: 50_000},
+	}
+	if len(m.Quotes) != len(want) {
+		t.Fatalf("quotes = %+v, want %+v", m.Quotes, want)
+	}
+	for i := range want {
+		got := m.Quotes[i]
+		if got.Symbol != want[i].Symbol || got.Address != want[i].Address || got.Pairs != want[i].Pairs ||
+			math.Abs(got.LiquidityUSD-want[i].LiquidityUSD) > 1e-6 || got.Volume24h != want[i].Volume24h {
+			t.Errorf("quote %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// Restricting quotes to USDT drops the WBNB market
+	usdtOnly, err := market.ParseQuoteAssets(chain.BSC, []string{"USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err = client.WithQuoteAssets(usdtOnly).GetMarket(context.Background(), "0x00000000000000000000000000000000000000bb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.LiquidityUSD != 300_000 || len(m.Quotes) != 1 {
+		t.Errorf("USDT-only liquidity = %.2f over %d quotes", m.LiquidityUSD, len(m.Quotes))
+	}
+}
+
+func TestGetMarketNoSupportedQuote(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+
+	_, err := newTestClient(srv).GetMarket(context.Background(), "0x00000000000000000000000000000000000000bc")
+	if err == nil || !strings.Contains(err.Error(), "no supported quote pairs") {
+		t.Errorf("err = %v, want no supported quote pairs", err)
+	}
+}
+
+func TestGetMarketUsesChainSlug(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(`[{"quoteToken":{"address":"0x4200000000000000000000000000000000000006","symbol":"WETH"},"liquidity":{"usd":5000},"volume":{"h24":100}}]`))
+	}))
+	defer srv.Close()
+
+	httpClient := transport.NewClient(transport.ProviderDexScreener, transport.Limits{Timeout: 5 * time.Second})
+	m, err := market.NewDexScreenerClient(chain.Base, httpClient).WithBaseURL(srv.URL).
+		GetMarket(context.Background(), "0x00000000000000000000000000000000000000bb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/token-pairs/v1/base/0x00000000000000000000000000000000000000bb" {
+		t.Errorf("requested %s", gotPath)
+	}
+	if len(m.Quotes) != 1 || m.Quotes[0].Symbol != "WETH" || m.LiquidityUSD != 5000 {
+		t.Errorf("Base market = %+v", m)
+	}
+}
+
+func TestParseQuoteAssets(t *testing.T) {
+	assets, err := market.ParseQuoteAssets(chain.BSC, []string{"wbnb", " USDT ", "CAKE=0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82", "WBNB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assets) != 3 || assets[0].Symbol != "WBNB" || assets[2].Address != "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82" {
+		t.Errorf("assets = %+v", assets)
+	}
+
+	for _, bad := range [][]string{{"DOGE"}, {"X=0x123"}, {"WETH"}} {
+		if _, err := market.ParseQuoteAssets(chain.BSC, bad); err == nil {
+			t.Errorf("ParseQuoteAssets(%q) succeeded", bad)
+		}
+	}
+
+	// No specs selects the chain defaults: wrapped native, then stablecoins
+	defaults, err := market.ParseQuoteAssets(chain.Base, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defaults) != 4 || defaults[0].Symbol != "WETH" || defaults[1].Symbol != "USDC" {
+		t.Errorf("Base defaults = %+v", defaults)
+	}
+}
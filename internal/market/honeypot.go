@@ -2,19 +2,22 @@
 package market
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"strconv"
-	"time"
 
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
 	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
 )
 
 type HoneyPotClient struct {
+	chain      chain.Chain
 	baseURL    string
-	httpClient *http.Client
+	httpClient *transport.Client
+	cache      *cache.Cache
 }
 
 type TopTokenHoldersResponse struct {
@@ -22,71 +25,82 @@ type TopTokenHoldersResponse struct {
 	Holders     []models.HoneyPotHolder `json:"holders"`
 }
 
-type TokenCreationResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Result  []struct {
-		BlockNumber     string `json:"blockNumber"`
-		TimeStamp       string `json:"timestamp"`
-		ContractCreator string `json:"contractCreator"`
-	} `json:"result"`
+// NewHoneyPotClient creates a client for tokens on ch. A nil httpClient uses the shared default transport.
+func NewHoneyPotClient(ch chain.Chain, httpClient *transport.Client) *HoneyPotClient {
+	if httpClient == nil {
+		httpClient = transport.Default.For(transport.ProviderHoneypot)
+	}
+	return &HoneyPotClient{
+		chain:      ch,
+		baseURL:    "https://api.honeypot.is",
+		httpClient: httpClient,
+	}
 }
 
-// APIErrorResponse is used when the API returns an error (result is a string)
-type APIErrorResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Result  string `json:"result"`
+// WithBaseURL points the client at a different API host (e.g. a test server)
+func (c *HoneyPotClient) WithBaseURL(baseURL string) *HoneyPotClient {
+	if baseURL != "" {
+		c.baseURL = baseURL
+	}
+	return c
 }
 
-func NewHoneyPotClient() *HoneyPotClient {
-	return &HoneyPotClient{
-		baseURL:    "https://api.honeypot.is",
-		httpClient: &http.Client{Timeout: 5 * time.Second},
-	}
+// WithCache serves top holder lookups from rc while they are fresh
+func (c *HoneyPotClient) WithCache(rc *cache.Cache) *HoneyPotClient {
+	c.cache = rc
+	return c
 }
 
-func (c *HoneyPotClient) GetTop10HoldersConcentration(contractAddress string) (float64, error) {
-	url := fmt.Sprintf("%s/v1/TopHolders?address=%s&chainID=56", c.baseURL, contractAddress)
+// GetTopHolders fetches the token's largest holders
+func (c *HoneyPotClient) GetTopHolders(ctx context.Context, contractAddress string) (*TopTokenHoldersResponse, error) {
+	url := fmt.Sprintf("%s/v1/TopHolders?address=%s&chainID=%d", c.baseURL, contractAddress, c.chain.ID)
 
-	resp, err := c.httpClient.Get(url)
+	key := cache.Key{Provider: transport.ProviderHoneypot, BaseURL: c.baseURL, Endpoint: cache.TopHolders, Chain: c.chain.ID, Address: contractAddress}
+	body, err := c.cache.Get(ctx, c.httpClient, key, url, cache.ValidJSON)
 	if err != nil {
-		fmt.Println("Error fetching token holders:", err)
-		return 0, err
+		return nil, fmt.Errorf("fetching top holders: %w", err)
 	}
 
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
 	var result TopTokenHoldersResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		fmt.Println("Error unmarshaling response:", err)
-		return 0, err
-	}
-
-	if len(result.Holders) == 0 {
-		return 0, nil
+		return nil, fmt.Errorf("decoding top holders: %w", err)
 	}
+	return &result, nil
+}
 
-	var totalTop10Balance float64
-	for _, holder := range result.Holders {
-		balance, err := strconv.ParseFloat(holder.Balance, 64)
-		if err != nil {
-			fmt.Println("Error parsing top 10 holder balance:", err)
-		}
-		totalTop10Balance += balance
+// Reported converts the list for holder classification
+func (r *TopTokenHoldersResponse) Reported() []holders.ReportedHolder {
+	reported := make([]holders.ReportedHolder, 0, len(r.Holders))
+	for _, h := range r.Holders {
+		reported = append(reported, holders.ReportedHolder{Address: h.Address, Balance: h.Balance, Alias: h.Alias})
 	}
+	return reported
+}
 
-	totalsupply, err := strconv.ParseFloat(result.TotalSupply, 64)
+// GetHolderBreakdown classifies the token's top holders, excluding the
+// given DEX pairs along with burn, locker and exchange addresses
+func (c *HoneyPotClient) GetHolderBreakdown(ctx context.Context, contractAddress string, pairs []string) (*holders.Breakdown, error) {
+	result, err := c.GetTopHolders(ctx, contractAddress)
 	if err != nil {
-		fmt.Println("Error parsing total supply:", err)
-		return 0, err
+		return nil, err
+	}
+	if len(result.Holders) == 0 {
+		return nil, nil
 	}
 
-	if totalsupply == 0 {
-		return 0, nil
+	b := holders.NewClassifier(c.chain, pairs).Breakdown(holders.SourceHoneypot, result.Reported(), result.TotalSupply)
+	if b == nil {
+		return nil, fmt.Errorf("invalid total supply %q", result.TotalSupply)
 	}
+	return b, nil
+}
 
-	return (totalTop10Balance / totalsupply) * 100, nil
+// GetTop10HoldersConcentration returns the percent of circulating supply
+// held by the ten largest genuine holders
+func (c *HoneyPotClient) GetTop10HoldersConcentration(ctx context.Context, contractAddress string) (float64, error) {
+	b, err := c.GetHolderBreakdown(ctx, contractAddress, nil)
+	if err != nil || b == nil {
+		return 0, err
+	}
+	return b.Top10Concentration, nil
 }
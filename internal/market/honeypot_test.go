@@ -0,0 +1,48 @@
+package market_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/market"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+const cake = "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"
+
+func TestGetHolderBreakdownExcludesPairsAndExchanges(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+
+	httpClient := transport.NewClient(transport.ProviderHoneypot, transport.Limits{Timeout: 5 * time.Second})
+	client := market.NewHoneyPotClient(chain.BSC, httpClient).WithBaseURL(srv.HoneypotURL())
+
+	m, err := newTestClient(srv).GetMarket(context.Background(), cake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := client.GetHolderBreakdown(context.Background(), cake, m.PairAddresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 141M of 380M listed; 11.6M sits in the three DexScreener pairs and
+	// 67.6M in Binance and Gate.io hot wallets, leaving 61.8M of 300.8M
+	if math.Abs(b.RawTop10Concentration-141.0/380*100) > 0.01 || math.Abs(b.Top10Concentration-61.8/300.8*100) > 0.01 {
+		t.Errorf("Top10 = %.2f%% (raw %.2f%%)", b.Top10Concentration, b.RawTop10Concentration)
+	}
+	want := map[string]int{holders.LabelPair: 3, holders.LabelCEX: 5, holders.LabelWallet: 2}
+	if len(b.Classes) != len(want) {
+		t.Fatalf("Classes = %+v", b.Classes)
+	}
+	for _, c := range b.Classes {
+		if c.Holders != want[c.Label] {
+			t.Errorf("%s holders = %d, want %d", c.Label, c.Holders, want[c.Label])
+		}
+	}
+}
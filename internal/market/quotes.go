@@ -0,0 +1,90 @@
+package market
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+)
+
+// ParseQuoteAssets resolves quote asset specs on a chain: a known symbol
+// ("WBNB") or "SYMBOL=0xaddress" for any other token. No specs selects the
+// chain's wrapped native token and stablecoins.
+func ParseQuoteAssets(ch chain.Chain, specs []string) ([]chain.Token, error) {
+	var assets []chain.Token
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		var asset chain.Token
+		if symbol, address, ok := strings.Cut(spec, "="); ok {
+			if !models.IsAddress(address) {
+				return nil, fmt.Errorf("quote asset %q: invalid address", spec)
+			}
+			asset = chain.Token{Symbol: strings.ToUpper(symbol), Address: strings.ToLower(address), Decimals: 18}
+		} else {
+			found := false
+			for _, known := range ch.KnownTokens() {
+				if strings.EqualFold(known.Symbol, spec) {
+					asset, found = known, true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown quote asset %q on %s (use SYMBOL=0xaddress)", spec, ch.Name)
+			}
+		}
+
+		if !seen[asset.Address] {
+			seen[asset.Address] = true
+			assets = append(assets, asset)
+		}
+	}
+	if len(assets) == 0 {
+		return ch.DefaultQuoteAssets(), nil
+	}
+	return assets, nil
+}
+
+// QuoteLiquidity is the liquidity and volume aggregated per quote asset
+type QuoteLiquidity struct {
+	Symbol       string  `json:"symbol"`
+	Address      string  `json:"address"`
+	Pairs        int     `json:"pairs"`
+	LiquidityUSD float64 `json:"liquidity_usd"`
+	Volume24h    float64 `json:"volume_24h"`
+}
+
+// PairMarket is one counted pair's market state
+type PairMarket struct {
+	Address      string  `json:"address"` // Lowercase
+	DexID        string  `json:"dex_id"`
+	Quote        string  `json:"quote"` // Quote asset symbol
+	LiquidityUSD float64 `json:"liquidity_usd"`
+	Volume24h    float64 `json:"volume_24h"`
+	PriceUSD     float64 `json:"price_usd"`
+}
+
+// pairLiquidityUSD returns the pair's USD liquidity. DexScreener omits
+// liquidity.usd when it cannot price the quote token; in that case both
+// sides are valued from priceUsd and priceNative (price of the quote in USD
+// = priceUsd / priceNative).
+func pairLiquidityUSD(pair models.DexScreenerPair) float64 {
+	if pair.Liquidity.USD > 0 {
+		return pair.Liquidity.USD
+	}
+
+	priceUSD, err1 := strconv.ParseFloat(pair.PriceUSD, 64)
+	priceNative, err2 := strconv.ParseFloat(pair.PriceNative, 64)
+	if err1 != nil || err2 != nil || priceUSD <= 0 || priceNative <= 0 {
+		return 0
+	}
+	quotePriceUSD := priceUSD / priceNative
+
+	return pair.Liquidity.Base*priceUSD + pair.Liquidity.Quote*quotePriceUSD
+}
@@ -0,0 +1,289 @@
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+)
+
+// The JSON-RPC endpoint answers router and quoter calls by simulating
+// constant-product pools from rpc/pools.json, so slippage is computed
+// from reserves rather than replayed. Other eth_calls are looked up in
+// rpc/eth_call/<to>.json (calldata → return data) and eth_getLogs in
+// rpc/eth_getLogs/<address>.json (a log list filtered by block range).
+
+// LatestBlock is the block number reported by eth_blockNumber
+const LatestBlock = 0x2a00000
+
+// MaxLogRange is the widest eth_getLogs block range the endpoint accepts
+const MaxLogRange = 5000
+
+const (
+	selectorGetAmountsOut         = "d06ca61f"
+	selectorQuoteExactInputSingle = "c6a5026a"
+)
+
+// mockPool is a pool in rpc/pools.json. Reserves are decimal base units;
+// V3 pools are approximated by virtual reserves at the current price.
+type mockPool struct {
+	Token0   string `json:"token0"`
+	Token1   string `json:"token1"`
+	Reserve0 string `json:"reserve0"`
+	Reserve1 string `json:"reserve1"`
+	Fee      int64  `json:"fee"` // Hundredths of a bip (V2 pools: 2500 = 0.25%)
+}
+
+type mockPools struct {
+	V2 []mockPool `json:"v2"`
+	V3 []mockPool `json:"v3"`
+}
+
+// RPCURL returns the JSON-RPC endpoint URL
+func (s *Server) RPCURL() string { return s.URL + "/rpc" }
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.hits["rpc/"+req.Method]++
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var result any
+	var err error
+	switch req.Method {
+	case "eth_blockNumber":
+		result = rpc.Quantity(LatestBlock)
+	case "eth_chainId":
+		result = "0x38"
+	case "eth_call":
+		result, err = s.ethCall(req.Params)
+	case "eth_getLogs":
+		result, err = s.getLogs(req.Params)
+	default:
+		err = fmt.Errorf("method %s not supported", req.Method)
+	}
+
+	if err != nil {
+		code := -32601
+		switch {
+		case strings.Contains(err.Error(), "revert"):
+			code = 3
+		case strings.Contains(err.Error(), "block range"):
+			code = -32005
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0", "id": req.ID,
+			"error": map[string]any{"code": code, "message": err.Error()},
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+func (s *Server) ethCall(params []json.RawMessage) (string, error) {
+	if len(params) == 0 {
+		return "", fmt.Errorf("missing call object")
+	}
+	var call struct {
+		To   string `json:"to"`
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(params[0], &call); err != nil {
+		return "", err
+	}
+	data := strings.ToLower(strings.TrimPrefix(call.Data, "0x"))
+	if len(data) < 8 {
+		return "", fmt.Errorf("execution reverted")
+	}
+
+	switch data[:8] {
+	case selectorGetAmountsOut:
+		return s.getAmountsOut(data[8:])
+	case selectorQuoteExactInputSingle:
+		return s.quoteExactInputSingle(data[8:])
+	}
+
+	body, err := fs.ReadFile(s.fixtures, path.Join("rpc/eth_call", strings.ToLower(call.To)+".json"))
+	if err != nil {
+		return "", fmt.Errorf("execution reverted")
+	}
+	var returns map[string]string
+	if err := json.Unmarshal(body, &returns); err != nil {
+		return "", err
+	}
+	ret, ok := returns["0x"+data]
+	if !ok {
+		return "", fmt.Errorf("execution reverted")
+	}
+	return ret, nil
+}
+
+func (s *Server) getLogs(params []json.RawMessage) ([]rpc.Log, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("missing filter")
+	}
+	var filter struct {
+		FromBlock string   `json:"fromBlock"`
+		ToBlock   string   `json:"toBlock"`
+		Address   []string `json:"address"`
+		Topics    []any    `json:"topics"`
+	}
+	if err := json.Unmarshal(params[0], &filter); err != nil {
+		return nil, err
+	}
+	from, err := rpc.ParseQuantity(filter.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := rpc.ParseQuantity(filter.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	if to >= from+MaxLogRange {
+		return nil, fmt.Errorf("exceed maximum block range: %d", MaxLogRange)
+	}
+	var topic0 string
+	if len(filter.Topics) > 0 {
+		topic0, _ = filter.Topics[0].(string)
+	}
+
+	logs := []rpc.Log{}
+	for _, address := range filter.Address {
+		body, err := fs.ReadFile(s.fixtures, path.Join("rpc/eth_getLogs", strings.ToLower(address)+".json"))
+		if err != nil {
+			continue
+		}
+		var all []rpc.Log
+		if err := json.Unmarshal(body, &all); err != nil {
+			return nil, err
+		}
+		for _, l := range all {
+			block, _ := rpc.ParseQuantity(l.BlockNumber)
+			if block < from || block > to {
+				continue
+			}
+			if topic0 != "" && (len(l.Topics) == 0 || !strings.EqualFold(l.Topics[0], topic0)) {
+				continue
+			}
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+func (s *Server) pools() (mockPools, error) {
+	var pools mockPools
+	body, err := fs.ReadFile(s.fixtures, "rpc/pools.json")
+	if err != nil {
+		return pools, nil
+	}
+	err = json.Unmarshal(body, &pools)
+	return pools, err
+}
+
+// getAmountsOut simulates a V2 router quote along a path of pools
+func (s *Server) getAmountsOut(args string) (string, error) {
+	words, err := rpc.Words(args)
+	if err != nil || len(words) < 3 {
+		return "", fmt.Errorf("execution reverted: bad calldata")
+	}
+	pools, err := s.pools()
+	if err != nil {
+		return "", err
+	}
+
+	amount, _ := rpc.DecodeUint(words[0])
+	n, _ := rpc.DecodeUint(words[2])
+	if int(n.Int64())+3 > len(words) || n.Int64() < 2 {
+		return "", fmt.Errorf("execution reverted: invalid path")
+	}
+	path := make([]string, n.Int64())
+	for i := range path {
+		path[i] = rpc.DecodeAddress(words[3+i])
+	}
+
+	amounts := []*big.Int{amount}
+	for i := 0; i+1 < len(path); i++ {
+		pool, ok := findPool(pools.V2, path[i], path[i+1], -1)
+		if !ok {
+			return "", fmt.Errorf("execution reverted")
+		}
+		amount = swapOut(pool, path[i], amount)
+		amounts = append(amounts, amount)
+	}
+
+	ret := rpc.EncodeUint64(0x20) + rpc.EncodeUint64(uint64(len(amounts)))
+	for _, a := range amounts {
+		ret += rpc.EncodeUint(a)
+	}
+	return "0x" + ret, nil
+}
+
+// quoteExactInputSingle simulates a V3 QuoterV2 single-pool quote
+func (s *Server) quoteExactInputSingle(args string) (string, error) {
+	words, err := rpc.Words(args)
+	if err != nil || len(words) < 5 {
+		return "", fmt.Errorf("execution reverted: bad calldata")
+	}
+	pools, err := s.pools()
+	if err != nil {
+		return "", err
+	}
+
+	tokenIn, tokenOut := rpc.DecodeAddress(words[0]), rpc.DecodeAddress(words[1])
+	amountIn, _ := rpc.DecodeUint(words[2])
+	fee, _ := rpc.DecodeUint(words[3])
+
+	pool, ok := findPool(pools.V3, tokenIn, tokenOut, fee.Int64())
+	if !ok {
+		return "", fmt.Errorf("execution reverted")
+	}
+	out := swapOut(pool, tokenIn, amountIn)
+
+	// amountOut, sqrtPriceX96After, initializedTicksCrossed, gasEstimate
+	return "0x" + rpc.EncodeUint(out) + rpc.EncodeUint64(0) + rpc.EncodeUint64(1) + rpc.EncodeUint64(100_000), nil
+}
+
+// findPool finds the pool for a token pair (and fee tier, if fee >= 0)
+func findPool(pools []mockPool, a, b string, fee int64) (mockPool, bool) {
+	for _, p := range pools {
+		t0, t1 := strings.ToLower(p.Token0), strings.ToLower(p.Token1)
+		if ((t0 == a && t1 == b) || (t0 == b && t1 == a)) && (fee < 0 || p.Fee == fee) {
+			return p, true
+		}
+	}
+	return mockPool{}, false
+}
+
+// swapOut is the constant-product output for amountIn of tokenIn after the pool fee
+func swapOut(p mockPool, tokenIn string, amountIn *big.Int) *big.Int {
+	r0, _ := new(big.Int).SetString(p.Reserve0, 10)
+	r1, _ := new(big.Int).SetString(p.Reserve1, 10)
+	rIn, rOut := r0, r1
+	if strings.ToLower(p.Token1) == tokenIn {
+		rIn, rOut = r1, r0
+	}
+
+	inWithFee := new(big.Int).Mul(amountIn, big.NewInt(1_000_000-p.Fee))
+	num := new(big.Int).Mul(inWithFee, rOut)
+	den := new(big.Int).Add(new(big.Int).Mul(rIn, big.NewInt(1_000_000)), inWithFee)
+	return num.Div(num, den)
+}
@@ -0,0 +1,158 @@
+// Package mockapi provides an httptest server that replays recorded
+// DexScreener, Honeypot.is, GoPlus and Etherscan v2 responses, plus a
+// JSON-RPC endpoint that simulates DEX pools, so the pipeline can run offline
+package mockapi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+//go:embed fixtures
+var embedded embed.FS
+
+// Server replays fixtures for every provider from a single host.
+// Provider paths do not overlap, so all base URLs point at the same server.
+type Server struct {
+	*httptest.Server
+
+	fixtures fs.FS
+
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+// New starts a server backed by the embedded fixtures
+func New() *Server {
+	sub, _ := fs.Sub(embedded, "fixtures")
+	return NewWithFS(sub)
+}
+
+// NewFromDir starts a server backed by fixtures on disk
+func NewFromDir(dir string) *Server {
+	return NewWithFS(os.DirFS(dir))
+}
+
+func NewWithFS(fixtures fs.FS) *Server {
+	s := &Server{
+		fixtures: fixtures,
+		hits:     make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /token-pairs/v1/{chain}/{address}", s.handleDexScreener)
+	mux.HandleFunc("GET /v1/TopHolders", s.handleTopHolders)
+	mux.HandleFunc("GET /v2/IsHoneypot", s.handleHoneypot)
+	mux.HandleFunc("GET /api/v1/token_security/{chainID}", s.handleGoPlus)
+	mux.HandleFunc("GET /v2/api", s.handleEtherscan)
+	mux.HandleFunc("POST /rpc", s.handleRPC)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// DexScreenerURL, HoneypotURL, GoPlusURL and EtherscanURL return the base
+// URLs to inject into the corresponding clients
+func (s *Server) DexScreenerURL() string { return s.URL }
+func (s *Server) HoneypotURL() string    { return s.URL }
+func (s *Server) GoPlusURL() string      { return s.URL }
+func (s *Server) EtherscanURL() string   { return s.URL + "/v2/api" }
+
+// Hits returns how many requests a provider endpoint received,
+// keyed like "dexscreener", "etherscan/getsourcecode" or "rpc/eth_call"
+func (s *Server) Hits(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[endpoint]
+}
+
+func (s *Server) handleDexScreener(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, "dexscreener", r.PathValue("address"), `[]`)
+}
+
+func (s *Server) handleTopHolders(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, "topholders", r.URL.Query().Get("address"), `{"totalSupply":"0","holders":[]}`)
+}
+
+func (s *Server) handleHoneypot(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, "honeypot", r.URL.Query().Get("address"), `{"simulationSuccess":false}`)
+}
+
+func (s *Server) handleGoPlus(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, "goplus", r.URL.Query().Get("contract_addresses"), `{"code":1,"message":"OK","result":{}}`)
+}
+
+func (s *Server) handleEtherscan(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch action := q.Get("action"); action {
+	case "getsourcecode":
+		s.serve(w, "etherscan/getsourcecode", q.Get("address"),
+			`{"status":"1","message":"OK","result":[{"SourceCode":"","ABI":"Contract source code not verified","ContractName":"","Proxy":"0","Implementation":""}]}`)
+	case "getcontractcreation":
+		s.serve(w, "etherscan/getcontractcreation", q.Get("contractaddresses"),
+			`{"status":"0","message":"No data found","result":null}`)
+	case "eth_getStorageAt":
+		s.serveRPC(w, "etherscan/eth_getStorageAt", q.Get("address"), q.Get("position"),
+			`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000000"}`)
+	case "eth_call":
+		s.serveRPC(w, "etherscan/eth_call", q.Get("to"), q.Get("data"),
+			`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`)
+	case "tokensupply":
+		s.serve(w, "etherscan/tokensupply", q.Get("contractaddress"),
+			`{"status":"1","message":"OK","result":"0"}`)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Error! Invalid action"}`))
+	}
+}
+
+// serveRPC answers an Etherscan proxy-module call from endpoint/<address>.json,
+// a map of storage slot or calldata to the returned word
+func (s *Server) serveRPC(w http.ResponseWriter, endpoint, address, key, fallback string) {
+	s.mu.Lock()
+	s.hits[endpoint]++
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	body, err := fs.ReadFile(s.fixtures, path.Join(endpoint, strings.ToLower(address)+".json"))
+	if err != nil {
+		w.Write([]byte(fallback))
+		return
+	}
+	var words map[string]string
+	if err := json.Unmarshal(body, &words); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	word, ok := words[strings.ToLower(key)]
+	if !ok {
+		w.Write([]byte(fallback))
+		return
+	}
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%q}`, word)
+}
+
+// serve writes endpoint/<address>.json, or fallback when there is no fixture
+func (s *Server) serve(w http.ResponseWriter, endpoint, address, fallback string) {
+	s.mu.Lock()
+	s.hits[endpoint]++
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	body, err := fs.ReadFile(s.fixtures, path.Join(endpoint, strings.ToLower(address)+".json"))
+	if err != nil {
+		w.Write([]byte(fallback))
+		return
+	}
+	w.Write(body)
+}
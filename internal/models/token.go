@@ -1,10 +1,29 @@
 // Package models contains data structures for BSC token analysis
 package models
 
-import "time"
+import (
+	"regexp"
+	"time"
+)
+
+// BasicTokenInfo is a token entry as listed in the input token files
+type BasicTokenInfo struct {
+	Address  string `json:"contract_address"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// IsAddress reports whether s is a 0x-prefixed 20-byte hex address
+func IsAddress(s string) bool {
+	return addressPattern.MatchString(s)
+}
 
 // Token represents a BSC token with all analysis data
 type Token struct {
+	Chain    string `db:"chain" json:"chain"`
 	Address  string `db:"address" json:"address"`
 	Name     string `db:"name" json:"name"`
 	Symbol   string `db:"symbol" json:"symbol"`
@@ -47,8 +66,13 @@ type DexScreenerPair struct {
 		Symbol  string `json:"symbol"`
 	} `json:"quoteToken"`
 
+	PriceNative string `json:"priceNative"` // Base token price in quote token units
+	PriceUSD    string `json:"priceUsd"`
+
 	Liquidity struct {
-		USD float64 `json:"usd"`
+		USD   float64 `json:"usd"`
+		Base  float64 `json:"base"`
+		Quote float64 `json:"quote"`
 	} `json:"liquidity"`
 
 	Volume struct {
@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// SinkConfig describes one sink. Which fields apply depends on Type.
+type SinkConfig struct {
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type"`                // One of SinkTypes
+	URL      string   `yaml:"url,omitempty"`       // webhook, slack: endpoint; telegram: Bot API base, empty = DefaultTelegramURL
+	BotToken string   `yaml:"bot_token,omitempty"` // telegram
+	ChatID   string   `yaml:"chat_id,omitempty"`   // telegram
+	SMTPAddr string   `yaml:"smtp_addr,omitempty"` // email: host:port
+	Username string   `yaml:"username,omitempty"`  // email: PLAIN auth, empty = none
+	Password string   `yaml:"password,omitempty"`  // email
+	From     string   `yaml:"from,omitempty"`      // email
+	To       []string `yaml:"to,omitempty"`        // email
+}
+
+// Config configures alerting. Without sinks nothing is sent.
+type Config struct {
+	Sinks        []SinkConfig  `yaml:"sinks"`
+	Routes       []Route       `yaml:"routes"`        // An event goes to the sinks of every route it matches
+	DedupeWindow time.Duration `yaml:"dedupe_window"` // Repeats of an event within it are dropped; 0 = never
+}
+
+// MarshalYAML writes the dedupe window as "6h0m0s" rather than nanoseconds
+func (c Config) MarshalYAML() (any, error) {
+	return struct {
+		Sinks        []SinkConfig `yaml:"sinks"`
+		Routes       []Route      `yaml:"routes"`
+		DedupeWindow string       `yaml:"dedupe_window"`
+	}{c.Sinks, c.Routes, c.DedupeWindow.String()}, nil
+}
+
+// DefaultConfig alerts on listed tokens that start failing and on every
+// honeypot, at most once per token and change every 6 hours. Sinks have to
+// be configured.
+func DefaultConfig() Config {
+	return Config{
+		Routes: []Route{
+			{Kinds: []string{KindTransition}, From: []string{"FEATURED", "VISIBLE"}, To: []string{"FAILED"}},
+			{Kinds: []string{KindHoneypot}},
+		},
+		DedupeWindow: 6 * time.Hour,
+	}
+}
+
+// Validate checks sink fields and that routes name known kinds and sinks
+func (c Config) Validate() error {
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	names := map[string]bool{}
+	for i, s := range c.Sinks {
+		label := fmt.Sprintf("sink %d", i+1)
+		if s.Name != "" {
+			label = fmt.Sprintf("sink %q", s.Name)
+		}
+		switch {
+		case s.Name == "":
+			fail("%s: name is required", label)
+		case names[s.Name]:
+			fail("%s: duplicate name", label)
+		}
+		names[s.Name] = true
+
+		switch s.Type {
+		case SinkWebhook, SinkSlack:
+			if u, err := url.Parse(s.URL); err != nil || u.Scheme == "" || u.Host == "" {
+				fail("%s: url %q is not a URL", label, s.URL)
+			}
+		case SinkTelegram:
+			if s.BotToken == "" || s.ChatID == "" {
+				fail("%s: bot_token and chat_id are required", label)
+			}
+		case SinkEmail:
+			if _, _, err := net.SplitHostPort(s.SMTPAddr); err != nil {
+				fail("%s: smtp_addr %q is not host:port", label, s.SMTPAddr)
+			}
+			if s.From == "" || len(s.To) == 0 {
+				fail("%s: from and to are required", label)
+			}
+		default:
+			fail("%s: unknown type %q (want %s)", label, s.Type, strings.Join(SinkTypes, ", "))
+		}
+	}
+
+	for i, r := range c.Routes {
+		for _, kind := range r.Kinds {
+			if !slices.Contains(Kinds, kind) {
+				fail("route %d: unknown kind %q (want %s)", i+1, kind, strings.Join(Kinds, ", "))
+			}
+		}
+		for _, name := range r.Sinks {
+			if !names[name] {
+				fail("route %d: unknown sink %q", i+1, name)
+			}
+		}
+	}
+
+	if c.DedupeWindow < 0 {
+		fail("dedupe_window must not be negative")
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Masked returns the config with tokens, passwords and webhook paths
+// (which carry the webhook secret) replaced by "****"
+func (c Config) Masked() Config {
+	sinks := make([]SinkConfig, len(c.Sinks))
+	for i, s := range c.Sinks {
+		if s.BotToken != "" {
+			s.BotToken = "****"
+		}
+		if s.Password != "" {
+			s.Password = "****"
+		}
+		if s.Type != SinkTelegram {
+			if u, err := url.Parse(s.URL); err == nil && u.Host != "" && (u.Path != "" || u.RawQuery != "") {
+				s.URL = u.Scheme + "://" + u.Host + "/****"
+			}
+		}
+		sinks[i] = s
+	}
+	c.Sinks = sinks
+	return c
+}
+
+// FromConfig builds the configured sinks and returns a notifier over them.
+// HTTP sinks share httpClient's rate limit and retries.
+func FromConfig(c Config, httpClient *transport.Client) (*Notifier, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	sinks := make([]Sink, 0, len(c.Sinks))
+	for _, s := range c.Sinks {
+		switch s.Type {
+		case SinkWebhook:
+			sinks = append(sinks, NewWebhookSink(s.Name, s.URL, httpClient))
+		case SinkSlack:
+			sinks = append(sinks, NewSlackSink(s.Name, s.URL, httpClient))
+		case SinkTelegram:
+			sinks = append(sinks, NewTelegramSink(s.Name, s.BotToken, s.ChatID, httpClient).WithBaseURL(s.URL))
+		case SinkEmail:
+			sinks = append(sinks, NewEmailSink(s.Name, s.SMTPAddr, s.From, s.To).WithAuth(s.Username, s.Password))
+		}
+	}
+	return New(sinks, c.Routes).WithDedupeWindow(c.DedupeWindow), nil
+}
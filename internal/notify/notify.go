@@ -0,0 +1,192 @@
+// Package notify delivers alerts about screening events, such as a watched
+// token going from FEATURED to FAILED or a newly detected honeypot, to
+// webhook, Slack, Telegram and email sinks. Routes decide which events go
+// to which sinks, and repeats of an event within the dedupe window are
+// dropped.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event kinds
+const (
+	KindTransition = "transition"        // A watched or re-screened token changed state
+	KindHoneypot   = "honeypot_rejected" // A screening rejected a token as a honeypot
+)
+
+// Kinds lists the event kinds routes can select
+var Kinds = []string{KindTransition, KindHoneypot}
+
+// Event is something worth alerting on
+type Event struct {
+	Kind    string    `json:"kind"`
+	Chain   string    `json:"chain"`
+	Address string    `json:"address"`
+	Symbol  string    `json:"symbol"`
+	From    string    `json:"from,omitempty"` // Transitions: previous state, "" for a new token
+	To      string    `json:"to,omitempty"`   // Transitions: new state
+	Score   float64   `json:"score"`
+	Reasons []string  `json:"reasons,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// Key identifies repeats of the same event for deduplication
+func (e Event) Key() string {
+	return strings.Join([]string{e.Kind, e.Chain, strings.ToLower(e.Address), e.From, e.To}, "/")
+}
+
+// Title is a one-line summary, used as the email subject
+func (e Event) Title() string {
+	switch e.Kind {
+	case KindTransition:
+		from := e.From
+		if from == "" {
+			from = "NEW"
+		}
+		return fmt.Sprintf("%s: %s -> %s", e.Symbol, from, e.To)
+	case KindHoneypot:
+		return fmt.Sprintf("%s: honeypot rejected", e.Symbol)
+	default:
+		return fmt.Sprintf("%s: %s", e.Symbol, e.Kind)
+	}
+}
+
+// Text is the plain-text alert body
+func (e Event) Text() string {
+	var b strings.Builder
+	b.WriteString(e.Title() + "\n")
+	fmt.Fprintf(&b, "Token: %s on %s\n", e.Address, e.Chain)
+	if e.Score > 0 {
+		fmt.Fprintf(&b, "Score: %.1f\n", e.Score)
+	}
+	for _, reason := range e.Reasons {
+		b.WriteString("- " + reason + "\n")
+	}
+	fmt.Fprintf(&b, "At: %s\n", e.At.UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// Sink delivers events to one destination
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, e Event) error
+}
+
+// Route sends matching events to sinks. Empty fields match everything.
+type Route struct {
+	Kinds []string `yaml:"kinds,omitempty"` // Event kinds
+	From  []string `yaml:"from,omitempty"`  // Previous states of transitions
+	To    []string `yaml:"to,omitempty"`    // New states of transitions
+	Sinks []string `yaml:"sinks,omitempty"` // Sink names; empty = every sink
+}
+
+// Matches reports whether e passes the route's filters
+func (r Route) Matches(e Event) bool {
+	return matches(r.Kinds, e.Kind) && matches(r.From, e.From) && matches(r.To, e.To)
+}
+
+func matches(values []string, value string) bool {
+	return len(values) == 0 || slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
+
+// Notifier routes events to sinks and drops repeats within the dedupe window
+type Notifier struct {
+	sinks  []Sink
+	routes []Route
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	sent map[string]time.Time // Last delivery, keyed by sink name and event key
+}
+
+// New creates a notifier delivering over sinks. Without routes nothing is
+// sent.
+func New(sinks []Sink, routes []Route) *Notifier {
+	return &Notifier{
+		sinks:  sinks,
+		routes: routes,
+		now:    time.Now,
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// WithDedupeWindow drops an event delivered to a sink less than window ago;
+// 0 delivers every event
+func (n *Notifier) WithDedupeWindow(window time.Duration) *Notifier {
+	n.window = window
+	return n
+}
+
+// WithClock replaces time.Now, for tests
+func (n *Notifier) WithClock(now func() time.Time) *Notifier {
+	n.now = now
+	return n
+}
+
+// Notify delivers e to the sinks of every matching route, at most once per
+// sink. Failed deliveries are not remembered, so a later repeat is retried.
+// The error joins every sink that failed.
+func (n *Notifier) Notify(ctx context.Context, e Event) error {
+	var errs []error
+	for _, sink := range n.targets(e) {
+		key := sink.Name() + "|" + e.Key()
+		if !n.claim(key) {
+			continue
+		}
+		if err := sink.Send(ctx, e); err != nil {
+			n.release(key)
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// targets returns the sinks of the routes matching e, in sink order
+func (n *Notifier) targets(e Event) []Sink {
+	var targets []Sink
+	for _, sink := range n.sinks {
+		for _, r := range n.routes {
+			if r.Matches(e) && (len(r.Sinks) == 0 || slices.Contains(r.Sinks, sink.Name())) {
+				targets = append(targets, sink)
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// claim records a delivery under key unless one happened within the window
+func (n *Notifier) claim(key string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.window <= 0 {
+		return true
+	}
+	now := n.now()
+	if last, ok := n.sent[key]; ok && now.Sub(last) < n.window {
+		return false
+	}
+	// Forget expired deliveries so a long-running watcher does not grow the map
+	for k, last := range n.sent {
+		if now.Sub(last) >= n.window {
+			delete(n.sent, k)
+		}
+	}
+	n.sent[key] = now
+	return true
+}
+
+func (n *Notifier) release(key string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.sent, key)
+}
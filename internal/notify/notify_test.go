@@ -0,0 +1,320 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/notify"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+const cake = "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"
+
+var (
+	at = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	featuredToFailed = notify.Event{Kind: notify.KindTransition, Chain: "bsc", Address: cake, Symbol: "CAKE",
+		From: "FEATURED", To: "FAILED", Reasons: []string{"rejected at fraud stage", "Sell tax raised to 40%"}, At: at}
+	newToFeatured = notify.Event{Kind: notify.KindTransition, Chain: "bsc", Address: cake, Symbol: "CAKE",
+		To: "FEATURED", Score: 85, At: at}
+	honeypot = notify.Event{Kind: notify.KindHoneypot, Chain: "bsc", Address: cake, Symbol: "CAKE",
+		Reasons: []string{"Cannot buy token (GoPlus)"}, At: at}
+)
+
+// recorder is a sink that keeps what it is sent and fails while err is set
+type recorder struct {
+	name   string
+	err    error
+	events []notify.Event
+}
+
+func (r *recorder) Name() string { return r.name }
+
+func (r *recorder) Send(ctx context.Context, e notify.Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, e)
+	return nil
+}
+
+func TestRoutingAndDedupe(t *testing.T) {
+	ops, oncall := &recorder{name: "ops"}, &recorder{name: "oncall"}
+	routes := []notify.Route{
+		{Kinds: []string{notify.KindTransition}, From: []string{"FEATURED", "VISIBLE"}, To: []string{"FAILED"}},
+		{Kinds: []string{notify.KindHoneypot}, Sinks: []string{"oncall"}},
+	}
+	now := at
+	n := notify.New([]notify.Sink{ops, oncall}, routes).
+		WithDedupeWindow(time.Hour).
+		WithClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	for _, e := range []notify.Event{featuredToFailed, newToFeatured, honeypot, featuredToFailed} {
+		if err := n.Notify(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(ops.events) != 1 || ops.events[0].To != "FAILED" {
+		t.Errorf("ops got %+v, want the one FEATURED -> FAILED alert", ops.events)
+	}
+	if len(oncall.events) != 2 || oncall.events[1].Kind != notify.KindHoneypot {
+		t.Errorf("oncall got %+v, want the transition and the honeypot", oncall.events)
+	}
+
+	// Once the window has passed the same change alerts again
+	now = now.Add(time.Hour)
+	n.Notify(ctx, featuredToFailed)
+	if len(ops.events) != 2 {
+		t.Errorf("ops got %d alerts after the dedupe window, want 2", len(ops.events))
+	}
+
+	// A failed delivery is not remembered, so the repeat goes out
+	oncall.err = errors.New("connection refused")
+	if err := n.Notify(ctx, notify.Event{Kind: notify.KindHoneypot, Address: "0xother", At: at}); err == nil ||
+		!strings.Contains(err.Error(), "oncall: connection refused") {
+		t.Fatalf("error = %v, want the oncall failure", err)
+	}
+	oncall.err = nil
+	n.Notify(ctx, notify.Event{Kind: notify.KindHoneypot, Address: "0xother", At: at})
+	if got := oncall.events[len(oncall.events)-1]; got.Address != "0xother" {
+		t.Errorf("retried alert was deduplicated: last oncall alert %+v", got)
+	}
+}
+
+// request is one request received by the HTTP stand-in
+type request struct {
+	path string
+	body map[string]any
+}
+
+func TestHTTPSinks(t *testing.T) {
+	var mu sync.Mutex
+	var requests []request
+	failNext := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/flaky" && failNext {
+			failNext = false
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, request{path: r.URL.Path, body: body})
+	}))
+	defer srv.Close()
+
+	cfg := notify.Config{
+		Sinks: []notify.SinkConfig{
+			{Name: "hook", Type: notify.SinkWebhook, URL: srv.URL + "/hook"},
+			{Name: "slack", Type: notify.SinkSlack, URL: srv.URL + "/flaky"},
+			{Name: "tg", Type: notify.SinkTelegram, URL: srv.URL, BotToken: "123:abc", ChatID: "-100"},
+		},
+		Routes: []notify.Route{{}},
+	}
+	client := transport.NewClient(transport.ProviderNotify, transport.Limits{MaxRetries: 1, Timeout: 5 * time.Second})
+	n, err := notify.FromConfig(cfg, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), featuredToFailed); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want 3: %+v", len(requests), requests)
+	}
+	if r := requests[0]; r.path != "/hook" || r.body["to"] != "FAILED" || r.body["address"] != cake {
+		t.Errorf("webhook request = %+v", r)
+	}
+	if r := requests[1]; r.path != "/flaky" || !strings.HasPrefix(r.body["text"].(string), "CAKE: FEATURED -> FAILED\n") {
+		t.Errorf("slack request = %+v", r)
+	}
+	if r := requests[2]; r.path != "/bot123:abc/sendMessage" || r.body["chat_id"] != "-100" ||
+		!strings.Contains(r.body["text"].(string), "- Sell tax raised to 40%") {
+		t.Errorf("telegram request = %+v", r)
+	}
+	if stats := client.Stats(); stats.Retries != 1 {
+		t.Errorf("retries = %d, want the one 503", stats.Retries)
+	}
+}
+
+func TestEmailSink(t *testing.T) {
+	addr, messages := smtpStandIn(t)
+
+	sink := notify.NewEmailSink("mail", addr, "screener@example.com", []string{"ops@example.com", "risk@example.com"})
+	if err := sink.Send(context.Background(), honeypot); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-messages
+	if msg.from != "screener@example.com" || strings.Join(msg.to, ",") != "ops@example.com,risk@example.com" {
+		t.Errorf("envelope from %q to %q", msg.from, msg.to)
+	}
+	for _, want := range []string{
+		"Subject: [dex-token-screener] CAKE: honeypot rejected\r\n",
+		"Token: " + cake + " on bsc\r\n",
+		"- Cannot buy token (GoPlus)\r\n",
+	} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("message missing %q:\n%s", want, msg.data)
+		}
+	}
+}
+
+func TestEmailSubjectIsEncoded(t *testing.T) {
+	addr, messages := smtpStandIn(t)
+
+	hostile := honeypot
+	hostile.Symbol = "CAKE\r\nBcc: victim@example.com"
+	sink := notify.NewEmailSink("mail", addr, "screener@example.com", []string{"ops@example.com"})
+	if err := sink.Send(context.Background(), hostile); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-messages
+	headers, _, _ := strings.Cut(msg.data, "\r\n\r\n")
+	for _, line := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(line, "Bcc:") {
+			t.Errorf("symbol injected a header:\n%s", headers)
+		}
+	}
+	if !strings.Contains(headers, "Subject: =?utf-8?q?") {
+		t.Errorf("subject not encoded:\n%s", headers)
+	}
+}
+
+type mail struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpStandIn accepts SMTP sessions on a local port and returns the
+// messages they deliver. It speaks just enough of RFC 5321 for net/smtp.
+func smtpStandIn(t *testing.T) (string, <-chan mail) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan mail, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, messages)
+		}
+	}()
+	return ln.Addr().String(), messages
+}
+
+func serveSMTP(conn net.Conn, messages chan<- mail) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var m mail
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		switch verb := strings.ToUpper(strings.SplitN(cmd, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			m.from = strings.Trim(strings.TrimPrefix(cmd[len("MAIL "):], "FROM:"), "<>")
+			reply("250 OK")
+		case "RCPT":
+			m.to = append(m.to, strings.Trim(strings.TrimPrefix(cmd[len("RCPT "):], "TO:"), "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			m.data = data.String()
+			messages <- m
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := notify.DefaultConfig().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+
+	cfg := notify.Config{
+		Sinks: []notify.SinkConfig{
+			{Name: "hook", Type: notify.SinkWebhook, URL: "not a url"},
+			{Name: "hook", Type: notify.SinkTelegram},
+			{Name: "mail", Type: notify.SinkEmail, SMTPAddr: "localhost"},
+			{Name: "pager", Type: "pagerduty"},
+		},
+		Routes:       []notify.Route{{Kinds: []string{"delisted"}, Sinks: []string{"discord"}}},
+		DedupeWindow: -time.Minute,
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("invalid config passed validation")
+	}
+	for _, want := range []string{
+		`sink "hook": url "not a url" is not a URL`,
+		`sink "hook": duplicate name`,
+		`sink "hook": bot_token and chat_id are required`,
+		`sink "mail": smtp_addr "localhost" is not host:port`,
+		`sink "mail": from and to are required`,
+		`sink "pager": unknown type "pagerduty"`,
+		`route 1: unknown kind "delisted"`,
+		`route 1: unknown sink "discord"`,
+		"dedupe_window must not be negative",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%v", want, err)
+		}
+	}
+
+	masked := notify.Config{Sinks: []notify.SinkConfig{
+		{Name: "slack", Type: notify.SinkSlack, URL: "https://hooks.slack.com/services/T0/B0/secret"},
+		{Name: "mail", Type: notify.SinkEmail, Password: "hunter2"},
+	}}.Masked()
+	if s := masked.Sinks[0]; s.URL != "https://hooks.slack.com/****" {
+		t.Errorf("masked url %q", s.URL)
+	}
+	if s := masked.Sinks[1]; s.Password != "****" {
+		t.Errorf("masked password %q", s.Password)
+	}
+}
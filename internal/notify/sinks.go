@@ -0,0 +1,178 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// Sink types as written in the config file
+const (
+	SinkWebhook  = "webhook"  // POST the event as JSON
+	SinkSlack    = "slack"    // Slack-compatible incoming webhook ({"text": ...})
+	SinkTelegram = "telegram" // Telegram Bot API sendMessage
+	SinkEmail    = "email"    // SMTP
+)
+
+// SinkTypes lists the supported sink types
+var SinkTypes = []string{SinkWebhook, SinkSlack, SinkTelegram, SinkEmail}
+
+// DefaultTelegramURL is the Bot API base URL
+const DefaultTelegramURL = "https://api.telegram.org"
+
+// WebhookSink posts each event as JSON to a URL
+type WebhookSink struct {
+	name       string
+	url        string
+	httpClient *transport.Client
+}
+
+func NewWebhookSink(name, url string, httpClient *transport.Client) *WebhookSink {
+	return &WebhookSink{name: name, url: url, httpClient: httpClient}
+}
+
+func (s *WebhookSink) Name() string { return s.name }
+
+func (s *WebhookSink) Send(ctx context.Context, e Event) error {
+	return postJSON(ctx, s.httpClient, s.url, e)
+}
+
+// SlackSink posts the alert text to a Slack-compatible incoming webhook
+// (Slack, Mattermost, Discord's /slack endpoint)
+type SlackSink struct {
+	name       string
+	url        string
+	httpClient *transport.Client
+}
+
+func NewSlackSink(name, url string, httpClient *transport.Client) *SlackSink {
+	return &SlackSink{name: name, url: url, httpClient: httpClient}
+}
+
+func (s *SlackSink) Name() string { return s.name }
+
+func (s *SlackSink) Send(ctx context.Context, e Event) error {
+	return postJSON(ctx, s.httpClient, s.url, map[string]string{"text": e.Text()})
+}
+
+// TelegramSink sends the alert text to a chat through a bot
+type TelegramSink struct {
+	name       string
+	baseURL    string
+	botToken   string
+	chatID     string
+	httpClient *transport.Client
+}
+
+func NewTelegramSink(name, botToken, chatID string, httpClient *transport.Client) *TelegramSink {
+	return &TelegramSink{name: name, baseURL: DefaultTelegramURL, botToken: botToken, chatID: chatID, httpClient: httpClient}
+}
+
+// WithBaseURL overrides the Bot API URL (for testing)
+func (s *TelegramSink) WithBaseURL(baseURL string) *TelegramSink {
+	if baseURL != "" {
+		s.baseURL = strings.TrimRight(baseURL, "/")
+	}
+	return s
+}
+
+func (s *TelegramSink) Name() string { return s.name }
+
+func (s *TelegramSink) Send(ctx context.Context, e Event) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", s.baseURL, s.botToken)
+	return postJSON(ctx, s.httpClient, url, map[string]any{
+		"chat_id":                  s.chatID,
+		"text":                     e.Text(),
+		"disable_web_page_preview": true,
+	})
+}
+
+// EmailSink mails the alert over SMTP. PLAIN auth is used when a username
+// is set; net/smtp only sends it over TLS or to localhost.
+type EmailSink struct {
+	name     string
+	addr     string // host:port
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func NewEmailSink(name, addr, from string, to []string) *EmailSink {
+	return &EmailSink{name: name, addr: addr, from: from, to: to}
+}
+
+// WithAuth sets the SMTP PLAIN credentials
+func (s *EmailSink) WithAuth(username, password string) *EmailSink {
+	s.username, s.password = username, password
+	return s
+}
+
+func (s *EmailSink) Name() string { return s.name }
+
+func (s *EmailSink) Send(ctx context.Context, e Event) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		host, _, err := net.SplitHostPort(s.addr)
+		if err != nil {
+			return fmt.Errorf("smtp address %q: %w", s.addr, err)
+		}
+		auth = smtp.PlainAuth("", s.username, s.password, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	// The symbol comes from the token contract; encoding it keeps a CR/LF in
+	// it from starting headers of its own
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "[dex-token-screener] "+e.Title()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", e.At.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(e.Text(), "\n", "\r\n"))
+
+	// smtp.SendMail takes no context; run it so a cancelled context returns
+	done := make(chan error, 1)
+	go func() { done <- smtp.SendMail(s.addr, auth, s.from, s.to, msg.Bytes()) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// postJSON posts body and treats any non-2xx response as a failure
+func postJSON(ctx context.Context, httpClient *transport.Client, url string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Listing statuses as stored in models.Token.Status
+const (
+	ListingFeatured = "featured"
+	ListingVisible  = "visible" // Listed with warnings
+	ListingHidden   = "hidden"
+)
+
+// ListingStatuses in tier order
+var ListingStatuses = []string{ListingFeatured, ListingVisible, ListingHidden}
+
+// ListingThresholds are the minimum composite scores of the featured and
+// visible tiers (plan.txt: >= 70 featured, 50-69 visible with warning,
+// < 50 hidden)
+type ListingThresholds struct {
+	Featured float64 `json:"featured"`
+	Visible  float64 `json:"visible"`
+}
+
+// Listing is a result's tier and why it was placed there
+type Listing struct {
+	Status   string   `json:"status"`
+	Reasons  []string `json:"reasons"`
+	Warnings []string `json:"warnings,omitempty"` // Shown alongside visible tokens
+}
+
+// Listing places a result in the featured/visible/hidden tiers. Only passed
+// tokens are listed; visible ones carry the warnings a listing should show.
+func (r TokenResult) Listing(t ListingThresholds) Listing {
+	switch r.Status {
+	case StatusPassed:
+	case StatusFailed:
+		reasons := append([]string{fmt.Sprintf("rejected at %s stage", r.Stage)}, r.FailureReasons...)
+		return Listing{Status: ListingHidden, Reasons: reasons}
+	default:
+		return Listing{Status: ListingHidden, Reasons: []string{fmt.Sprintf("not screened: %s", r.ErrorReason)}}
+	}
+
+	switch {
+	case r.Score >= t.Featured:
+		return Listing{
+			Status:  ListingFeatured,
+			Reasons: []string{fmt.Sprintf("score %.2f >= featured threshold %g", r.Score, t.Featured)},
+		}
+	case r.Score >= t.Visible:
+		return Listing{
+			Status:   ListingVisible,
+			Reasons:  []string{fmt.Sprintf("score %.2f below featured threshold %g", r.Score, t.Featured)},
+			Warnings: r.listingWarnings(),
+		}
+	default:
+		return Listing{
+			Status:  ListingHidden,
+			Reasons: []string{fmt.Sprintf("score %.2f below visible threshold %g", r.Score, t.Visible)},
+		}
+	}
+}
+
+// ListingStatus maps a result onto the featured/visible/hidden listing buckets
+func (r TokenResult) ListingStatus(t ListingThresholds) string {
+	return r.Listing(t).Status
+}
+
+// listingWarnings collects what holds a passed token back from featured
+func (r TokenResult) listingWarnings() []string {
+	warnings := append([]string(nil), r.RiskFactors...)
+	if r.ContractAnalysis != nil {
+		if kinds := r.ContractAnalysis.Kinds(); len(kinds) > 0 {
+			warnings = append(warnings, "contract flags: "+strings.Join(kinds, ", "))
+		}
+	}
+	if r.Proxy != nil {
+		warnings = append(warnings, "upgradeable proxy")
+	}
+	if r.Fragmented {
+		warnings = append(warnings, "liquidity fragmented across pairs (high slippage risk)")
+	}
+	if s := r.TokenScore; s != nil && s.SlippageSimulated && s.SlippageImpactPct >= 3 {
+		warnings = append(warnings, fmt.Sprintf("$1K buy moves the price %.1f%%", s.SlippageImpactPct))
+	}
+	if len(warnings) == 0 {
+		warnings = append(warnings, "score below featured threshold")
+	}
+	return warnings
+}
@@ -0,0 +1,71 @@
+package pipeline_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+)
+
+func TestListingTiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   pipeline.TokenResult
+		status   string
+		reason   string
+		warnings []string
+	}{
+		{
+			name:   "featured",
+			result: pipeline.TokenResult{Status: pipeline.StatusPassed, Score: 82, RiskFactors: []string{"proxy_contract"}},
+			status: pipeline.ListingFeatured,
+			reason: "score 82.00 >= featured threshold 70",
+		},
+		{
+			name: "visible with warnings",
+			result: pipeline.TokenResult{Status: pipeline.StatusPassed, Score: 61, Fragmented: true,
+				RiskFactors: []string{"owner_not_renounced", "high_tax_12.0%"}},
+			status:   pipeline.ListingVisible,
+			reason:   "score 61.00 below featured threshold 70",
+			warnings: []string{"owner_not_renounced", "high_tax_12.0%", "liquidity fragmented across pairs (high slippage risk)"},
+		},
+		{
+			name:     "visible without flags",
+			result:   pipeline.TokenResult{Status: pipeline.StatusPassed, Score: 50},
+			status:   pipeline.ListingVisible,
+			reason:   "score 50.00 below featured threshold 70",
+			warnings: []string{"score below featured threshold"},
+		},
+		{
+			name:   "hidden by score",
+			result: pipeline.TokenResult{Status: pipeline.StatusPassed, Score: 49.9},
+			status: pipeline.ListingHidden,
+			reason: "score 49.90 below visible threshold 50",
+		},
+		{
+			name: "hidden by rejection",
+			result: pipeline.TokenResult{Status: pipeline.StatusFailed, Stage: pipeline.StageScoring,
+				FailureReasons: []string{"Pair too new: 2.0 days < 7 days"}},
+			status: pipeline.ListingHidden,
+			reason: "rejected at scoring stage",
+		},
+		{
+			name:   "hidden by error",
+			result: pipeline.TokenResult{Status: pipeline.StatusError, ErrorReason: "no DEXScreener pairs"},
+			status: pipeline.ListingHidden,
+			reason: "not screened: no DEXScreener pairs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.result.Listing(listing)
+			if l.Status != tt.status || len(l.Reasons) == 0 || l.Reasons[0] != tt.reason {
+				t.Fatalf("listing = %+v, want %s because %q", l, tt.status, tt.reason)
+			}
+			if strings.Join(l.Warnings, "; ") != strings.Join(tt.warnings, "; ") {
+				t.Errorf("warnings = %q, want %q", l.Warnings, tt.warnings)
+			}
+		})
+	}
+}
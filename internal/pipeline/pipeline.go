@@ -0,0 +1,621 @@
+// Package pipeline screens token lists through a bounded goroutine pool
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/analysis"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/contract"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/fraud"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/liquidity"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/market"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/scoring"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/slippage"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// Result statuses
+const (
+	StatusPassed = "PASSED"
+	StatusFailed = "FAILED"
+	StatusError  = "ERROR"
+)
+
+// Pipeline stages, recorded on each result as the stage that decided it
+const (
+	StageDexScreener = "dexscreener"
+	StageThresholds  = "thresholds"
+	StageHolders     = "holders"
+	StageBscScan     = "bscscan"
+	StageLiquidity   = "liquidity" // Liquidity history (drain detection)
+	StageFraud       = "fraud"
+	StageScoring     = "scoring"
+)
+
+// TokenResult is the screening outcome for a single token
+type TokenResult struct {
+	Chain          string                  `json:"chain"`
+	Symbol         string                  `json:"symbol"`
+	Name           string                  `json:"name"`
+	Decimals       int                     `json:"decimals"`
+	Address        string                  `json:"address"`
+	Status         string                  `json:"status"` // "PASSED", "FAILED", "ERROR"
+	Stage          string                  `json:"stage"`  // Stage that produced the final status
+	ErrorReason    string                  `json:"error_reason"`
+	Score          float64                 `json:"score"`
+	Liquidity      float64                 `json:"liquidity_usd"`
+	Volume         float64                 `json:"volume_24h"`
+	Quotes         []market.QuoteLiquidity `json:"quotes,omitempty"` // Liquidity per quote asset
+	Age            float64                 `json:"pool_age_days"`
+	Fragmented     bool                    `json:"fragmented"`
+	Concentration  float64                 `json:"top10_concentration"`
+	HolderSource   string                  `json:"holder_source"` // Where Concentration came from (config.HolderSource*)
+	Verified       bool                    `json:"verified"`
+	FailureReasons []string                `json:"failure_reasons"`
+	RiskFactors    []string                `json:"risk_factors"` // Fraud risk factors
+	Warnings       []string                `json:"warnings"`     // Non-fatal issues (e.g. GoPlus unavailable)
+
+	Fraud              *fraud.FraudResult    `json:"fraud,omitempty"`
+	HolderDistribution *holders.Distribution `json:"holder_distribution,omitempty"` // On-chain holder index
+	HolderBreakdown    *holders.Breakdown    `json:"holder_breakdown,omitempty"`    // Classified provider top-holder list
+	Proxy              *contract.ProxyInfo   `json:"proxy,omitempty"`               // Set when the token is an upgradeable proxy
+	ContractAnalysis   *analysis.Report      `json:"contract_analysis,omitempty"`   // Red flags found in the verified source
+	Slippage           *slippage.Result      `json:"slippage,omitempty"`            // Simulated buys through the chain's DEX router/quoter
+	LiquidityDrain     *liquidity.Assessment `json:"liquidity_drain,omitempty"`     // Liquidity change over the drain windows
+	TokenScore         *scoring.TokenScore   `json:"token_score,omitempty"`
+	CheckedAt          time.Time             `json:"checked_at"`
+}
+
+// Clients bundles the API clients used by the pipeline stages, all bound to one chain
+type Clients struct {
+	Chain       chain.Chain
+	DexScreener *market.DexScreenerClient
+	Holders     *market.HoneyPotClient
+	BscScan     *contract.BscScanClient
+	Honeypot    *fraud.HoneypotClient
+	GoPlus      *fraud.GoPlusClient
+	Slippage    *slippage.Simulator // nil when no RPC endpoint is available
+	HolderIndex *holders.Indexer    // nil when no RPC endpoint is available
+	FraudRules  *fraud.Engine       // Evaluates the Honeypot.is and GoPlus data; nil = fraud.DefaultPolicy
+}
+
+// NewClients creates the API clients for cfg.Chain, sharing one rate-limited
+// transport per provider from the registry. Base URLs set in cfg override the
+// production hosts. The slippage simulator and holder index use cfg.RPCURL,
+// falling back to the chain's public RPC.
+func NewClients(cfg *config.Config, registry *transport.Registry) (Clients, error) {
+	ch, err := chain.Lookup(cfg.Chain)
+	if err != nil {
+		return Clients{}, err
+	}
+	quoteAssets, err := market.ParseQuoteAssets(ch, cfg.QuoteAssets)
+	if err != nil {
+		return Clients{}, err
+	}
+	policy, err := cfg.FraudRules()
+	if err != nil {
+		return Clients{}, err
+	}
+	fraudRules, err := fraud.NewEngine(policy)
+	if err != nil {
+		return Clients{}, err
+	}
+
+	var simulator *slippage.Simulator
+	var holderIndex *holders.Indexer
+	rpcURL := cfg.RPCURL
+	if rpcURL == "" {
+		rpcURL = ch.RPCURL
+	}
+	if rpcURL != "" && rpcURL != config.RPCDisabled {
+		rpcClient := rpc.NewClient(rpcURL, registry.For(transport.ProviderRPC))
+		simulator = slippage.NewSimulator(ch, rpcClient)
+		holderIndex = holders.NewIndexer(ch, rpcClient).
+			WithBlockRange(uint64(max(cfg.LogBlockRange, 0))).
+			WithTopN(cfg.HolderTopN)
+	}
+
+	return Clients{
+		Chain: ch,
+		DexScreener: market.NewDexScreenerClient(ch, registry.For(transport.ProviderDexScreener)).
+			WithBaseURL(cfg.DexScreenerBaseURL).
+			WithQuoteAssets(quoteAssets),
+		Holders: market.NewHoneyPotClient(ch, registry.For(transport.ProviderHoneypot)).
+			WithBaseURL(cfg.HoneypotBaseURL),
+		BscScan: contract.NewBscScanClient(ch, cfg.BscScanAPIKey, registry.For(transport.ProviderEtherscan)).
+			WithBaseURL(cfg.EtherscanBaseURL),
+		Honeypot: fraud.NewHoneypotClient(ch, registry.For(transport.ProviderHoneypot)).
+			WithBaseURL(cfg.HoneypotBaseURL),
+		GoPlus: fraud.NewGoPlusClient(ch, registry.For(transport.ProviderGoPlus)).
+			WithBaseURL(cfg.GoPlusBaseURL),
+		Slippage:    simulator,
+		HolderIndex: holderIndex,
+		FraudRules:  fraudRules,
+	}, nil
+}
+
+// WithCache serves the provider responses of every API client from c while
+// they are fresh. A nil c fetches every time.
+func (c Clients) WithCache(rc *cache.Cache) Clients {
+	c.DexScreener.WithCache(rc)
+	c.Holders.WithCache(rc)
+	c.BscScan.WithCache(rc)
+	c.Honeypot.WithCache(rc)
+	c.GoPlus.WithCache(rc)
+	return c
+}
+
+// Pipeline screens tokens through the market, holder, contract, fraud,
+// slippage and scoring stages, several tokens at a time
+type Pipeline struct {
+	cfg       *config.Config
+	clients   Clients
+	scorer    *scoring.Scorer
+	snapshots liquidity.Store
+	drain     *liquidity.Detector // Nil when drain detection is disabled
+	workers   int
+}
+
+// New creates a pipeline scoring with cfg's weights and thresholds over
+// clients. Liquidity history is kept in memory when drain detection is on.
+func New(cfg *config.Config, clients Clients) *Pipeline {
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	p := &Pipeline{
+		cfg:     cfg,
+		clients: clients,
+		scorer:  cfg.Scorer(),
+		workers: workers,
+	}
+	if cfg.LiquidityDrain.Enabled {
+		p.snapshots = liquidity.NewMemoryStore()
+		p.drain = liquidity.NewDetector(cfg.LiquidityDrain)
+	}
+	return p
+}
+
+// WithScorer replaces the scorer built from the config, e.g. to compare
+// weights over the same tokens
+func (p *Pipeline) WithScorer(s *scoring.Scorer) *Pipeline {
+	p.scorer = s
+	return p
+}
+
+// WithSnapshots keeps liquidity history in store (e.g. Postgres) instead of
+// in memory, so drains are detected across runs
+func (p *Pipeline) WithSnapshots(store liquidity.Store) *Pipeline {
+	p.snapshots = store
+	return p
+}
+
+// Run screens all tokens using the worker pool and returns results in input order.
+// onResult, if non-nil, is called in input order as soon as each result (and all
+// results before it) are available.
+func (p *Pipeline) Run(ctx context.Context, tokens []models.BasicTokenInfo, onResult func(i int, r TokenResult)) []TokenResult {
+	results := make([]TokenResult, len(tokens))
+	done := make([]bool, len(tokens))
+
+	jobs := make(chan int)
+	finished := make(chan int)
+
+	var wg sync.WaitGroup
+	for range p.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = p.ScreenToken(ctx, tokens[i])
+				finished <- i
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range tokens {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	// Emit results in input order as contiguous prefixes complete
+	next := 0
+	for i := range finished {
+		done[i] = true
+		for next < len(tokens) && done[next] {
+			if onResult != nil {
+				onResult(next, results[next])
+			}
+			next++
+		}
+	}
+
+	// Tokens never dispatched because the context was cancelled
+	for i := next; i < len(tokens); i++ {
+		if !done[i] {
+			results[i] = TokenResult{
+				Chain:       p.clients.Chain.Name,
+				Symbol:      tokens[i].Symbol,
+				Address:     tokens[i].Address,
+				Status:      StatusError,
+				ErrorReason: ctx.Err().Error(),
+			}
+		}
+		if onResult != nil {
+			onResult(i, results[i])
+		}
+	}
+
+	return results
+}
+
+// ScreenToken runs every pipeline stage for a single token. Cancelling ctx
+// abandons the provider calls in flight, and the token errors at that stage.
+func (p *Pipeline) ScreenToken(ctx context.Context, tokenInfo models.BasicTokenInfo) TokenResult {
+	cfg := p.cfg
+	result := TokenResult{
+		Chain:     p.clients.Chain.Name,
+		Symbol:    tokenInfo.Symbol,
+		Name:      tokenInfo.Name,
+		Decimals:  tokenInfo.Decimals,
+		Address:   tokenInfo.Address,
+		CheckedAt: time.Now(),
+	}
+
+	// ===== STEP 1: DEXSCREENER - AGGREGATE QUOTE PAIRS + LIQ/VOL =====
+	marketData, err := p.clients.DexScreener.GetMarket(ctx, tokenInfo.Address)
+	if err != nil {
+		return errorResult(result, StageDexScreener, err.Error())
+	}
+	liq, vol := marketData.LiquidityUSD, marketData.Volume24h
+	fragSafe, poolAge := marketData.IsFragmentationSafe, marketData.LargestPoolAgeDays
+
+	result.Liquidity = liq
+	result.Volume = vol
+	result.Quotes = marketData.Quotes
+
+	// Record this cycle's pairs and compare with earlier cycles. A pulled
+	// LP is rejected before the thresholds so the reason is explicit.
+	if p.drain != nil {
+		drain, err := p.liquidityDrain(ctx, tokenInfo.Address, marketData.Pairs, result.CheckedAt)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Liquidity history unavailable: %v", err))
+		}
+		result.LiquidityDrain = drain
+		if drain != nil && drain.Reject {
+			result.Status = StatusFailed
+			result.Stage = StageLiquidity
+			result.FailureReasons = []string{drain.Message}
+			return result
+		}
+	}
+
+	// ===== STEP 2: CHECK LIQ/VOL THRESHOLDS BEFORE FURTHER API CALLS =====
+	if liq < cfg.MinLiquidityUSD || vol < cfg.MinVolume24h {
+		result.Status = StatusFailed
+		result.Stage = StageThresholds
+		result.FailureReasons = []string{
+			fmt.Sprintf("Below minimum thresholds (Liq: $%.0f < $%.0f, Vol: $%.0f < $%.0f)",
+				liq, cfg.MinLiquidityUSD, vol, cfg.MinVolume24h),
+		}
+		return result
+	}
+
+	// ===== STEP 3: HOLDER CONCENTRATION =====
+	var holderConc float64
+	result.HolderSource = config.HolderSourceAPI
+	if cfg.HolderSource == config.HolderSourceOnChain {
+		dist, err := p.onChainHolders(ctx, tokenInfo.Address, marketData.PairAddresses)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("On-chain holder index unavailable, using Honeypot.is: %v", err))
+		} else {
+			result.HolderDistribution = dist
+			result.HolderSource = config.HolderSourceOnChain
+			holderConc = dist.Top10Concentration
+		}
+	}
+	if result.HolderSource == config.HolderSourceAPI {
+		breakdown, err := p.clients.Holders.GetHolderBreakdown(ctx, tokenInfo.Address, marketData.PairAddresses)
+		if err != nil {
+			return errorResult(result, StageHolders, err.Error())
+		}
+		if breakdown != nil {
+			result.HolderBreakdown = breakdown
+			holderConc = breakdown.Top10Concentration
+		} else if cfg.HolderSource == config.HolderSourceAPI && p.clients.HolderIndex != nil {
+			// Honeypot.is lists no holders for the token: index them ourselves
+			if dist, err := p.onChainHolders(ctx, tokenInfo.Address, marketData.PairAddresses); err == nil {
+				result.HolderDistribution = dist
+				result.HolderSource = config.HolderSourceOnChain
+				holderConc = dist.Top10Concentration
+			}
+		}
+	}
+	// Without holder data the concentration is unknown, not zero: the
+	// holder sub-score is withheld rather than awarded
+	holdersUnknown := result.HolderBreakdown == nil && result.HolderDistribution == nil
+	if holdersUnknown {
+		result.Warnings = append(result.Warnings, "Holder data unavailable: holder score set to 0")
+	}
+
+	// ===== STEP 4: CONTRACT VERIFICATION =====
+	source, err := p.clients.BscScan.GetSourceCode(ctx, tokenInfo.Address)
+	if err != nil {
+		return errorResult(result, StageBscScan, err.Error())
+	}
+
+	if !source.IsVerified() {
+		result.Status = StatusFailed
+		result.Stage = StageBscScan
+		result.FailureReasons = []string{"Contract not verified"}
+		return result
+	}
+
+	// Upgradeable proxies hold no token logic: follow them to the
+	// implementation and verify/analyze that instead
+	proxy, err := p.clients.BscScan.ResolveProxy(ctx, tokenInfo.Address, source)
+	if err != nil {
+		return errorResult(result, StageBscScan, fmt.Sprintf("Proxy resolution: %v", err))
+	}
+	if proxy != nil {
+		result.Proxy = proxy
+
+		source, err = p.clients.BscScan.GetSourceCode(ctx, proxy.Implementation)
+		if err != nil {
+			return errorResult(result, StageBscScan, fmt.Sprintf("Implementation source: %v", err))
+		}
+		proxy.ImplementationName = source.ContractName
+		proxy.ImplementationVerified = source.IsVerified()
+
+		if !proxy.ImplementationVerified {
+			result.Status = StatusFailed
+			result.Stage = StageBscScan
+			result.FailureReasons = []string{fmt.Sprintf("Proxy implementation not verified (%s)", proxy.Implementation)}
+			return result
+		}
+
+		upgrader := proxy.Upgrader
+		if upgrader == "" {
+			upgrader = "unknown"
+		}
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("Upgradeable proxy: implementation %s (%s), upgrader %s", proxy.Implementation, source.ContractName, upgrader))
+	}
+	result.Verified = true
+
+	// Scan the verified (implementation) source for privileged functions and owner controls
+	report, err := analysis.AnalyzeSource(source.SourceCode)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Source analysis failed: %v", err))
+	}
+	result.ContractAnalysis = report
+
+	// ===== STEP 5: FRAUD DETECTION (HONEYPOT + GOPLUS) =====
+	honeypotData, err := p.clients.Honeypot.CheckToken(ctx, tokenInfo.Address)
+	if err != nil {
+		return errorResult(result, StageFraud, fmt.Sprintf("Honeypot API: %v", err))
+	}
+
+	goplusData, err := p.clients.GoPlus.CheckToken(ctx, tokenInfo.Address)
+	if err == nil {
+		goplusData.ClassifyHolders(holders.NewClassifier(p.clients.Chain, marketData.PairAddresses))
+	} else {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("GoPlus unavailable: %v", err))
+
+		// Create safe default (assume best case)
+		goplusData = &fraud.GoPlusData{
+			IsOpenSource:       true,       // Already verified on BscScan
+			Top10Concentration: holderConc, // Will get from Honeypot API
+		}
+	}
+
+	// Aggregate fraud results: every rule in the policy is evaluated
+	var fraudResult *fraud.FraudResult
+	if p.clients.FraudRules != nil {
+		fraudResult = p.clients.FraudRules.Evaluate(honeypotData, goplusData)
+	} else {
+		fraudResult = fraud.AggregateFraudCheck(honeypotData, goplusData)
+	}
+	result.Fraud = fraudResult
+	result.RiskFactors = fraudResult.RiskFactors
+	if result.LiquidityDrain != nil && result.LiquidityDrain.Warn {
+		result.RiskFactors = append(append([]string(nil), result.RiskFactors...), liquidity.RiskFactor)
+	}
+
+	// If fraud detected, REJECT immediately (don't even score)
+	if !fraudResult.IsSafe {
+		result.Status = StatusFailed
+		result.Stage = StageFraud
+		for _, hit := range fraudResult.Rejections() {
+			result.FailureReasons = append(result.FailureReasons, hit.Message)
+		}
+		return result
+	}
+
+	// ===== STEP 6: SLIPPAGE SIMULATION (ROUTER/QUOTER OVER RPC) =====
+	if p.clients.Slippage != nil {
+		sim, err := p.clients.Slippage.Simulate(ctx, tokenInfo.Address)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Slippage simulation failed: %v", err))
+		}
+		result.Slippage = sim
+	}
+
+	// ===== STEP 7: CALCULATE SCORE =====
+	in := scoring.Input{
+		Verified:           result.Verified,
+		LiquidityUSD:       liq,
+		Volume24hUSD:       vol,
+		Top10HolderPercent: holderConc,
+		HoldersUnknown:     holdersUnknown,
+		FragmentationSafe:  fragSafe,
+		FragmentationBasis: marketData.FragmentationBasis,
+		PairAgeDays:        poolAge,
+		FraudRiskScore:     fraudResult.RiskScore,
+		FraudRiskFactors:   fraudResult.RiskFactors,
+	}
+	if report != nil {
+		in.ContractScore = report.Score(fraudResult.HasOwner)
+		in.ContractAnalyzed = true
+		in.ContractFlags = report.Kinds()
+	}
+	if result.Slippage != nil {
+		in.SlippageImpactPct, in.SlippageSimulated = result.Slippage.Impact(slippage.ScoredTradeUSD)
+	}
+	scoreResult := p.scorer.Score(in)
+	safe := scoreResult.IsSafe
+
+	result.Stage = StageScoring
+	result.Age = poolAge
+	result.Fragmented = !fragSafe
+	result.Concentration = holderConc
+	result.Score = scoreResult.CompositeScore
+	result.FailureReasons = scoreResult.FailureReasons
+	result.TokenScore = &scoreResult
+
+	if safe {
+		result.Status = StatusPassed
+	} else {
+		result.Status = StatusFailed
+	}
+
+	return result
+}
+
+// liquidityDrain saves the token's pairs as one snapshot cycle and assesses
+// its history, this cycle included
+func (p *Pipeline) liquidityDrain(ctx context.Context, address string, pairs []market.PairMarket, at time.Time) (*liquidity.Assessment, error) {
+	token := strings.ToLower(address)
+
+	snapshots := make([]liquidity.Snapshot, len(pairs))
+	for i, pair := range pairs {
+		snapshots[i] = liquidity.Snapshot{
+			Chain:        p.clients.Chain.Name,
+			Token:        token,
+			Pair:         pair.Address,
+			DexID:        pair.DexID,
+			Quote:        pair.Quote,
+			LiquidityUSD: pair.LiquidityUSD,
+			Volume24hUSD: pair.Volume24h,
+			PriceUSD:     pair.PriceUSD,
+			At:           at,
+		}
+	}
+	if err := p.snapshots.SaveSnapshots(ctx, snapshots); err != nil {
+		return nil, err
+	}
+
+	history, err := p.snapshots.History(ctx, p.clients.Chain.Name, token, at.Add(-p.cfg.LiquidityDrain.Lookback()))
+	if err != nil {
+		return nil, err
+	}
+	return p.drain.Assess(history), nil
+}
+
+// onChainHolders indexes the token's Transfer logs from its creation block,
+// excluding burn, locker and exchange addresses and the token's DEX pairs
+func (p *Pipeline) onChainHolders(ctx context.Context, address string, pairs []string) (*holders.Distribution, error) {
+	if p.clients.HolderIndex == nil {
+		return nil, fmt.Errorf("no RPC endpoint")
+	}
+	fromBlock, err := p.clients.BscScan.GetCreationBlock(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("creation block: %w", err)
+	}
+	return p.clients.HolderIndex.Distribution(ctx, address, fromBlock, pairs)
+}
+
+func errorResult(result TokenResult, stage, reason string) TokenResult {
+	result.Status = StatusError
+	result.Stage = stage
+	result.ErrorReason = reason
+	return result
+}
+
+// Statistics summarises a screening run
+type Statistics struct {
+	TotalTokens       int `json:"total_tokens"`
+	ErrorCount        int `json:"error_count"`
+	EvaluatedCount    int `json:"evaluated_count"`
+	PassedCount       int `json:"passed_count"`
+	FailedCount       int `json:"failed_count"`
+	NoQuotePairs      int `json:"no_quote_pairs"` // No pair against any supported quote asset
+	NoDexScreenerData int `json:"no_dexscreener_data"`
+	FraudAPIErrors    int `json:"fraud_api_errors"`  // Track fraud API failures
+	HoneypotRejected  int `json:"honeypot_rejected"` // Track honeypot rejections
+	OtherErrors       int `json:"other_errors"`
+
+	// Listing tiers
+	FeaturedCount int `json:"featured_count"`
+	VisibleCount  int `json:"visible_count"` // Listed with warnings
+	HiddenCount   int `json:"hidden_count"`  // Includes failed and errored tokens
+}
+
+// Summarize computes run statistics from the collected results
+func Summarize(results []TokenResult, listing ListingThresholds) Statistics {
+	stats := Statistics{
+		TotalTokens: len(results),
+	}
+
+	for _, r := range results {
+		switch r.ListingStatus(listing) {
+		case ListingFeatured:
+			stats.FeaturedCount++
+		case ListingVisible:
+			stats.VisibleCount++
+		default:
+			stats.HiddenCount++
+		}
+
+		// Every token that got past DexScreener was evaluated
+		if r.Stage != StageDexScreener && r.Stage != "" {
+			stats.EvaluatedCount++
+		}
+
+		switch r.Status {
+		case StatusError:
+			stats.ErrorCount++
+
+			// Categorize error type
+			switch {
+			case r.Stage == StageDexScreener && strings.Contains(r.ErrorReason, "no supported quote pairs"):
+				stats.NoQuotePairs++
+			case r.Stage == StageDexScreener && strings.Contains(r.ErrorReason, "no DEXScreener pairs"):
+				stats.NoDexScreenerData++
+			case r.Stage == StageFraud:
+				stats.FraudAPIErrors++
+			default:
+				stats.OtherErrors++
+			}
+		case StatusFailed:
+			stats.FailedCount++
+			if r.Fraud != nil && !r.Fraud.IsSafe && r.Fraud.IsHoneypot {
+				stats.HoneypotRejected++
+			}
+		case StatusPassed:
+			stats.PassedCount++
+		}
+	}
+
+	return stats
+}
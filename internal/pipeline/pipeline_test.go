@@ -0,0 +1,448 @@
+package pipeline_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/analysis"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/cache"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/contract"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/liquidity"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+const (
+	avl      = "0x9beee89723ceec27d7c2834bec6834208ffdc202"
+	cake     = "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"
+	doge     = "0xba2ae424d960c26247dd6c32edc70b295c744c43"
+	wbnbOnly = "0x33c7d0387e25964f65497cc92637c0ec32944444"
+	absent   = "0x834baf4f7832cc3c00734ddb2e0c61c68d975822"
+	lowLiq   = "0x73cf73c2503154de4dc12067546aa9357dadaff2"
+	taxed    = "0x00000000000000000000000000000000000000aa"
+	thin     = "0x00000000000000000000000000000000000000dd"
+)
+
+// newTestPipeline wires a pipeline to the fixture server with no rate
+// limiting and no response cache. opts adjust the config before the clients
+// are created.
+func newTestPipeline(t *testing.T, opts ...func(*config.Config)) (*pipeline.Pipeline, *mockapi.Server) {
+	t.Helper()
+
+	srv := mockapi.New()
+	t.Cleanup(srv.Close)
+
+	p, rc := newPipelineFor(t, srv, opts...)
+	t.Cleanup(func() { rc.Close() })
+	return p, srv
+}
+
+// newPipelineFor wires a pipeline to srv, returning the response cache it
+// opened for the caller to close
+func newPipelineFor(t *testing.T, srv *mockapi.Server, opts ...func(*config.Config)) (*pipeline.Pipeline, *cache.Cache) {
+	t.Helper()
+
+	// Default thresholds and weights, fixture hosts
+	cfg := config.Default()
+	cfg.BscScanAPIKey = "test"
+	cfg.Workers = 4
+	cfg.DexScreenerBaseURL = srv.DexScreenerURL()
+	cfg.HoneypotBaseURL = srv.HoneypotURL()
+	cfg.GoPlusBaseURL = srv.GoPlusURL()
+	cfg.EtherscanBaseURL = srv.EtherscanURL()
+	cfg.RPCURL = srv.RPCURL()
+	cfg.Cache.Enabled = false
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	offline := transport.Limits{MaxRetries: 0, Timeout: 5 * time.Second}
+	registry := transport.NewRegistry(map[string]transport.Limits{
+		transport.ProviderDexScreener: offline,
+		transport.ProviderHoneypot:    offline,
+		transport.ProviderGoPlus:      offline,
+		transport.ProviderEtherscan:   offline,
+		transport.ProviderRPC:         offline,
+	})
+
+	clients, err := pipeline.NewClients(cfg, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := cache.Open(cfg.Cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pipeline.New(cfg, clients.WithCache(rc)), rc
+}
+
+// listing are the default tier thresholds
+var listing = pipeline.ListingThresholds{Featured: 70, Visible: 50}
+
+func TestPipelineEndToEnd(t *testing.T) {
+	p, srv := newTestPipeline(t)
+
+	tests := []struct {
+		symbol  string
+		address string
+		status  string
+		stage   string
+		reason  string // substring of the error or first failure reason
+	}{
+		{"AVL", avl, pipeline.StatusFailed, pipeline.StageFraud, "Honeypot detected"},
+		{"CAKE", cake, pipeline.StatusPassed, pipeline.StageScoring, ""},
+		{"DOGE", doge, pipeline.StatusFailed, pipeline.StageBscScan, "Contract not verified"},
+		{"8", wbnbOnly, pipeline.StatusFailed, pipeline.StageThresholds, "Below minimum thresholds"},
+		{"42", absent, pipeline.StatusError, pipeline.StageDexScreener, "no DEXScreener pairs"},
+		{"42", lowLiq, pipeline.StatusFailed, pipeline.StageThresholds, "Below minimum thresholds"},
+		{"TAXED", taxed, pipeline.StatusFailed, pipeline.StageFraud, "Excessive tax"},
+	}
+
+	tokens := make([]models.BasicTokenInfo, len(tests))
+	for i, tt := range tests {
+		tokens[i] = models.BasicTokenInfo{Address: tt.address, Symbol: tt.symbol, Decimals: 18}
+	}
+
+	var emitted []int
+	results := p.Run(context.Background(), tokens, func(i int, r pipeline.TokenResult) {
+		emitted = append(emitted, i)
+	})
+
+	if len(results) != len(tests) {
+		t.Fatalf("got %d results, want %d", len(results), len(tests))
+	}
+	for i := range emitted {
+		if emitted[i] != i {
+			t.Fatalf("results emitted out of order: %v", emitted)
+		}
+	}
+
+	for i, tt := range tests {
+		r := results[i]
+		t.Run(tt.symbol+"_"+tt.address[:8], func(t *testing.T) {
+			if r.Address != tt.address {
+				t.Fatalf("result %d is for %s, want %s", i, r.Address, tt.address)
+			}
+			if r.Status != tt.status || r.Stage != tt.stage {
+				t.Fatalf("got %s at %s (%s %v), want %s at %s",
+					r.Status, r.Stage, r.ErrorReason, r.FailureReasons, tt.status, tt.stage)
+			}
+
+			reason := r.ErrorReason
+			if len(r.FailureReasons) > 0 {
+				reason = r.FailureReasons[0]
+			}
+			if !strings.Contains(reason, tt.reason) {
+				t.Errorf("reason %q does not contain %q", reason, tt.reason)
+			}
+		})
+	}
+
+	cakeResult := results[1]
+	if got := cakeResult.ListingStatus(listing); got != pipeline.ListingFeatured {
+		t.Errorf("CAKE listing = %s (score %.2f), want featured", got, cakeResult.Score)
+	}
+
+	// CAKE's owner-gated mint is reported by the source analyzer
+	if a := cakeResult.ContractAnalysis; a == nil || len(a.Kinds()) != 1 || a.Kinds()[0] != analysis.KindPrivilegedMint {
+		t.Errorf("CAKE contract analysis = %+v, want privileged_mint", cakeResult.ContractAnalysis)
+	}
+
+	// Only tokens that pass the fraud checks are simulated on-chain
+	if sim := cakeResult.Slippage; sim == nil || len(sim.Trades) != 2 {
+		t.Errorf("CAKE slippage = %+v, want $1K and $10K trades", cakeResult.Slippage)
+	} else if s := cakeResult.TokenScore; !s.SlippageSimulated || s.SlippageScore != 100 {
+		t.Errorf("CAKE slippage score = %.0f (impact %.2f%%), want 100", s.SlippageScore, s.SlippageImpactPct)
+	}
+	if results[0].Slippage != nil {
+		t.Error("AVL was simulated despite failing the fraud checks")
+	}
+
+	// AVL is a proxy: resolved through the EIP-1967 slots, with the
+	// implementation's source analyzed instead of the proxy's
+	avlResult := results[0]
+	if px := avlResult.Proxy; px == nil {
+		t.Error("AVL proxy not resolved")
+	} else {
+		if px.Implementation != "0x00000000000000000000000000000000000000a1" || px.ResolvedBy != contract.ResolvedBySlot {
+			t.Errorf("AVL implementation = %s via %s", px.Implementation, px.ResolvedBy)
+		}
+		if px.Admin != "0x00000000000000000000000000000000000000a2" || px.Upgrader != "0x00000000000000000000000000000000000000a3" {
+			t.Errorf("AVL admin = %s, upgrader = %s", px.Admin, px.Upgrader)
+		}
+	}
+	if a := avlResult.ContractAnalysis; a == nil || len(a.Kinds()) != 2 {
+		t.Errorf("AVL implementation analysis = %+v, want fee_setter and blacklist", avlResult.ContractAnalysis)
+	}
+	// The fail rate rejects it once, however many rules look at it
+	if len(avlResult.FailureReasons) != 1 {
+		t.Errorf("AVL failure reasons = %q, want the fail-rate rejection only", avlResult.FailureReasons)
+	}
+
+	// Tokens traded only against WBNB are evaluated on their WBNB liquidity
+	for _, r := range results {
+		if r.Chain != "bsc" {
+			t.Errorf("%s chain = %q, want bsc", r.Symbol, r.Chain)
+		}
+	}
+
+	if q := results[3].Quotes; len(q) != 1 || q[0].Symbol != "WBNB" || q[0].LiquidityUSD == 0 {
+		t.Errorf("WBNB-only quotes = %+v", q)
+	}
+
+	stats := pipeline.Summarize(results, listing)
+	want := pipeline.Statistics{
+		TotalTokens:       7,
+		ErrorCount:        1,
+		EvaluatedCount:    6,
+		PassedCount:       1,
+		FailedCount:       5,
+		NoDexScreenerData: 1,
+		HoneypotRejected:  1,
+		FeaturedCount:     1,
+		HiddenCount:       6,
+	}
+	if stats != want {
+		t.Errorf("Summarize = %+v, want %+v", stats, want)
+	}
+
+	// Below-threshold tokens must not reach the paid APIs
+	// (AVL's implementation is fetched in addition to the proxy)
+	if got := srv.Hits("etherscan/getsourcecode"); got != 5 {
+		t.Errorf("getsourcecode called %d times, want 5", got)
+	}
+}
+
+func TestPipelineLiquidityDrain(t *testing.T) {
+	p, _ := newTestPipeline(t)
+	store := liquidity.NewMemoryStore()
+	p.WithSnapshots(store)
+	token := models.BasicTokenInfo{Address: cake, Symbol: "CAKE", Decimals: 18}
+
+	// The first cycle has no history to compare with
+	first := p.ScreenToken(context.Background(), token)
+	if first.Status != pipeline.StatusPassed || first.LiquidityDrain == nil || len(first.LiquidityDrain.Deltas) != 0 {
+		t.Fatalf("first cycle: %s at %s, drain %+v", first.Status, first.Stage, first.LiquidityDrain)
+	}
+
+	// 100 minutes ago the pairs held 40% more: a warning, not a rejection
+	seed := func(factor float64, ago time.Duration) {
+		err := store.SaveSnapshots(context.Background(), []liquidity.Snapshot{{
+			Chain: "bsc", Token: cake, Pair: "0xpair", LiquidityUSD: first.Liquidity * factor, At: time.Now().Add(-ago),
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	seed(1.4, 100*time.Minute)
+	r := p.ScreenToken(context.Background(), token)
+	if r.Status != pipeline.StatusPassed || !r.LiquidityDrain.Warn || !containsString(r.RiskFactors, liquidity.RiskFactor) {
+		t.Errorf("warned cycle: %s, drain %+v, risk factors %v", r.Status, r.LiquidityDrain, r.RiskFactors)
+	}
+
+	// Ten times the liquidity an hour and a half ago: the LP was pulled
+	seed(10, 90*time.Minute)
+	r = p.ScreenToken(context.Background(), token)
+	if r.Status != pipeline.StatusFailed || r.Stage != pipeline.StageLiquidity ||
+		!strings.HasPrefix(r.FailureReasons[0], "Liquidity drained: -90.0% over 1h") {
+		t.Errorf("drained cycle: %s at %s, %v", r.Status, r.Stage, r.FailureReasons)
+	}
+}
+
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPipelineOnChainHolders(t *testing.T) {
+	p, srv := newTestPipeline(t, func(cfg *config.Config) {
+		cfg.HolderSource = config.HolderSourceOnChain
+		cfg.MinLiquidityUSD = 10000
+	})
+
+	// THIN has a $40K pool; its holders are indexed from Transfer logs
+	// before the unverified contract rejects it
+	r := p.ScreenToken(context.Background(), models.BasicTokenInfo{Address: thin, Symbol: "THIN", Decimals: 18})
+	if r.Status != pipeline.StatusFailed || r.Stage != pipeline.StageBscScan {
+		t.Fatalf("got %s at %s (%s %v)", r.Status, r.Stage, r.ErrorReason, r.Warnings)
+	}
+	if r.HolderSource != config.HolderSourceOnChain || r.HolderDistribution == nil {
+		t.Fatalf("holder source = %s, warnings = %v", r.HolderSource, r.Warnings)
+	}
+	// The DexScreener pair is excluded alongside the burn and locker addresses
+	if d := r.HolderDistribution; d.HolderCount != 6 || len(d.Excluded) != 3 || d.Excluded[0].Label != "pair" {
+		t.Errorf("distribution = %d holders, excluded %+v", d.HolderCount, d.Excluded)
+	}
+	if got := srv.Hits("topholders"); got != 0 {
+		t.Errorf("Honeypot.is top holders called %d times with the on-chain source", got)
+	}
+
+	// Without an index (no creation record) the API is used instead
+	r = p.ScreenToken(context.Background(), models.BasicTokenInfo{Address: doge, Symbol: "DOGE", Decimals: 18})
+	if r.HolderSource != config.HolderSourceAPI || len(r.Warnings) == 0 || !strings.Contains(r.Warnings[0], "On-chain holder index unavailable") {
+		t.Errorf("fallback: source = %s, warnings = %v", r.HolderSource, r.Warnings)
+	}
+}
+
+// withoutFixtures hides fixtures, as if the provider had no data for them
+type withoutFixtures struct {
+	fs.FS
+	hidden []string
+}
+
+func (f withoutFixtures) Open(name string) (fs.File, error) {
+	if slices.Contains(f.hidden, name) {
+		return nil, fs.ErrNotExist
+	}
+	return f.FS.Open(name)
+}
+
+func TestPipelineMissingHolders(t *testing.T) {
+	// THIN has no Honeypot.is holder list, so its holders are indexed from
+	// Transfer logs even with the API source
+	p, srv := newTestPipeline(t, func(cfg *config.Config) { cfg.MinLiquidityUSD = 10000 })
+	r := p.ScreenToken(context.Background(), models.BasicTokenInfo{Address: thin, Symbol: "THIN", Decimals: 18})
+	if r.HolderSource != config.HolderSourceOnChain || r.HolderDistribution == nil || len(r.Warnings) != 0 {
+		t.Errorf("THIN holder source = %s, warnings = %v", r.HolderSource, r.Warnings)
+	}
+	if got := srv.Hits("topholders"); got != 1 {
+		t.Errorf("Honeypot.is top holders called %d times, want 1", got)
+	}
+
+	// Without the list or a creation record to index from, CAKE still passes
+	// but its concentration does not count as 0%
+	empty := mockapi.NewWithFS(withoutFixtures{os.DirFS("../mockapi/fixtures"), []string{
+		"topholders/" + cake + ".json",
+		"etherscan/getcontractcreation/" + cake + ".json",
+	}})
+	defer empty.Close()
+	p, rc := newPipelineFor(t, empty)
+	defer rc.Close()
+	r = p.ScreenToken(context.Background(), models.BasicTokenInfo{Address: cake, Symbol: "CAKE", Decimals: 18})
+	if r.Status != pipeline.StatusPassed || r.HolderBreakdown != nil || r.HolderDistribution != nil {
+		t.Fatalf("got %s at %s (%s %v)", r.Status, r.Stage, r.ErrorReason, r.FailureReasons)
+	}
+	if !containsString(r.Warnings, "Holder data unavailable: holder score set to 0") {
+		t.Errorf("warnings = %v", r.Warnings)
+	}
+	if s := r.TokenScore; s.HolderScore != 0 || s.Components[2].Basis != "no holder data" {
+		t.Errorf("holder score = %.0f (%s), want 0", s.HolderScore, s.Components[2].Basis)
+	}
+}
+
+func TestPipelineResponseCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	withCache := func(cfg *config.Config) {
+		cfg.Cache.Enabled = true
+		cfg.Cache.Path = path
+	}
+	tokens := []models.BasicTokenInfo{
+		{Address: cake, Symbol: "CAKE", Decimals: 18},
+		{Address: doge, Symbol: "DOGE", Decimals: 18},
+	}
+	endpoints := []string{"dexscreener", "honeypot", "goplus", "etherscan/getsourcecode", "etherscan/getcontractcreation"}
+
+	// Entries are kept per host, so every run talks to the same server
+	srv := mockapi.New()
+	defer srv.Close()
+	p, rc := newPipelineFor(t, srv, withCache)
+	hits := func() map[string]int {
+		calls := map[string]int{}
+		for _, endpoint := range endpoints {
+			calls[endpoint] = srv.Hits(endpoint)
+		}
+		return calls
+	}
+
+	first := p.Run(context.Background(), tokens, nil)
+	rc.Close()
+	calls := hits()
+	if calls["etherscan/getsourcecode"] == 0 || calls["dexscreener"] != 2 {
+		t.Fatalf("first run calls %v", calls)
+	}
+
+	// A second process with the same cache file screens from disk. DOGE has
+	// no top holders and no creation record to index them from; a missing
+	// record is not kept, so only that lookup is repeated.
+	p, rc = newPipelineFor(t, srv, withCache)
+	second := p.Run(context.Background(), tokens, nil)
+	rc.Close()
+	repeated := map[string]int{"etherscan/getcontractcreation": 1}
+	for endpoint, got := range hits() {
+		if got-calls[endpoint] != repeated[endpoint] {
+			t.Errorf("%s called %d times on the cached run", endpoint, got-calls[endpoint])
+		}
+	}
+	for i := range first {
+		if first[i].Status != second[i].Status || first[i].Score != second[i].Score {
+			t.Errorf("%s: cached run got %s %.1f, first run %s %.1f",
+				tokens[i].Symbol, second[i].Status, second[i].Score, first[i].Status, first[i].Score)
+		}
+	}
+
+	// -refresh fetches again
+	p, rc = newPipelineFor(t, srv, withCache, func(cfg *config.Config) { cfg.Cache.Refresh = true })
+	p.Run(context.Background(), tokens, nil)
+	rc.Close()
+	if got := srv.Hits("dexscreener") - calls["dexscreener"]; got != 2 {
+		t.Errorf("refresh run called dexscreener %d times, want 2", got)
+	}
+
+	// Another host does not share the entries, and -no-cache (the default
+	// here) never reads the file
+	for _, opt := range []func(*config.Config){withCache, func(*config.Config) {}} {
+		p, other := newTestPipeline(t, opt)
+		p.Run(context.Background(), tokens, nil)
+		if got := other.Hits("etherscan/getsourcecode"); got != calls["etherscan/getsourcecode"] {
+			t.Errorf("uncached run called getsourcecode %d times, want %d", got, calls["etherscan/getsourcecode"])
+		}
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	p, srv := newTestPipeline(t)
+	tokens := []models.BasicTokenInfo{
+		{Address: cake, Symbol: "CAKE", Decimals: 18},
+		{Address: doge, Symbol: "DOGE", Decimals: 18},
+		{Address: avl, Symbol: "AVL", Decimals: 18},
+	}
+
+	// A cancelled context makes no provider calls: tokens either error on
+	// their first stage or are never dispatched
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var emitted []int
+	results := p.Run(ctx, tokens, func(i int, r pipeline.TokenResult) {
+		emitted = append(emitted, i)
+	})
+
+	if len(results) != len(tokens) || len(emitted) != len(tokens) {
+		t.Fatalf("%d results, %d emitted for %d tokens", len(results), len(emitted), len(tokens))
+	}
+	for i, r := range results {
+		if emitted[i] != i {
+			t.Errorf("results emitted out of order: %v", emitted)
+		}
+		if r.Address != tokens[i].Address || r.Status != pipeline.StatusError || !strings.Contains(r.ErrorReason, "context canceled") {
+			t.Errorf("result %d = %s %s: %s, want a cancelled error", i, r.Address, r.Status, r.ErrorReason)
+		}
+	}
+	if got := srv.Hits("dexscreener"); got != 0 {
+		t.Errorf("dexscreener called %d times after cancellation", got)
+	}
+}
@@ -0,0 +1,261 @@
+// Package report writes machine-readable screening output (JSON Lines, CSV
+// and a summary JSON) alongside the human-readable text report
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/analysis"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// Output formats selectable with the -format flag
+const (
+	FormatText    = "text"
+	FormatJSONL   = "jsonl"
+	FormatCSV     = "csv"
+	FormatSummary = "summary"
+)
+
+// ParseFormats parses a comma-separated format list such as "jsonl,csv"
+func ParseFormats(value string) (map[string]bool, error) {
+	formats := map[string]bool{}
+	for _, f := range strings.Split(value, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		switch f {
+		case "":
+		case FormatText, FormatJSONL, FormatCSV, FormatSummary:
+			formats[f] = true
+		default:
+			return nil, fmt.Errorf("unknown output format %q (want text, jsonl, csv or summary)", f)
+		}
+	}
+	return formats, nil
+}
+
+// ResultWriter streams per-token results in a structured format
+type ResultWriter interface {
+	WriteResult(r pipeline.TokenResult) error
+	Flush() error
+}
+
+// JSONLWriter writes one JSON document per token
+type JSONLWriter struct {
+	enc     *json.Encoder
+	listing pipeline.ListingThresholds
+}
+
+func NewJSONLWriter(w io.Writer, listing pipeline.ListingThresholds) *JSONLWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONLWriter{enc: enc, listing: listing}
+}
+
+// jsonlRecord adds the derived listing tier to the full result
+type jsonlRecord struct {
+	ListingStatus string           `json:"listing_status"`
+	Listing       pipeline.Listing `json:"listing"`
+	pipeline.TokenResult
+}
+
+func (j *JSONLWriter) WriteResult(r pipeline.TokenResult) error {
+	listing := r.Listing(j.listing)
+	return j.enc.Encode(jsonlRecord{
+		ListingStatus: listing.Status,
+		Listing:       listing,
+		TokenResult:   r,
+	})
+}
+
+func (j *JSONLWriter) Flush() error {
+	return nil
+}
+
+// csvHeader lists the CSV columns. List-valued fields are joined with " | ".
+var csvHeader = []string{
+	"chain", "symbol", "name", "address", "status", "stage", "listing_status", "listing_reasons", "listing_warnings",
+	"error_reason",
+	"failure_reasons", "risk_factors", "warnings",
+	"score", "liquidity_score", "volume_score", "holder_score", "fragmentation_score", "contract_score",
+	"fraud_score", "slippage_score", "slippage_1k_impact_pct",
+	"liquidity_usd", "quote_liquidity", "volume_24h", "pool_age_days", "fragmented", "top10_concentration", "verified",
+	"holder_source", "holder_count", "holder_gini", "holder_classes",
+	"contract_findings", "proxy_implementation", "proxy_upgrader",
+	"is_honeypot", "fraud_risk_score", "max_buy_tax", "max_sell_tax", "max_transfer_tax",
+	"holder_fail_rate", "creator_percent", "is_proxy", "has_owner",
+	"honeypot_is_honeypot", "honeypot_reason", "honeypot_risk_level", "honeypot_total_holders",
+	"honeypot_failed_sells", "honeypot_flags",
+	"goplus_holder_count", "goplus_top10_concentration", "goplus_lp_holder_count",
+	"goplus_cannot_buy", "goplus_cannot_sell_all", "goplus_honeypot_with_creator",
+	"checked_at",
+}
+
+// CSVWriter writes one row per token with untruncated reasons
+type CSVWriter struct {
+	w           *csv.Writer
+	listing     pipeline.ListingThresholds
+	wroteHeader bool
+}
+
+func NewCSVWriter(w io.Writer, listing pipeline.ListingThresholds) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w), listing: listing}
+}
+
+func (c *CSVWriter) WriteResult(r pipeline.TokenResult) error {
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	listing := r.Listing(c.listing)
+	row := []string{
+		r.Chain, r.Symbol, r.Name, r.Address, r.Status, r.Stage,
+		listing.Status, joinList(listing.Reasons), joinList(listing.Warnings), r.ErrorReason,
+		joinList(r.FailureReasons), joinList(r.RiskFactors), joinList(r.Warnings),
+		formatFloat(r.Score),
+	}
+
+	if s := r.TokenScore; s != nil {
+		row = append(row, formatFloat(s.LiquidityScore), formatFloat(s.VolumeScore),
+			formatFloat(s.HolderScore), formatFloat(s.FragmentationScore), formatFloat(s.ContractScore),
+			formatFloat(s.FraudScore))
+	} else {
+		row = append(row, "", "", "", "", "", "")
+	}
+	if s := r.TokenScore; s != nil && s.SlippageSimulated {
+		row = append(row, formatFloat(s.SlippageScore), formatFloat(s.SlippageImpactPct))
+	} else {
+		row = append(row, "", "")
+	}
+
+	row = append(row,
+		formatFloat(r.Liquidity), quoteLiquidity(r), formatFloat(r.Volume), formatFloat(r.Age),
+		strconv.FormatBool(r.Fragmented), formatFloat(r.Concentration), strconv.FormatBool(r.Verified),
+	)
+	if d := r.HolderDistribution; d != nil {
+		row = append(row, r.HolderSource, strconv.Itoa(d.HolderCount), formatFloat(d.Gini), holderClasses(d.Classes))
+	} else if b := r.HolderBreakdown; b != nil {
+		row = append(row, r.HolderSource, "", "", holderClasses(b.Classes))
+	} else {
+		row = append(row, r.HolderSource, "", "", "")
+	}
+	row = append(row, contractFindings(r))
+	if p := r.Proxy; p != nil {
+		row = append(row, p.Implementation, p.Upgrader)
+	} else {
+		row = append(row, "", "")
+	}
+
+	var honeypotCols, goplusCols []string
+	if f := r.Fraud; f != nil {
+		row = append(row,
+			strconv.FormatBool(f.IsHoneypot), strconv.Itoa(f.RiskScore),
+			formatFloat(f.MaxBuyTax), formatFloat(f.MaxSellTax), formatFloat(f.MaxTransferTax),
+			formatFloat(f.HolderFailRate), formatFloat(f.CreatorPercent),
+			strconv.FormatBool(f.IsProxy), strconv.FormatBool(f.HasOwner),
+		)
+		if h := f.HoneypotData; h != nil {
+			honeypotCols = []string{
+				strconv.FormatBool(h.IsHoneypot), h.HoneypotReason, strconv.Itoa(h.RiskLevel),
+				strconv.Itoa(h.TotalHolders), strconv.Itoa(h.FailedSells), joinList(h.Flags),
+			}
+		}
+		if g := f.GoPlusData; g != nil {
+			goplusCols = []string{
+				strconv.Itoa(g.HolderCount), formatFloat(g.Top10Concentration), strconv.Itoa(g.LPHolderCount),
+				strconv.FormatBool(g.CannotBuy), strconv.FormatBool(g.CannotSellAll),
+				strconv.FormatBool(g.HoneypotWithCreator),
+			}
+		}
+	} else {
+		row = append(row, make([]string, 9)...)
+	}
+
+	if honeypotCols == nil {
+		honeypotCols = make([]string, 6)
+	}
+	if goplusCols == nil {
+		goplusCols = make([]string, 6)
+	}
+	row = append(row, honeypotCols...)
+	row = append(row, goplusCols...)
+	row = append(row, r.CheckedAt.Format(time.RFC3339))
+
+	return c.w.Write(row)
+}
+
+// quoteLiquidity renders the per-quote breakdown as "SYMBOL:liquidity_usd"
+func quoteLiquidity(r pipeline.TokenResult) string {
+	parts := make([]string, len(r.Quotes))
+	for i, q := range r.Quotes {
+		parts[i] = q.Symbol + ":" + formatFloat(q.LiquidityUSD)
+	}
+	return joinList(parts)
+}
+
+// holderClasses renders class shares as "class:percent:holders"
+func holderClasses(classes []holders.ClassShare) string {
+	parts := make([]string, len(classes))
+	for i, c := range classes {
+		parts[i] = fmt.Sprintf("%s:%.2f:%d", c.Label, c.Percent, c.Holders)
+	}
+	return joinList(parts)
+}
+
+// contractFindings renders source-analysis findings as "severity:kind:function"
+func contractFindings(r pipeline.TokenResult) string {
+	if r.ContractAnalysis == nil {
+		return ""
+	}
+	var parts []string
+	for _, f := range r.ContractAnalysis.Findings {
+		if f.Severity == analysis.SeverityInfo {
+			continue
+		}
+		part := f.Severity + ":" + f.Kind
+		if f.Function != "" {
+			part += ":" + f.Function
+		}
+		parts = append(parts, part)
+	}
+	return joinList(parts)
+}
+
+func (c *CSVWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Summary is the run-level document written by the summary format
+type Summary struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Input       string                     `json:"input"`
+	Statistics  pipeline.Statistics        `json:"statistics"`
+	APIUsage    map[string]transport.Stats `json:"api_usage,omitempty"`
+}
+
+// WriteSummaryJSON writes the summary as indented JSON
+func WriteSummaryJSON(w io.Writer, summary Summary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(summary)
+}
+
+func joinList(values []string) string {
+	return strings.Join(values, " | ")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
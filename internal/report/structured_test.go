@@ -0,0 +1,155 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/analysis"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/contract"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/fraud"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/holders"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/report"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/scoring"
+)
+
+var listing = pipeline.ListingThresholds{Featured: 70, Visible: 50}
+
+// fullResult fills every optional section, with reasons too long for the
+// text report
+func fullResult() pipeline.TokenResult {
+	long := strings.Repeat("very long reason ", 20)
+	return pipeline.TokenResult{
+		Chain: "bsc", Symbol: "CAKE", Name: "PancakeSwap Token", Address: "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82",
+		Status: pipeline.StatusPassed, Stage: pipeline.StageScoring, Score: 72.5,
+		FailureReasons: []string{long + "1", long + "2"},
+		RiskFactors:    []string{"owner_not_renounced", "liquidity_drain"},
+		Warnings:       []string{"Upgradeable proxy: implementation 0xa1 (Impl), upgrader 0xa3"},
+		Liquidity:      5e6, Volume: 1e6, Age: 400, Concentration: 35, Verified: true,
+		HolderSource: "onchain",
+		HolderDistribution: &holders.Distribution{HolderCount: 6, Gini: 0.4,
+			Classes: []holders.ClassShare{{Label: "wallet", Percent: 35, Holders: 6}}},
+		ContractAnalysis: &analysis.Report{Findings: []analysis.Finding{
+			{Kind: analysis.KindPrivilegedMint, Severity: "high", Function: "mint"},
+		}},
+		Proxy: &contract.ProxyInfo{Implementation: "0xa1", Upgrader: "0xa3"},
+		TokenScore: &scoring.TokenScore{LiquidityScore: 90, VolumeScore: 80, HolderScore: 85, FragmentationScore: 100,
+			ContractScore: 70, FraudScore: 90, SlippageScore: 100, SlippageImpactPct: 0.2, SlippageSimulated: true},
+		Fraud: &fraud.FraudResult{IsSafe: true, RiskScore: 10, HasOwner: true,
+			HoneypotData: &fraud.HoneypotData{TotalHolders: 100, Flags: []string{"a", "b"}},
+			GoPlusData:   &fraud.GoPlusData{HolderCount: 100, Top10Concentration: 35}},
+		CheckedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		result pipeline.TokenResult
+		want   map[string]string // Column values to check
+	}{
+		{"full", fullResult(), map[string]string{
+			"fraud_score":     "90",
+			"failure_reasons": strings.Join(fullResult().FailureReasons, " | "),
+			"holder_count":    "6",
+			"checked_at":      "2026-01-01T12:00:00Z",
+		}},
+		{"errored", pipeline.TokenResult{Symbol: "ERR", Status: pipeline.StatusError, ErrorReason: "timeout"},
+			map[string]string{"fraud_score": "", "error_reason": "timeout"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := report.NewCSVWriter(&buf, listing)
+			if err := w.WriteResult(tt.result); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 2 {
+				t.Fatalf("got %d records, want a header and one row", len(records))
+			}
+			header, row := records[0], records[1]
+			if len(row) != len(header) {
+				t.Fatalf("row has %d columns, header %d", len(row), len(header))
+			}
+			for column, want := range tt.want {
+				i := slices.Index(header, column)
+				if i < 0 {
+					t.Errorf("no %s column", column)
+				} else if row[i] != want {
+					t.Errorf("%s = %q, want %q", column, row[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONLWriterRoundTrips(t *testing.T) {
+	var buf bytes.Buffer
+	want := fullResult()
+	if err := report.NewJSONLWriter(&buf, listing).WriteResult(want); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Fatalf("wrote %d lines, want 1", lines)
+	}
+
+	var got struct {
+		ListingStatus string `json:"listing_status"`
+		pipeline.TokenResult
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ListingStatus != want.ListingStatus(listing) {
+		t.Errorf("listing_status = %q, want %q", got.ListingStatus, want.ListingStatus(listing))
+	}
+	if !slices.Equal(got.FailureReasons, want.FailureReasons) || !slices.Equal(got.RiskFactors, want.RiskFactors) ||
+		!slices.Equal(got.Warnings, want.Warnings) {
+		t.Errorf("lists changed in the round trip: %q, %q, %q", got.FailureReasons, got.RiskFactors, got.Warnings)
+	}
+	if got.TokenScore == nil || got.TokenScore.FraudScore != want.TokenScore.FraudScore || !got.CheckedAt.Equal(want.CheckedAt) {
+		t.Errorf("score %+v at %s", got.TokenScore, got.CheckedAt)
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string // nil when an error is expected
+	}{
+		{"text", []string{report.FormatText}},
+		{"jsonl, CSV", []string{report.FormatCSV, report.FormatJSONL}},
+		{"csv,csv,,CSV", []string{report.FormatCSV}},
+		{"", []string{}},
+		{"jsonl,xml", nil},
+		{"yaml", nil},
+	}
+
+	for _, tt := range tests {
+		formats, err := report.ParseFormats(tt.value)
+		if tt.want == nil {
+			if err == nil || !strings.Contains(err.Error(), "unknown output format") {
+				t.Errorf("ParseFormats(%q) = %v, %v, want an unknown format error", tt.value, formats, err)
+			}
+			continue
+		}
+		if got := slices.Sorted(maps.Keys(formats)); err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseFormats(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
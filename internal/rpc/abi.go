@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Hand-rolled ABI encoding for the handful of static calls the screener
+// makes. Every value is a 32-byte word written as 64 hex characters.
+
+// WordSize is the ABI word size in hex characters
+const WordSize = 64
+
+// EncodeAddress left-pads an address to a word
+func EncodeAddress(address string) string {
+	return leftPad(strings.ToLower(strings.TrimPrefix(address, "0x")))
+}
+
+// EncodeUint left-pads an unsigned integer to a word
+func EncodeUint(v *big.Int) string {
+	return leftPad(v.Text(16))
+}
+
+// EncodeUint64 left-pads a small unsigned integer to a word
+func EncodeUint64(v uint64) string {
+	return EncodeUint(new(big.Int).SetUint64(v))
+}
+
+func leftPad(hex string) string {
+	if len(hex) >= WordSize {
+		return hex[len(hex)-WordSize:]
+	}
+	return strings.Repeat("0", WordSize-len(hex)) + hex
+}
+
+// Words splits return data into 32-byte words
+func Words(data string) ([]string, error) {
+	data = strings.TrimPrefix(data, "0x")
+	if len(data)%WordSize != 0 {
+		return nil, fmt.Errorf("return data is %d hex chars, not a whole number of words", len(data))
+	}
+	words := make([]string, len(data)/WordSize)
+	for i := range words {
+		words[i] = data[i*WordSize : (i+1)*WordSize]
+	}
+	return words, nil
+}
+
+// DecodeUint parses a word as an unsigned integer
+func DecodeUint(word string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(word, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid word %q", word)
+	}
+	return v, nil
+}
+
+// DecodeAddress returns the low 20 bytes of a word as a lowercase address
+func DecodeAddress(word string) string {
+	word = strings.ToLower(strings.TrimPrefix(word, "0x"))
+	if len(word) < 40 {
+		return ""
+	}
+	return "0x" + word[len(word)-40:]
+}
+
+// DecodeUintArray decodes return data holding a single dynamic uint256[]
+func DecodeUintArray(data string) ([]*big.Int, error) {
+	words, err := Words(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) < 2 {
+		return nil, fmt.Errorf("return data too short for uint256[]")
+	}
+	offset, err := decodeIndex(words[0], len(words)*32-1)
+	if err != nil {
+		return nil, fmt.Errorf("array offset: %w", err)
+	}
+	start := offset / 32
+	n, err := decodeIndex(words[start], len(words)-start-1)
+	if err != nil {
+		return nil, fmt.Errorf("array length: %w", err)
+	}
+
+	values := make([]*big.Int, n)
+	for i := range values {
+		if values[i], err = DecodeUint(words[start+1+i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// DecodeString decodes return data holding a single string. Some older
+// tokens return a bytes32 instead, which is decoded without its zero padding.
+func DecodeString(data string) (string, error) {
+	words, err := Words(data)
+	if err != nil {
+		return "", err
+	}
+	if len(words) == 1 {
+		b, err := hex.DecodeString(words[0])
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\x00"), nil
+	}
+	if len(words) < 2 {
+		return "", fmt.Errorf("return data too short for string")
+	}
+	offset, err := decodeIndex(words[0], len(words)*32-1)
+	if err != nil {
+		return "", fmt.Errorf("string offset: %w", err)
+	}
+	start := offset / 32
+	n, err := decodeIndex(words[start], (len(words)-start-1)*32)
+	if err != nil {
+		return "", fmt.Errorf("string length: %w", err)
+	}
+	raw := strings.Join(words[start+1:], "")
+	b, err := hex.DecodeString(raw[:n*2])
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// decodeIndex parses an offset or length word. Values are checked against
+// limit before the conversion to int, so hostile return data cannot wrap
+// them negative.
+func decodeIndex(word string, limit int) (int, error) {
+	v, err := DecodeUint(word)
+	if err != nil {
+		return 0, err
+	}
+	if v.Sign() < 0 || !v.IsInt64() || v.Int64() > int64(limit) {
+		return 0, fmt.Errorf("%s out of range", v)
+	}
+	return int(v.Int64()), nil
+}
@@ -0,0 +1,102 @@
+package rpc_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+)
+
+// huge is a word far larger than any return data
+var huge = strings.Repeat("f", rpc.WordSize)
+
+// word left-pads hex digits to an ABI word
+func word(digits string) string {
+	return strings.Repeat("0", rpc.WordSize-len(digits)) + digits
+}
+
+// text right-pads s to whole words
+func text(s string) string {
+	h := hex.EncodeToString([]byte(s))
+	return h + strings.Repeat("0", (rpc.WordSize-len(h)%rpc.WordSize)%rpc.WordSize)
+}
+
+func TestDecodeString(t *testing.T) {
+	if got, err := rpc.DecodeString("0x" + word("20") + word("4") + text("CAKE")); err != nil || got != "CAKE" {
+		t.Errorf("DecodeString = %q, %v", got, err)
+	}
+	// Older tokens return a bytes32 symbol
+	if got, err := rpc.DecodeString("0x" + text("MKR")); err != nil || got != "MKR" {
+		t.Errorf("bytes32 symbol = %q, %v", got, err)
+	}
+
+	hostile := map[string]string{
+		"offset past the data":  word("60") + word("4") + text("CAKE"),
+		"offset wraps negative": huge + word("4") + text("CAKE"),
+		"offset above int64":    "1" + strings.Repeat("0", rpc.WordSize-1) + word("4") + text("CAKE"),
+		"negative offset":       "-" + word("20")[1:] + word("4") + text("CAKE"),
+		"length wraps negative": word("20") + huge + text("CAKE"),
+		"length past the data":  word("20") + word("21") + text("CAKE"),
+		"length of max int64":   word("20") + word("7fffffffffffffff") + text("CAKE"),
+	}
+	for name, data := range hostile {
+		if got, err := rpc.DecodeString("0x" + data); err == nil {
+			t.Errorf("%s: decoded %q, want an error", name, got)
+		}
+	}
+}
+
+func TestDecodeUintArray(t *testing.T) {
+	// getAmountsOut for a two-hop path
+	amounts, err := rpc.DecodeUintArray("0x" + word("20") + word("3") + word("de0b6b3a7640000") + word("2a") + word("0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"1000000000000000000", "42", "0"}
+	if len(amounts) != len(want) {
+		t.Fatalf("got %d amounts, want %d", len(amounts), len(want))
+	}
+	for i, w := range want {
+		if amounts[i].String() != w {
+			t.Errorf("amounts[%d] = %s, want %s", i, amounts[i], w)
+		}
+	}
+	if empty, err := rpc.DecodeUintArray("0x" + word("20") + word("0")); err != nil || len(empty) != 0 {
+		t.Errorf("empty array = %v, %v", empty, err)
+	}
+
+	hostile := map[string]string{
+		"too short":             word("20"),
+		"offset past the data":  word("40") + word("1"),
+		"offset wraps negative": huge + word("1") + word("1"),
+		"length wraps negative": word("20") + huge + word("1"),
+		"length past the data":  word("20") + word("2") + word("1"),
+		"length of max int64":   word("20") + word("7fffffffffffffff") + word("1"),
+		"length of max uint64":  word("20") + word("ffffffffffffffff") + word("1"),
+		"not a whole word":      word("20") + word("1") + "01",
+		"not hex":               word("20") + word("1") + strings.Repeat("z", rpc.WordSize),
+	}
+	for name, data := range hostile {
+		if got, err := rpc.DecodeUintArray("0x" + data); err == nil {
+			t.Errorf("%s: decoded %v, want an error", name, got)
+		}
+	}
+}
+
+func TestEncodeDecodeWords(t *testing.T) {
+	const cake = "0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82"
+	words, err := rpc.Words("0x" + rpc.EncodeAddress(cake) + rpc.EncodeUint64(18))
+	if err != nil || len(words) != 2 {
+		t.Fatalf("Words = %v, %v", words, err)
+	}
+	if got := rpc.DecodeAddress(words[0]); got != strings.ToLower(cake) {
+		t.Errorf("DecodeAddress = %s", got)
+	}
+	if v, err := rpc.DecodeUint(words[1]); err != nil || v.Int64() != 18 {
+		t.Errorf("DecodeUint = %v, %v", v, err)
+	}
+	if got := rpc.DecodeAddress("0x1234"); got != "" {
+		t.Errorf("DecodeAddress of a short word = %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+// Package rpc is a minimal Ethereum JSON-RPC client for the read-only calls
+// the screener makes against a node, a public endpoint or a local fork
+// (anvil, hardhat)
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+// Client sends JSON-RPC requests to a single endpoint
+type Client struct {
+	url        string
+	httpClient *transport.Client
+	nextID     atomic.Int64
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+// IsRevert reports whether err is an eth_call that reverted, as opposed to a
+// transport or node failure. Geth-style nodes use code 3 (with revert data)
+// or -32000 with an "execution reverted" message.
+func IsRevert(err error) bool {
+	rpcErr, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	return rpcErr.Code == 3 || strings.Contains(strings.ToLower(rpcErr.Message), "revert")
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// NewClient creates a client for the endpoint at url. A nil httpClient uses the shared default transport.
+func NewClient(url string, httpClient *transport.Client) *Client {
+	if httpClient == nil {
+		httpClient = transport.Default.For(transport.ProviderRPC)
+	}
+	return &Client{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+// URL returns the endpoint the client talks to
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Call invokes method with params and decodes the result into result
+func (c *Client) Call(ctx context.Context, result any, method string, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(request{
+		JSONRPC: "2.0",
+		ID:      c.nextID.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", method, resp.StatusCode)
+	}
+
+	var rpcResp response
+	if err := json.Unmarshal(data, &rpcResp); err != nil {
+		return fmt.Errorf("%s: decoding response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// EthCall performs a read-only call against the latest block and returns the raw return data
+func (c *Client) EthCall(ctx context.Context, to, data string) (string, error) {
+	var result string
+	err := c.Call(ctx, &result, "eth_call", map[string]string{"to": to, "data": data}, "latest")
+	return result, err
+}
+
+// BlockNumber returns the latest block number
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var result string
+	if err := c.Call(ctx, &result, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return ParseQuantity(result)
+}
+
+// Log is an event log returned by eth_getLogs
+type Log struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     string   `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+	LogIndex        string   `json:"logIndex"`
+	Removed         bool     `json:"removed"`
+}
+
+// LogFilter selects logs for eth_getLogs. Topics are matched by position;
+// an empty string matches any value.
+type LogFilter struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Addresses []string
+	Topics    []string
+}
+
+// GetLogs returns the logs matching filter. Nodes cap the block range (and
+// result size) of a single request, so callers should page through ranges.
+func (c *Client) GetLogs(ctx context.Context, filter LogFilter) ([]Log, error) {
+	params := map[string]any{
+		"fromBlock": Quantity(filter.FromBlock),
+		"toBlock":   Quantity(filter.ToBlock),
+	}
+	if len(filter.Addresses) > 0 {
+		params["address"] = filter.Addresses
+	}
+	if len(filter.Topics) > 0 {
+		topics := make([]any, len(filter.Topics))
+		for i, t := range filter.Topics {
+			if t != "" {
+				topics[i] = t
+			}
+		}
+		params["topics"] = topics
+	}
+
+	var logs []Log
+	err := c.Call(ctx, &logs, "eth_getLogs", params)
+	return logs, err
+}
+
+// Quantity encodes n as a hex JSON-RPC quantity
+func Quantity(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+// ParseQuantity decodes a hex-encoded JSON-RPC quantity such as "0x1b4"
+func ParseQuantity(s string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+}
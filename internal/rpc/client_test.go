@@ -0,0 +1,53 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+func newClient(t *testing.T) *rpc.Client {
+	t.Helper()
+	srv := mockapi.New()
+	t.Cleanup(srv.Close)
+	return rpc.NewClient(srv.RPCURL(), transport.NewClient(transport.ProviderRPC, transport.Limits{Timeout: 5 * time.Second}))
+}
+
+func TestClientCalls(t *testing.T) {
+	c := newClient(t)
+
+	head, err := c.BlockNumber(context.Background())
+	if err != nil || head != mockapi.LatestBlock {
+		t.Errorf("BlockNumber = %d, %v, want %d", head, err, mockapi.LatestBlock)
+	}
+
+	// symbol() on a fixture token
+	ret, err := c.EthCall(context.Background(), "0x00000000000000000000000000000000000000f1", "0x95d89b41")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if symbol, err := rpc.DecodeString(ret); err != nil || symbol != "NEWT" {
+		t.Errorf("symbol = %q, %v", symbol, err)
+	}
+
+	// Ranges wider than the node allows come back as JSON-RPC errors
+	_, err = c.GetLogs(context.Background(), rpc.LogFilter{FromBlock: mockapi.LatestBlock - 2*mockapi.MaxLogRange, ToBlock: mockapi.LatestBlock})
+	var rpcErr *rpc.Error
+	if !errors.As(err, &rpcErr) || rpc.IsRevert(err) {
+		t.Errorf("GetLogs over a wide range = %v, want a non-revert RPC error", err)
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	if q := rpc.Quantity(436); q != "0x1b4" {
+		t.Errorf("Quantity = %s", q)
+	}
+	if n, err := rpc.ParseQuantity("0x1b4"); err != nil || n != 436 {
+		t.Errorf("ParseQuantity = %d, %v", n, err)
+	}
+}
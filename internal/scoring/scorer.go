@@ -1,155 +1,341 @@
+// Package scoring turns a token's market metrics into hard filter verdicts
+// and a weighted 0-100 composite score. A Scorer holds its own weights and
+// thresholds, so scorers with different settings can run side by side.
 package scoring
 
 import (
 	"fmt"
+	"strings"
+)
+
+// Hard filter names, shared with the evaluation rule names
+const (
+	FilterVerified  = "contract_not_verified"
+	FilterLiquidity = "min_liquidity"
+	FilterVolume    = "min_volume"
+	FilterTop10     = "max_top10_holders"
+	FilterPairAge   = "min_pair_age"
+)
 
-	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+// Composite score components
+const (
+	ComponentLiquidity     = "liquidity"
+	ComponentVolume        = "volume"
+	ComponentHolder        = "holder"
+	ComponentFragmentation = "fragmentation"
+	ComponentSlippage      = "slippage"
+	ComponentContract      = "contract"   // Source analysis red flags
+	ComponentFraudRisk     = "fraud_risk" // Inverse of the fraud rules' risk score
 )
 
+// Weights weight the sub-scores in the composite and should sum to 1.
+// Tokens without a slippage simulation or an analyzable source have the
+// other weights scaled back up to 1.
+type Weights struct {
+	Liquidity     float64 `json:"liquidity"`
+	Volume        float64 `json:"volume"`
+	Holder        float64 `json:"holder"`
+	Fragmentation float64 `json:"fragmentation"`
+	Slippage      float64 `json:"slippage"`
+	Contract      float64 `json:"contract"`
+	FraudRisk     float64 `json:"fraud_risk"`
+}
+
+// Thresholds are the hard filters; failing any one rejects the token
+type Thresholds struct {
+	MinLiquidityUSD             float64 `json:"min_liquidity_usd"`
+	MinVolume24h                float64 `json:"min_volume_24h"`
+	MaxTop10HolderConcentration float64 `json:"max_top10_holder_concentration"`
+	MinPairAgeDays              float64 `json:"min_pair_age_days"`
+}
+
+// Input is what a token is scored on
+type Input struct {
+	Verified           bool
+	LiquidityUSD       float64 // Aggregated over the counted pairs
+	Volume24hUSD       float64
+	Top10HolderPercent float64
+	HoldersUnknown     bool // No holder list was available; the holder sub-score is 0
+	FragmentationSafe  bool
+	FragmentationBasis string  // Why FragmentationSafe was decided; empty for a generic basis
+	PairAgeDays        float64 // Age of the largest pair
+	SlippageImpactPct  float64 // Simulated price impact of the scored trade size
+	SlippageSimulated  bool
+	ContractScore      float64  // 0-100 from the source analyzer
+	ContractAnalyzed   bool     // False when the source could not be analyzed
+	ContractFlags      []string // Red flag kinds behind ContractScore
+	FraudRiskScore     int      // 0-100 from the fraud rules; higher is riskier
+	FraudRiskFactors   []string // Warn rules behind FraudRiskScore
+}
+
+// FilterResult is one hard filter's verdict
+type FilterResult struct {
+	Name    string  `json:"name"`
+	Passed  bool    `json:"passed"`
+	Value   float64 `json:"value"`
+	Limit   float64 `json:"limit"`
+	Message string  `json:"message,omitempty"` // Failure reason, empty when passed
+}
+
+// Component is one sub-score and how it entered the composite
+type Component struct {
+	Name         string  `json:"name"`
+	Input        float64 `json:"input"`        // Metric the sub-score was derived from
+	Score        float64 `json:"score"`        // 0-100
+	Weight       float64 `json:"weight"`       // Effective weight after rescaling
+	Contribution float64 `json:"contribution"` // Score * Weight
+	Basis        string  `json:"basis"`        // The band of the curve that applied
+}
+
 type TokenScore struct {
-	LiquidityScore     float64
-	VolumeScore        float64
-	HolderScore        float64
-	FragmentationScore float64
-	CompositeScore     float64
-	IsSafe             bool
-	FailureReasons     []string
-}
-
-func Scorer(
-	isContractVerified bool,
-	aggregatedLiquidityUSD float64,
-	aggregatedVolume24hUSD float64,
-	top10HoldersPercentage float64,
-	isFragmentationSafe bool,
-	largestSingleLiquidityPoolAgeDays float64,
-) (TokenScore, bool) {
-	cfg := config.Load()
+	LiquidityScore     float64        `json:"liquidity_score"`
+	VolumeScore        float64        `json:"volume_score"`
+	HolderScore        float64        `json:"holder_score"`
+	FragmentationScore float64        `json:"fragmentation_score"`
+	ContractScore      float64        `json:"contract_score"` // Contract risk signals from source analysis
+	FraudScore         float64        `json:"fraud_score"`    // 100 minus the fraud rules' risk score
+	SlippageScore      float64        `json:"slippage_score"`
+	SlippageImpactPct  float64        `json:"slippage_impact_pct"` // Simulated price impact of the scored trade size
+	SlippageSimulated  bool           `json:"slippage_simulated"`
+	CompositeScore     float64        `json:"composite_score"`
+	IsSafe             bool           `json:"is_safe"`
+	FailureReasons     []string       `json:"failure_reasons"`
+	Filters            []FilterResult `json:"filters"`              // Every hard filter, in evaluation order
+	Components         []Component    `json:"components,omitempty"` // Empty when a hard filter failed
+}
 
-	result := TokenScore{
-		IsSafe:         true,
-		FailureReasons: []string{},
+// FailedFilters returns the hard filters the token failed
+func (s *TokenScore) FailedFilters() []FilterResult {
+	var failed []FilterResult
+	for _, f := range s.Filters {
+		if !f.Passed {
+			failed = append(failed, f)
+		}
 	}
+	return failed
+}
 
-	// Hard Filters - Any failure = reject
-	if !isContractVerified {
-		result.IsSafe = false
-		result.FailureReasons = append(result.FailureReasons, "Contract not verified")
-	}
+// Scorer scores tokens with fixed weights and thresholds
+type Scorer struct {
+	weights    Weights
+	thresholds Thresholds
+}
 
-	if aggregatedLiquidityUSD < cfg.MinLiquidityUSD {
-		result.IsSafe = false
-		result.FailureReasons = append(result.FailureReasons,
-			fmt.Sprintf("Liquidity too low: $%.2f < $%.2f", aggregatedLiquidityUSD, cfg.MinLiquidityUSD))
-	}
+// NewScorer creates a scorer with the given weights and hard filter thresholds
+func NewScorer(weights Weights, thresholds Thresholds) *Scorer {
+	return &Scorer{weights: weights, thresholds: thresholds}
+}
 
-	if aggregatedVolume24hUSD < cfg.MinVolume24h {
-		result.IsSafe = false
-		result.FailureReasons = append(result.FailureReasons,
-			fmt.Sprintf("Volume too low: $%.2f < $%.2f", aggregatedVolume24hUSD, cfg.MinVolume24h))
+func (s *Scorer) Weights() Weights       { return s.weights }
+func (s *Scorer) Thresholds() Thresholds { return s.thresholds }
+
+// Score applies the hard filters and, if they all pass, computes the
+// weighted composite with a breakdown of every sub-score
+func (s *Scorer) Score(in Input) TokenScore {
+	t := s.thresholds
+	result := TokenScore{
+		IsSafe:            true,
+		FailureReasons:    []string{},
+		SlippageSimulated: in.SlippageSimulated,
 	}
 
-	if top10HoldersPercentage > cfg.MaxTop10HolderConcentration {
+	verified := 0.0
+	if in.Verified {
+		verified = 1
+	}
+	filters := []FilterResult{
+		{Name: FilterVerified, Passed: in.Verified, Value: verified, Limit: 1,
+			Message: "Contract not verified"},
+		{Name: FilterLiquidity, Passed: in.LiquidityUSD >= t.MinLiquidityUSD, Value: in.LiquidityUSD, Limit: t.MinLiquidityUSD,
+			Message: fmt.Sprintf("Liquidity too low: $%.2f < $%.2f", in.LiquidityUSD, t.MinLiquidityUSD)},
+		{Name: FilterVolume, Passed: in.Volume24hUSD >= t.MinVolume24h, Value: in.Volume24hUSD, Limit: t.MinVolume24h,
+			Message: fmt.Sprintf("Volume too low: $%.2f < $%.2f", in.Volume24hUSD, t.MinVolume24h)},
+		{Name: FilterTop10, Passed: in.Top10HolderPercent <= t.MaxTop10HolderConcentration, Value: in.Top10HolderPercent, Limit: t.MaxTop10HolderConcentration,
+			Message: fmt.Sprintf("Holder concentration too high: %.2f%% > %.2f%%", in.Top10HolderPercent, t.MaxTop10HolderConcentration)},
+		{Name: FilterPairAge, Passed: in.PairAgeDays >= t.MinPairAgeDays, Value: in.PairAgeDays, Limit: t.MinPairAgeDays,
+			Message: fmt.Sprintf("Pair too new: %.1f days < %g days", in.PairAgeDays, t.MinPairAgeDays)},
+	}
+	for i := range filters {
+		if filters[i].Passed {
+			filters[i].Message = ""
+			continue
+		}
 		result.IsSafe = false
-		result.FailureReasons = append(result.FailureReasons,
-			fmt.Sprintf("Holder concentration too high: %.2f%% > %.2f%%", top10HoldersPercentage, cfg.MaxTop10HolderConcentration))
+		result.FailureReasons = append(result.FailureReasons, filters[i].Message)
 	}
+	result.Filters = filters
 
-	if largestSingleLiquidityPoolAgeDays < 7.0 {
-		result.IsSafe = false
-		result.FailureReasons = append(result.FailureReasons,
-			fmt.Sprintf("Pair too new: %.1f days < 7 days", largestSingleLiquidityPoolAgeDays))
+	if in.SlippageSimulated {
+		result.SlippageImpactPct = in.SlippageImpactPct
+		result.SlippageScore, _ = slippageScore(in.SlippageImpactPct)
 	}
 
 	// If any hard filter failed, return 0 score
 	if !result.IsSafe {
-		result.CompositeScore = 0
-		return result, false
+		return result
 	}
 
-	// Calculate individual scores (0-100)
-	result.LiquidityScore = calculateLiquidityScore(aggregatedLiquidityUSD)
-	result.VolumeScore = calculateVolumeScore(aggregatedVolume24hUSD, aggregatedLiquidityUSD)
-	result.HolderScore = calculateHolderScore(top10HoldersPercentage)
-	result.FragmentationScore = calculateFragmentationScore(isFragmentationSafe)
+	result.ContractScore = in.ContractScore
+	result.FraudScore = float64(100 - in.FraudRiskScore)
 
-	// Weighted composite score
-	result.CompositeScore = (result.LiquidityScore*cfg.LiquidityWeight +
-		result.VolumeScore*cfg.VolumeWeight +
-		result.HolderScore*cfg.HolderWeight +
-		result.FragmentationScore*cfg.FragmentationWeight)
+	fragmentation := 0.0
+	if in.FragmentationSafe {
+		fragmentation = 1
+	}
+	w := s.weights
+	components := []Component{
+		{Name: ComponentLiquidity, Input: in.LiquidityUSD, Weight: w.Liquidity},
+		{Name: ComponentVolume, Input: in.Volume24hUSD, Weight: w.Volume},
+		{Name: ComponentHolder, Input: in.Top10HolderPercent, Weight: w.Holder},
+		{Name: ComponentFragmentation, Input: fragmentation, Weight: w.Fragmentation},
+		{Name: ComponentSlippage, Input: in.SlippageImpactPct, Score: result.SlippageScore, Weight: w.Slippage},
+		{Name: ComponentContract, Input: in.ContractScore, Score: in.ContractScore, Weight: w.Contract,
+			Basis: flagsBasis("source analysis", in.ContractFlags)},
+		{Name: ComponentFraudRisk, Input: float64(in.FraudRiskScore), Score: result.FraudScore, Weight: w.FraudRisk,
+			Basis: flagsBasis(fmt.Sprintf("100 - fraud risk %d", in.FraudRiskScore), in.FraudRiskFactors)},
+	}
+	result.LiquidityScore, components[0].Basis = liquidityScore(in.LiquidityUSD)
+	result.VolumeScore, components[1].Basis = volumeScore(in.Volume24hUSD, in.LiquidityUSD)
+	result.HolderScore, components[2].Basis = holderScore(in.Top10HolderPercent)
+	if in.HoldersUnknown {
+		result.HolderScore, components[2].Basis = 0, "no holder data"
+	}
+	result.FragmentationScore, components[3].Basis = fragmentationScore(in.FragmentationSafe)
+	if in.FragmentationBasis != "" {
+		components[3].Basis = in.FragmentationBasis
+	}
+	_, components[4].Basis = slippageScore(in.SlippageImpactPct)
+	components[0].Score = result.LiquidityScore
+	components[1].Score = result.VolumeScore
+	components[2].Score = result.HolderScore
+	components[3].Score = result.FragmentationScore
 
-	return result, true
+	// Components without data (no simulation, no analyzable source) are left
+	// out, with the remaining weights scaled back up to the configured total
+	missing := map[string]bool{
+		ComponentSlippage: !in.SlippageSimulated,
+		ComponentContract: !in.ContractAnalyzed,
+	}
+	var total, available float64
+	for _, c := range components {
+		total += c.Weight
+		if !missing[c.Name] {
+			available += c.Weight
+		}
+	}
+	scale := 1.0
+	if available > 0 && available < total {
+		scale = total / available
+	}
+
+	kept := components[:0]
+	for _, c := range components {
+		if c.Weight <= 0 {
+			continue
+		}
+		if missing[c.Name] {
+			c.Weight = 0
+			c.Basis = "no data, other weights rescaled"
+		} else {
+			c.Weight *= scale
+		}
+		c.Contribution = c.Score * c.Weight
+		result.CompositeScore += c.Contribution
+		kept = append(kept, c)
+	}
+	result.Components = kept
+
+	return result
 }
 
-func calculateLiquidityScore(liquidityUSD float64) float64 {
+// flagsBasis describes a component derived from a list of flags
+func flagsBasis(source string, flags []string) string {
+	if len(flags) == 0 {
+		return source + ", no flags"
+	}
+	return source + ": " + strings.Join(flags, ", ")
+}
+
+func liquidityScore(liquidityUSD float64) (float64, string) {
 	if liquidityUSD >= 5_000_000 {
-		return 100
+		return 100, ">= $5M"
 	} else if liquidityUSD >= 1_000_000 {
-		return 80 + ((liquidityUSD-1_000_000)/4_000_000)*20 // 80-100
+		return 80 + ((liquidityUSD-1_000_000)/4_000_000)*20, "$1M-$5M: 80-100 linear"
 	} else if liquidityUSD >= 500_000 {
-		return 60 + ((liquidityUSD-500_000)/500_000)*20 // 60-80
+		return 60 + ((liquidityUSD-500_000)/500_000)*20, "$500K-$1M: 60-80 linear"
 	} else if liquidityUSD >= 100_000 {
-		return 30 + ((liquidityUSD-100_000)/400_000)*30 // 30-60
+		return 30 + ((liquidityUSD-100_000)/400_000)*30, "$100K-$500K: 30-60 linear"
 	} else {
-		return 0
+		return 0, "< $100K"
 	}
 }
 
-func calculateVolumeScore(volume24h, liquidityUSD float64) float64 {
+func volumeScore(volume24h, liquidityUSD float64) (float64, string) {
 	if liquidityUSD == 0 {
-		return 0
+		return 0, "no liquidity"
 	}
 
 	// For HIGH liquidity tokens, high turnover is GOOD
 	if liquidityUSD >= 5_000_000 {
 		// Major tokens: reward high activity
 		if volume24h >= 50_000_000 { // $50M+ daily
-			return 100
+			return 100, "major token, volume >= $50M"
 		} else if volume24h >= 10_000_000 { // $10M+
-			return 95
+			return 95, "major token, volume >= $10M"
 		} else if volume24h >= 1_000_000 { // $1M+
-			return 85
+			return 85, "major token, volume >= $1M"
 		} else {
 			turnover := volume24h / liquidityUSD
 			if turnover >= 0.10 { // 10%+
-				return 80
+				return 80, "major token, turnover >= 10%"
 			}
-			return 60 // Low activity on major token
+			return 60, "major token, turnover < 10%" // Low activity on major token
 		}
 	}
 
 	// For MEDIUM liquidity ($500K-$5M): traditional turnover analysis
 	turnover := volume24h / liquidityUSD
 	if turnover >= 0.05 && turnover <= 0.30 { // 5-30%
-		return 100
+		return 100, "turnover 5-30%"
 	} else if turnover > 0.30 && turnover <= 1.0 { // 30-100%
-		return 85
+		return 85, "turnover 30-100%"
 	} else if turnover > 1.0 { // >100% (but <$5M liq = risky)
-		return 60
+		return 60, "turnover > 100%"
 	} else if turnover >= 0.02 {
-		return 50 + (turnover-0.02)*1666
+		return 50 + (turnover-0.02)*1666, "turnover 2-5%: 50-100 linear"
 	} else {
-		return turnover * 2500
+		return turnover * 2500, "turnover < 2%: 0-50 linear"
 	}
 }
 
-func calculateHolderScore(top10Percentage float64) float64 {
+func holderScore(top10Percentage float64) (float64, string) {
 	// Lower concentration = better
 	if top10Percentage < 20 {
-		return 100
+		return 100, "top 10 < 20%"
 	} else if top10Percentage < 40 {
-		return 85
+		return 85, "top 10 20-40%"
 	} else if top10Percentage < 60 {
-		return 60
+		return 60, "top 10 40-60%"
 	} else {
-		return 30
+		return 30, "top 10 >= 60%"
 	}
 }
 
-func calculateFragmentationScore(isFragmentationSafe bool) float64 {
+func fragmentationScore(isFragmentationSafe bool) (float64, string) {
 	if isFragmentationSafe {
-		return 100
+		return 100, "liquidity concentrated in one pair"
+	}
+	return 40, "liquidity fragmented across pairs" // Penalty but not elimination
+}
+
+func slippageScore(impactPct float64) (float64, string) {
+	// Price impact of a $1K buy, per plan.txt's slippage curve
+	if impactPct < 1 {
+		return 100, "impact < 1%"
+	} else if impactPct < 3 {
+		return 70, "impact 1-3%"
+	} else if impactPct < 5 {
+		return 40, "impact 3-5%"
 	}
-	return 40 // Penalty but not elimination
+	return 0, "impact >= 5%"
 }
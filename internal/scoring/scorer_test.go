@@ -0,0 +1,153 @@
+package scoring_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/scoring"
+)
+
+var (
+	defaultWeights = scoring.Weights{Liquidity: 0.35, Volume: 0.30, Holder: 0.25, Fragmentation: 0.10}
+	defaultLimits  = scoring.Thresholds{MinLiquidityUSD: 100000, MinVolume24h: 10000, MaxTop10HolderConcentration: 90, MinPairAgeDays: 7}
+
+	healthy = scoring.Input{
+		Verified:           true,
+		LiquidityUSD:       2_000_000,
+		Volume24hUSD:       200_000,
+		Top10HolderPercent: 30,
+		FragmentationSafe:  true,
+		PairAgeDays:        100,
+	}
+)
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestScoreBreakdown(t *testing.T) {
+	s := scoring.NewScorer(defaultWeights, defaultLimits).Score(healthy)
+
+	if !s.IsSafe || len(s.FailedFilters()) != 0 || len(s.Filters) != 5 {
+		t.Fatalf("healthy token: safe %t, filters %+v", s.IsSafe, s.Filters)
+	}
+	want := []struct {
+		name   string
+		score  float64
+		weight float64
+	}{
+		{scoring.ComponentLiquidity, 85, 0.35},
+		{scoring.ComponentVolume, 100, 0.30},
+		{scoring.ComponentHolder, 85, 0.25},
+		{scoring.ComponentFragmentation, 100, 0.10},
+	}
+	if len(s.Components) != len(want) {
+		t.Fatalf("got %d components, want %d", len(s.Components), len(want))
+	}
+	var sum float64
+	for i, w := range want {
+		c := s.Components[i]
+		if c.Name != w.name || !approx(c.Score, w.score) || !approx(c.Weight, w.weight) || c.Basis == "" {
+			t.Errorf("component %d = %+v, want %s %g x %g", i, c, w.name, w.score, w.weight)
+		}
+		sum += c.Contribution
+	}
+	if !approx(s.CompositeScore, 91) || !approx(sum, s.CompositeScore) {
+		t.Errorf("composite %g (contributions %g), want 91", s.CompositeScore, sum)
+	}
+	if c := s.Components[0]; c.Basis != "$1M-$5M: 80-100 linear" {
+		t.Errorf("liquidity basis %q", c.Basis)
+	}
+
+	// The market's reason for the fragmentation verdict replaces the generic one
+	in := healthy
+	in.FragmentationBasis = "total liquidity $2000001 > $2M"
+	if c := scoring.NewScorer(defaultWeights, defaultLimits).Score(in).Components[3]; c.Basis != in.FragmentationBasis {
+		t.Errorf("fragmentation basis %q, want %q", c.Basis, in.FragmentationBasis)
+	}
+}
+
+func TestScoreReportsEveryFailedFilter(t *testing.T) {
+	in := healthy
+	in.LiquidityUSD = 50_000
+	in.PairAgeDays = 2
+
+	s := scoring.NewScorer(defaultWeights, defaultLimits).Score(in)
+	if s.IsSafe || s.CompositeScore != 0 || len(s.Components) != 0 {
+		t.Fatalf("failed token: safe %t, composite %g, %d components", s.IsSafe, s.CompositeScore, len(s.Components))
+	}
+	failed := s.FailedFilters()
+	if len(failed) != 2 || failed[0].Name != scoring.FilterLiquidity || failed[1].Name != scoring.FilterPairAge {
+		t.Fatalf("failed filters = %+v", failed)
+	}
+	if f := failed[0]; f.Value != 50_000 || f.Limit != 100_000 || f.Message != "Liquidity too low: $50000.00 < $100000.00" {
+		t.Errorf("liquidity filter = %+v", f)
+	}
+	if len(s.FailureReasons) != 2 || s.FailureReasons[1] != "Pair too new: 2.0 days < 7 days" {
+		t.Errorf("failure reasons = %q", s.FailureReasons)
+	}
+}
+
+func TestScoreSlippage(t *testing.T) {
+	weights := scoring.Weights{Liquidity: 0.30, Volume: 0.25, Holder: 0.20, Fragmentation: 0.10, Slippage: 0.15}
+	scorer := scoring.NewScorer(weights, defaultLimits)
+
+	// Without a simulation the other weights are scaled back up to 1
+	s := scorer.Score(healthy)
+	want := (85*0.30 + 100*0.25 + 85*0.20 + 100*0.10) / 0.85
+	if !approx(s.CompositeScore, want) {
+		t.Errorf("unsimulated composite %g, want %g", s.CompositeScore, want)
+	}
+	if c := s.Components[4]; c.Name != scoring.ComponentSlippage || c.Weight != 0 || c.Contribution != 0 {
+		t.Errorf("unsimulated slippage component = %+v", c)
+	}
+
+	in := healthy
+	in.SlippageImpactPct, in.SlippageSimulated = 2, true
+	s = scorer.Score(in)
+	want = 85*0.30 + 100*0.25 + 85*0.20 + 100*0.10 + 70*0.15
+	if !approx(s.CompositeScore, want) || s.SlippageScore != 70 || s.SlippageImpactPct != 2 {
+		t.Errorf("simulated composite %g (slippage %g), want %g", s.CompositeScore, s.SlippageScore, want)
+	}
+}
+
+func TestScorersAreIndependent(t *testing.T) {
+	strict := scoring.NewScorer(defaultWeights, scoring.Thresholds{MinLiquidityUSD: 5_000_000})
+	lenient := scoring.NewScorer(scoring.Weights{Liquidity: 1}, scoring.Thresholds{MaxTop10HolderConcentration: 100})
+
+	if s := strict.Score(healthy); s.IsSafe {
+		t.Error("strict scorer passed $2M liquidity")
+	}
+	if s := lenient.Score(healthy); !s.IsSafe || !approx(s.CompositeScore, 85) {
+		t.Errorf("lenient scorer: safe %t, composite %g, want 85", s.IsSafe, s.CompositeScore)
+	}
+}
+
+func TestScoreContractAndFraudRisk(t *testing.T) {
+	weights := scoring.Weights{Liquidity: 0.30, Volume: 0.25, Holder: 0.20, Fragmentation: 0.10, Contract: 0.10, FraudRisk: 0.05}
+	scorer := scoring.NewScorer(weights, defaultLimits)
+	base := 85*0.30 + 100*0.25 + 85*0.20 + 100*0.10
+
+	clean := healthy
+	clean.ContractScore, clean.ContractAnalyzed = 100, true
+	if s := scorer.Score(clean); !approx(s.CompositeScore, base+10+5) {
+		t.Errorf("clean composite %g, want %g", s.CompositeScore, base+15)
+	}
+
+	// Proxy, owner and high tax flags cost points instead of being ignored
+	flagged := clean
+	flagged.ContractScore, flagged.ContractFlags = 50, []string{"privileged_mint"}
+	flagged.FraudRiskScore, flagged.FraudRiskFactors = 40, []string{"proxy_contract", "owner_not_renounced", "high_tax_12.0%"}
+	s := scorer.Score(flagged)
+	if !approx(s.CompositeScore, base+5+3) || s.FraudScore != 60 || s.ContractScore != 50 {
+		t.Errorf("flagged composite %g (fraud %g, contract %g), want %g", s.CompositeScore, s.FraudScore, s.ContractScore, base+8)
+	}
+	if c := s.Components[5]; c.Name != scoring.ComponentFraudRisk || c.Basis != "100 - fraud risk 40: proxy_contract, owner_not_renounced, high_tax_12.0%" {
+		t.Errorf("fraud component = %+v", c)
+	}
+
+	// Without an analyzable source the other weights are scaled up to 1
+	unanalyzed := healthy
+	s = scorer.Score(unanalyzed)
+	if want := (base + 5) / 0.9; !approx(s.CompositeScore, want) {
+		t.Errorf("unanalyzed composite %g, want %g", s.CompositeScore, want)
+	}
+}
@@ -0,0 +1,284 @@
+// Package slippage simulates buys of a token through the chain's V2 router
+// and V3 quoter over JSON-RPC and measures the price impact of each trade size
+package slippage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+)
+
+// DefaultTradeSizesUSD are the buy sizes simulated for every token (plan.txt's slippage curve)
+var DefaultTradeSizesUSD = []float64{1_000, 10_000}
+
+// ScoredTradeUSD is the trade size whose impact feeds the slippage score
+const ScoredTradeUSD = 1_000
+
+// referenceTradeUSD is small enough to execute at the marginal price of any
+// pool worth screening. Impact is measured against its effective price, so
+// pool fees and token transfer taxes cancel out.
+const referenceTradeUSD = 10
+
+// Function selectors
+const (
+	selectorGetAmountsOut         = "0xd06ca61f" // getAmountsOut(uint256,address[])
+	selectorQuoteExactInputSingle = "0xc6a5026a" // quoteExactInputSingle((address,address,uint256,uint24,uint160))
+)
+
+// Trade is the best simulated buy for one size
+type Trade struct {
+	SizeUSD        float64 `json:"size_usd"`
+	Route          string  `json:"route"`      // e.g. "v2 USDT>WBNB>TOKEN" or "v3 USDT>TOKEN 0.25%"
+	AmountOut      string  `json:"amount_out"` // Token base units
+	PriceImpactPct float64 `json:"price_impact_pct"`
+}
+
+// Result is the simulated slippage curve of a token
+type Result struct {
+	Trades []Trade `json:"trades"`
+}
+
+// Impact returns the price impact in percent for sizeUSD, if it was simulated
+func (r *Result) Impact(sizeUSD float64) (float64, bool) {
+	for _, t := range r.Trades {
+		if t.SizeUSD == sizeUSD {
+			return t.PriceImpactPct, true
+		}
+	}
+	return 0, false
+}
+
+// Simulator quotes buys on one chain
+type Simulator struct {
+	chain      chain.Chain
+	client     *rpc.Client
+	tradeSizes []float64
+}
+
+// NewSimulator creates a simulator for ch that quotes through client
+func NewSimulator(ch chain.Chain, client *rpc.Client) *Simulator {
+	return &Simulator{
+		chain:      ch,
+		client:     client,
+		tradeSizes: DefaultTradeSizesUSD,
+	}
+}
+
+// WithTradeSizes overrides the simulated trade sizes in USD
+func (s *Simulator) WithTradeSizes(sizes []float64) *Simulator {
+	if len(sizes) > 0 {
+		s.tradeSizes = sizes
+	}
+	return s
+}
+
+// route is one way of buying the token with USD worth of an input asset
+type route struct {
+	name  string
+	input chain.Token
+	quote func(ctx context.Context, amountIn *big.Int) (*big.Int, error)
+}
+
+// Simulate quotes buys of token for the reference size and every trade size
+// across all V2 and V3 routes from the chain's first stablecoin and wrapped
+// native token, keeping the best output per size
+func (s *Simulator) Simulate(ctx context.Context, token string) (*Result, error) {
+	token = strings.ToLower(token)
+	if len(s.chain.Stablecoins) == 0 {
+		return nil, fmt.Errorf("no stablecoin configured for %s", s.chain.Name)
+	}
+	stable, native := s.chain.Stablecoins[0], s.chain.WrappedNative
+	if token == stable.Address || token == native.Address {
+		return nil, fmt.Errorf("token is a quote asset")
+	}
+
+	// Native-input routes need the native price, taken from the V2 router
+	nativeUSD, err := s.nativePriceUSD(ctx, stable, native)
+	if err != nil && !rpc.IsRevert(err) {
+		return nil, fmt.Errorf("pricing %s: %w", native.Symbol, err)
+	}
+
+	routes := s.routes(token, stable, native, nativeUSD > 0)
+
+	prices := map[chain.Token]float64{stable: 1, native: nativeUSD}
+	amountIn := func(r route, usd float64) *big.Int {
+		return toBaseUnits(usd/prices[r.input], r.input.Decimals)
+	}
+
+	// Routes that cannot fill the reference trade have no pool
+	refOut := new(big.Int)
+	var viable []route
+	for _, r := range routes {
+		out, err := r.quote(ctx, amountIn(r, referenceTradeUSD))
+		if err != nil {
+			if rpc.IsRevert(err) {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", r.name, err)
+		}
+		if out.Sign() == 0 {
+			continue
+		}
+		viable = append(viable, r)
+		if out.Cmp(refOut) > 0 {
+			refOut = out
+		}
+	}
+	if len(viable) == 0 {
+		return nil, fmt.Errorf("no %s or %s route to token", stable.Symbol, native.Symbol)
+	}
+	refPrice := ratio(refOut, referenceTradeUSD)
+
+	result := &Result{}
+	for _, size := range s.tradeSizes {
+		best := Trade{SizeUSD: size, PriceImpactPct: 100}
+		bestOut := new(big.Int)
+		for _, r := range viable {
+			out, err := r.quote(ctx, amountIn(r, size))
+			if err != nil {
+				if rpc.IsRevert(err) {
+					continue // Not enough liquidity in this pool
+				}
+				return nil, fmt.Errorf("%s: %w", r.name, err)
+			}
+			if out.Cmp(bestOut) > 0 {
+				bestOut = out
+				best.Route = r.name
+			}
+		}
+		best.AmountOut = bestOut.String()
+		if bestOut.Sign() > 0 {
+			impact := (1 - ratio(bestOut, size)/refPrice) * 100
+			best.PriceImpactPct = math.Round(math.Max(impact, 0)*100) / 100
+		}
+		result.Trades = append(result.Trades, best)
+	}
+
+	return result, nil
+}
+
+// routes lists every candidate buy route for token
+func (s *Simulator) routes(token string, stable, native chain.Token, withNative bool) []route {
+	var routes []route
+
+	if s.chain.V2Router != "" {
+		paths := [][]chain.Token{{stable}, {stable, native}}
+		if withNative {
+			paths = append(paths, []chain.Token{native})
+		}
+		for _, p := range paths {
+			path := make([]string, 0, len(p)+1)
+			names := make([]string, 0, len(p)+1)
+			for _, t := range p {
+				path = append(path, t.Address)
+				names = append(names, t.Symbol)
+			}
+			path = append(path, token)
+			names = append(names, "TOKEN")
+
+			routes = append(routes, route{
+				name:  "v2 " + strings.Join(names, ">"),
+				input: p[0],
+				quote: func(ctx context.Context, amountIn *big.Int) (*big.Int, error) {
+					return s.getAmountsOut(ctx, amountIn, path)
+				},
+			})
+		}
+	}
+
+	if s.chain.V3Quoter != "" {
+		inputs := []chain.Token{stable}
+		if withNative {
+			inputs = append(inputs, native)
+		}
+		for _, in := range inputs {
+			for _, fee := range s.chain.V3FeeTiers {
+				routes = append(routes, route{
+					name:  fmt.Sprintf("v3 %s>TOKEN %g%%", in.Symbol, float64(fee)/10_000),
+					input: in,
+					quote: func(ctx context.Context, amountIn *big.Int) (*big.Int, error) {
+						return s.quoteExactInputSingle(ctx, in.Address, token, amountIn, fee)
+					},
+				})
+			}
+		}
+	}
+
+	return routes
+}
+
+// nativePriceUSD prices one wrapped native token in the stablecoin
+func (s *Simulator) nativePriceUSD(ctx context.Context, stable, native chain.Token) (float64, error) {
+	if s.chain.V2Router == "" {
+		return 0, nil
+	}
+	out, err := s.getAmountsOut(ctx, toBaseUnits(1, native.Decimals), []string{native.Address, stable.Address})
+	if err != nil {
+		return 0, err
+	}
+	price, _ := new(big.Float).Quo(new(big.Float).SetInt(out), big.NewFloat(math.Pow10(stable.Decimals))).Float64()
+	return price, nil
+}
+
+// getAmountsOut calls the V2 router and returns the final output amount
+func (s *Simulator) getAmountsOut(ctx context.Context, amountIn *big.Int, path []string) (*big.Int, error) {
+	var data strings.Builder
+	data.WriteString(selectorGetAmountsOut)
+	data.WriteString(rpc.EncodeUint(amountIn))
+	data.WriteString(rpc.EncodeUint64(0x40)) // Offset of path
+	data.WriteString(rpc.EncodeUint64(uint64(len(path))))
+	for _, addr := range path {
+		data.WriteString(rpc.EncodeAddress(addr))
+	}
+
+	ret, err := s.client.EthCall(ctx, s.chain.V2Router, data.String())
+	if err != nil {
+		return nil, err
+	}
+	amounts, err := rpc.DecodeUintArray(ret)
+	if err != nil {
+		return nil, fmt.Errorf("decoding getAmountsOut: %w", err)
+	}
+	if len(amounts) != len(path) {
+		return nil, fmt.Errorf("getAmountsOut returned %d amounts for a %d-hop path", len(amounts), len(path))
+	}
+	return amounts[len(amounts)-1], nil
+}
+
+// quoteExactInputSingle calls the V3 QuoterV2 for a single-pool swap
+func (s *Simulator) quoteExactInputSingle(ctx context.Context, tokenIn, tokenOut string, amountIn *big.Int, fee uint32) (*big.Int, error) {
+	data := selectorQuoteExactInputSingle +
+		rpc.EncodeAddress(tokenIn) +
+		rpc.EncodeAddress(tokenOut) +
+		rpc.EncodeUint(amountIn) +
+		rpc.EncodeUint64(uint64(fee)) +
+		rpc.EncodeUint64(0) // sqrtPriceLimitX96: no limit
+
+	ret, err := s.client.EthCall(ctx, s.chain.V3Quoter, data)
+	if err != nil {
+		return nil, err
+	}
+	words, err := rpc.Words(ret)
+	if err != nil || len(words) == 0 {
+		return nil, fmt.Errorf("decoding quoteExactInputSingle: unexpected return data %q", ret)
+	}
+	return rpc.DecodeUint(words[0])
+}
+
+// toBaseUnits converts a decimal amount into integer base units
+func toBaseUnits(amount float64, decimals int) *big.Int {
+	f := new(big.Float).Mul(big.NewFloat(amount), new(big.Float).SetFloat64(math.Pow10(decimals)))
+	v, _ := f.Int(nil)
+	return v
+}
+
+// ratio returns out/usd as a float
+func ratio(out *big.Int, usd float64) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(out), big.NewFloat(usd)).Float64()
+	return f
+}
@@ -0,0 +1,67 @@
+package slippage_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/chain"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/mockapi"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/rpc"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/slippage"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/transport"
+)
+
+func newTestSimulator(srv *mockapi.Server) *slippage.Simulator {
+	httpClient := transport.NewClient(transport.ProviderRPC, transport.Limits{Timeout: 5 * time.Second})
+	return slippage.NewSimulator(chain.BSC, rpc.NewClient(srv.RPCURL(), httpClient))
+}
+
+func TestSimulate(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	sim := newTestSimulator(srv)
+
+	tests := []struct {
+		name      string
+		address   string
+		impact1K  float64 // Expected impact in percent (constant-product pools in rpc/pools.json)
+		impact10K float64
+		route     string // Substring of the $1K route
+	}{
+		// $4M USDT / CAKE V2 pool plus a CAKE/WBNB V3 pool: negligible impact
+		{"deep", "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82", 0.04, 0.33, "TOKEN"},
+		// $20K USDT pool: 1 - (20000 + 9.975) / (20000 + 997.5)
+		{"thin", "0x00000000000000000000000000000000000000dd", 4.70, 33.24, "v2 USDT>TOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := sim.Simulate(context.Background(), tt.address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Trades) != 2 {
+				t.Fatalf("got %d trades, want 2", len(result.Trades))
+			}
+
+			impact, ok := result.Impact(slippage.ScoredTradeUSD)
+			if !ok || math.Abs(impact-tt.impact1K) > 0.02 {
+				t.Errorf("$1K impact = %.2f%%, want %.2f%%", impact, tt.impact1K)
+			}
+			if got := result.Trades[1].PriceImpactPct; math.Abs(got-tt.impact10K) > 0.02 {
+				t.Errorf("$10K impact = %.2f%%, want %.2f%%", got, tt.impact10K)
+			}
+			if !strings.Contains(result.Trades[0].Route, tt.route) {
+				t.Errorf("$1K route = %q, want %q", result.Trades[0].Route, tt.route)
+			}
+		})
+	}
+
+	// No pool against USDT or WBNB
+	if _, err := sim.Simulate(context.Background(), "0x00000000000000000000000000000000000000bc"); err == nil || !strings.Contains(err.Error(), "no USDT or WBNB route") {
+		t.Errorf("Simulate without pools: err = %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+)
+
+// MemoryStore is an in-process ResultStore used when no DATABASE_URL is
+// configured and as a stand-in for Postgres in tests. Only the latest result
+// per chain and token is kept.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	listing   pipeline.ListingThresholds
+	nextRunID int64
+	results   map[string]pipeline.TokenResult // Keyed by "chain/address"
+}
+
+func NewMemoryStore(listing pipeline.ListingThresholds) *MemoryStore {
+	return &MemoryStore{
+		listing: listing,
+		results: make(map[string]pipeline.TokenResult),
+	}
+}
+
+func (m *MemoryStore) StartRun(ctx context.Context, source string, totalTokens int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextRunID++
+	return m.nextRunID, nil
+}
+
+func (m *MemoryStore) FinishRun(ctx context.Context, runID int64) error {
+	return nil
+}
+
+func (m *MemoryStore) SaveResult(ctx context.Context, runID int64, r pipeline.TokenResult) error {
+	if r.CheckedAt.IsZero() {
+		r.CheckedAt = time.Now()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[resultChain(r)+"/"+strings.ToLower(r.Address)] = r
+	return nil
+}
+
+func (m *MemoryStore) LatestResult(ctx context.Context, chain, address string) (*pipeline.TokenResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	address = strings.ToLower(address)
+	var latest *pipeline.TokenResult
+	for _, r := range m.results {
+		if strings.ToLower(r.Address) != address || (chain != "" && resultChain(r) != chain) {
+			continue
+		}
+		if latest == nil || r.CheckedAt.After(latest.CheckedAt) {
+			latest = &r
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+	return latest, nil
+}
+
+func (m *MemoryStore) ListTokens(ctx context.Context, chain, status string, limit int) ([]models.Token, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var tokens []models.Token
+	for _, r := range m.results {
+		listingStatus := r.ListingStatus(m.listing)
+		if status != "" && listingStatus != status {
+			continue
+		}
+		if chain != "" && resultChain(r) != chain {
+			continue
+		}
+		tokens = append(tokens, models.Token{
+			Chain:              resultChain(r),
+			Address:            strings.ToLower(r.Address),
+			Name:               r.Name,
+			Symbol:             r.Symbol,
+			Decimals:           r.Decimals,
+			Verified:           r.Verified,
+			LiquidityUSD:       r.Liquidity,
+			Volume24h:          r.Volume,
+			Top10Concentration: r.Concentration,
+			CompositeScore:     r.Score,
+			Status:             listingStatus,
+			CheckedAt:          r.CheckedAt,
+		})
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].CompositeScore != tokens[j].CompositeScore {
+			return tokens[i].CompositeScore > tokens[j].CompositeScore
+		}
+		if tokens[i].Chain != tokens[j].Chain {
+			return tokens[i].Chain < tokens[j].Chain
+		}
+		return tokens[i].Address < tokens[j].Address
+	})
+
+	if len(tokens) > limit {
+		tokens = tokens[:limit]
+	}
+	return tokens, nil
+}
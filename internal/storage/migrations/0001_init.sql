@@ -0,0 +1,75 @@
+-- Latest known state of every screened token (mirrors models.Token)
+CREATE TABLE IF NOT EXISTS tokens (
+    address         TEXT PRIMARY KEY,
+    name            TEXT NOT NULL DEFAULT '',
+    symbol          TEXT NOT NULL DEFAULT '',
+    decimals        INTEGER NOT NULL DEFAULT 0,
+    verified        BOOLEAN NOT NULL DEFAULT FALSE,
+    liquidity_usd   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    volume_24h      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    top10_holders   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    composite_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    status          TEXT NOT NULL DEFAULT 'hidden',
+    checked_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tokens_status_score_idx ON tokens (status, composite_score DESC);
+
+-- One row per pipeline invocation
+CREATE TABLE IF NOT EXISTS screening_runs (
+    id           BIGSERIAL PRIMARY KEY,
+    source       TEXT NOT NULL DEFAULT '',
+    total_tokens INTEGER NOT NULL DEFAULT 0,
+    started_at   TIMESTAMPTZ NOT NULL,
+    finished_at  TIMESTAMPTZ
+);
+
+-- Per-run outcome for each token
+CREATE TABLE IF NOT EXISTS token_results (
+    id              BIGSERIAL PRIMARY KEY,
+    run_id          BIGINT NOT NULL REFERENCES screening_runs (id) ON DELETE CASCADE,
+    address         TEXT NOT NULL REFERENCES tokens (address) ON DELETE CASCADE,
+    status          TEXT NOT NULL,
+    stage           TEXT NOT NULL DEFAULT '',
+    listing_status  TEXT NOT NULL,
+    error_reason    TEXT NOT NULL DEFAULT '',
+    failure_reasons JSONB NOT NULL DEFAULT '[]',
+    warnings        JSONB NOT NULL DEFAULT '[]',
+    score           DOUBLE PRECISION NOT NULL DEFAULT 0,
+    liquidity_usd   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    volume_24h      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    pool_age_days   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    fragmented      BOOLEAN NOT NULL DEFAULT FALSE,
+    top10_holders   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    verified        BOOLEAN NOT NULL DEFAULT FALSE,
+    is_honeypot     BOOLEAN NOT NULL DEFAULT FALSE,
+    fraud_risk      INTEGER NOT NULL DEFAULT 0,
+    checked_at      TIMESTAMPTZ NOT NULL,
+    UNIQUE (run_id, address)
+);
+
+CREATE INDEX IF NOT EXISTS token_results_address_idx ON token_results (address, checked_at DESC);
+
+-- Scoring breakdown for tokens that reached the scoring stage
+CREATE TABLE IF NOT EXISTS token_scores (
+    result_id           BIGINT PRIMARY KEY REFERENCES token_results (id) ON DELETE CASCADE,
+    liquidity_score     DOUBLE PRECISION NOT NULL,
+    volume_score        DOUBLE PRECISION NOT NULL,
+    holder_score        DOUBLE PRECISION NOT NULL,
+    fragmentation_score DOUBLE PRECISION NOT NULL,
+    composite_score     DOUBLE PRECISION NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS risk_factors (
+    result_id BIGINT NOT NULL REFERENCES token_results (id) ON DELETE CASCADE,
+    factor    TEXT NOT NULL,
+    PRIMARY KEY (result_id, factor)
+);
+
+-- Raw API responses kept for auditing and re-scoring
+CREATE TABLE IF NOT EXISTS provider_payloads (
+    result_id BIGINT NOT NULL REFERENCES token_results (id) ON DELETE CASCADE,
+    provider  TEXT NOT NULL,
+    payload   JSONB NOT NULL,
+    PRIMARY KEY (result_id, provider)
+);
@@ -0,0 +1,2 @@
+-- Full TokenResult document (including fraud verdict and score breakdown) for API reads
+ALTER TABLE token_results ADD COLUMN IF NOT EXISTS result JSONB;
@@ -0,0 +1,16 @@
+-- Tokens are identified by (chain, address): the same address can be a
+-- different token on another chain. Existing rows were all screened on BSC.
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS chain TEXT NOT NULL DEFAULT 'bsc';
+ALTER TABLE token_results ADD COLUMN IF NOT EXISTS chain TEXT NOT NULL DEFAULT 'bsc';
+
+ALTER TABLE token_results DROP CONSTRAINT IF EXISTS token_results_address_fkey;
+ALTER TABLE token_results DROP CONSTRAINT IF EXISTS token_results_run_id_address_key;
+ALTER TABLE tokens DROP CONSTRAINT IF EXISTS tokens_pkey;
+
+ALTER TABLE tokens ADD PRIMARY KEY (chain, address);
+ALTER TABLE token_results ADD CONSTRAINT token_results_run_id_chain_address_key UNIQUE (run_id, chain, address);
+ALTER TABLE token_results ADD CONSTRAINT token_results_chain_address_fkey
+    FOREIGN KEY (chain, address) REFERENCES tokens (chain, address) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS token_results_address_idx;
+CREATE INDEX IF NOT EXISTS token_results_chain_address_idx ON token_results (chain, address, checked_at DESC);
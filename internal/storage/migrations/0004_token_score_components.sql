@@ -0,0 +1,6 @@
+-- Contract, fraud and slippage sub-scores joined the composite after 0001
+ALTER TABLE token_scores ADD COLUMN IF NOT EXISTS contract_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE token_scores ADD COLUMN IF NOT EXISTS fraud_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE token_scores ADD COLUMN IF NOT EXISTS slippage_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE token_scores ADD COLUMN IF NOT EXISTS slippage_impact_pct DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE token_scores ADD COLUMN IF NOT EXISTS slippage_simulated BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,13 @@
+-- Per-pair market state recorded on every screening cycle, for drain detection
+CREATE TABLE IF NOT EXISTS liquidity_snapshots (
+    chain         TEXT NOT NULL,
+    token         TEXT NOT NULL,
+    pair          TEXT NOT NULL,
+    dex_id        TEXT NOT NULL DEFAULT '',
+    quote         TEXT NOT NULL DEFAULT '',
+    liquidity_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+    volume_24h    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    price_usd     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    taken_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (chain, token, taken_at, pair)
+);
@@ -0,0 +1,15 @@
+-- State changes of tokens under the watch command, e.g. FEATURED -> FAILED
+CREATE TABLE IF NOT EXISTS watch_transitions (
+    id         BIGSERIAL PRIMARY KEY,
+    chain      TEXT NOT NULL,
+    address    TEXT NOT NULL,
+    symbol     TEXT NOT NULL DEFAULT '',
+    from_state TEXT NOT NULL DEFAULT '',
+    to_state   TEXT NOT NULL,
+    from_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    to_score   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    reasons    JSONB NOT NULL DEFAULT '[]',
+    changed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS watch_transitions_chain_address_idx ON watch_transitions (chain, address, changed_at DESC);
@@ -0,0 +1,290 @@
+// Package storage persists screening results to Postgres
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+)
+
+// ErrNotFound is returned when no result exists for a token
+var ErrNotFound = errors.New("not found")
+
+// ResultStore is implemented by the Postgres Store and the in-memory MemoryStore
+type ResultStore interface {
+	StartRun(ctx context.Context, source string, totalTokens int) (int64, error)
+	FinishRun(ctx context.Context, runID int64) error
+	SaveResult(ctx context.Context, runID int64, r pipeline.TokenResult) error
+	LatestResult(ctx context.Context, chain, address string) (*pipeline.TokenResult, error)
+	ListTokens(ctx context.Context, chain, status string, limit int) ([]models.Token, error)
+}
+
+type Store struct {
+	db      *sql.DB
+	listing pipeline.ListingThresholds
+}
+
+// Open connects to Postgres and applies pending migrations
+func Open(ctx context.Context, databaseURL string, listing pipeline.ListingThresholds) (*Store, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	store := New(db, listing)
+	if err := store.Migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// New wraps an existing connection. Call Migrate before use on a fresh database.
+func New(db *sql.DB, listing pipeline.ListingThresholds) *Store {
+	return &Store{db: db, listing: listing}
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// StartRun records the start of a screening run and returns its ID
+func (s *Store) StartRun(ctx context.Context, source string, totalTokens int) (int64, error) {
+	var runID int64
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO screening_runs (source, total_tokens, started_at) VALUES ($1, $2, $3) RETURNING id`,
+		source, totalTokens, time.Now()).Scan(&runID)
+	return runID, err
+}
+
+// FinishRun marks a run as complete
+func (s *Store) FinishRun(ctx context.Context, runID int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE screening_runs SET finished_at = $2 WHERE id = $1`, runID, time.Now())
+	return err
+}
+
+// SaveResult upserts the token row and stores the run result with its
+// sub-scores, risk factors and raw provider payloads in one transaction
+func (s *Store) SaveResult(ctx context.Context, runID int64, r pipeline.TokenResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	address := strings.ToLower(r.Address)
+	chain := resultChain(r)
+	listingStatus := r.ListingStatus(s.listing)
+	checkedAt := r.CheckedAt
+	if checkedAt.IsZero() {
+		checkedAt = time.Now()
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tokens (chain, address, name, symbol, decimals, verified, liquidity_usd, volume_24h,
+			top10_holders, composite_score, status, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (chain, address) DO UPDATE SET
+			name = EXCLUDED.name,
+			symbol = EXCLUDED.symbol,
+			decimals = EXCLUDED.decimals,
+			verified = EXCLUDED.verified,
+			liquidity_usd = EXCLUDED.liquidity_usd,
+			volume_24h = EXCLUDED.volume_24h,
+			top10_holders = EXCLUDED.top10_holders,
+			composite_score = EXCLUDED.composite_score,
+			status = EXCLUDED.status,
+			checked_at = EXCLUDED.checked_at`,
+		chain, address, r.Name, r.Symbol, r.Decimals, r.Verified, r.Liquidity, r.Volume,
+		r.Concentration, r.Score, listingStatus, checkedAt)
+	if err != nil {
+		return fmt.Errorf("upserting token %s: %w", address, err)
+	}
+
+	document, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding result for %s: %w", address, err)
+	}
+	failureReasons, _ := json.Marshal(nonNil(r.FailureReasons))
+	warnings, _ := json.Marshal(nonNil(r.Warnings))
+
+	var isHoneypot bool
+	var fraudRisk int
+	if r.Fraud != nil {
+		isHoneypot = r.Fraud.IsHoneypot
+		fraudRisk = r.Fraud.RiskScore
+	}
+
+	var resultID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO token_results (run_id, chain, address, status, stage, listing_status, error_reason,
+			failure_reasons, warnings, score, liquidity_usd, volume_24h, pool_age_days, fragmented,
+			top10_holders, verified, is_honeypot, fraud_risk, checked_at, result)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		ON CONFLICT (run_id, chain, address) DO UPDATE SET
+			status = EXCLUDED.status,
+			stage = EXCLUDED.stage,
+			listing_status = EXCLUDED.listing_status,
+			error_reason = EXCLUDED.error_reason,
+			failure_reasons = EXCLUDED.failure_reasons,
+			warnings = EXCLUDED.warnings,
+			score = EXCLUDED.score,
+			liquidity_usd = EXCLUDED.liquidity_usd,
+			volume_24h = EXCLUDED.volume_24h,
+			pool_age_days = EXCLUDED.pool_age_days,
+			fragmented = EXCLUDED.fragmented,
+			top10_holders = EXCLUDED.top10_holders,
+			verified = EXCLUDED.verified,
+			is_honeypot = EXCLUDED.is_honeypot,
+			fraud_risk = EXCLUDED.fraud_risk,
+			checked_at = EXCLUDED.checked_at,
+			result = EXCLUDED.result
+		RETURNING id`,
+		runID, chain, address, r.Status, r.Stage, listingStatus, r.ErrorReason,
+		string(failureReasons), string(warnings), r.Score, r.Liquidity, r.Volume, r.Age, r.Fragmented,
+		r.Concentration, r.Verified, isHoneypot, fraudRisk, checkedAt, string(document)).Scan(&resultID)
+	if err != nil {
+		return fmt.Errorf("inserting result for %s: %w", address, err)
+	}
+
+	// Child rows are replaced wholesale when a result is re-saved
+	for _, table := range []string{"token_scores", "risk_factors", "provider_payloads"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE result_id = $1", resultID); err != nil {
+			return err
+		}
+	}
+
+	if score := r.TokenScore; score != nil {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO token_scores (result_id, liquidity_score, volume_score, holder_score,
+				fragmentation_score, contract_score, fraud_score, slippage_score, slippage_impact_pct,
+				slippage_simulated, composite_score)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			resultID, score.LiquidityScore, score.VolumeScore, score.HolderScore,
+			score.FragmentationScore, score.ContractScore, score.FraudScore, score.SlippageScore,
+			score.SlippageImpactPct, score.SlippageSimulated, score.CompositeScore)
+		if err != nil {
+			return fmt.Errorf("inserting scores for %s: %w", address, err)
+		}
+	}
+
+	for _, factor := range r.RiskFactors {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO risk_factors (result_id, factor) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			resultID, factor)
+		if err != nil {
+			return fmt.Errorf("inserting risk factor for %s: %w", address, err)
+		}
+	}
+
+	for provider, payload := range providerPayloads(r) {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO provider_payloads (result_id, provider, payload) VALUES ($1, $2, $3)`,
+			resultID, provider, string(payload))
+		if err != nil {
+			return fmt.Errorf("inserting %s payload for %s: %w", provider, address, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LatestResult returns the most recent stored result for a token. An empty
+// chain matches the token on any chain.
+func (s *Store) LatestResult(ctx context.Context, chain, address string) (*pipeline.TokenResult, error) {
+	var document []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT result FROM token_results
+		WHERE address = $1 AND ($2 = '' OR chain = $2) AND result IS NOT NULL
+		ORDER BY checked_at DESC, id DESC
+		LIMIT 1`, strings.ToLower(address), chain).Scan(&document)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result pipeline.TokenResult
+	if err := json.Unmarshal(document, &result); err != nil {
+		return nil, fmt.Errorf("decoding result for %s: %w", address, err)
+	}
+	return &result, nil
+}
+
+// ListTokens returns tokens in a listing status ordered by score.
+// An empty chain or status matches every token.
+func (s *Store) ListTokens(ctx context.Context, chain, status string, limit int) ([]models.Token, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain, address, name, symbol, decimals, verified, liquidity_usd, volume_24h,
+			top10_holders, composite_score, status, checked_at
+		FROM tokens
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR chain = $2)
+		ORDER BY composite_score DESC, chain, address
+		LIMIT $3`, status, chain, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []models.Token
+	for rows.Next() {
+		var t models.Token
+		if err := rows.Scan(&t.Chain, &t.Address, &t.Name, &t.Symbol, &t.Decimals, &t.Verified, &t.LiquidityUSD,
+			&t.Volume24h, &t.Top10Concentration, &t.CompositeScore, &t.Status, &t.CheckedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+// providerPayloads collects the raw JSON responses kept on the result.
+// Payloads that are not valid JSON are skipped since the column is JSONB.
+func providerPayloads(r pipeline.TokenResult) map[string][]byte {
+	payloads := make(map[string][]byte)
+	if r.Fraud == nil {
+		return payloads
+	}
+	if r.Fraud.HoneypotData != nil && json.Valid(r.Fraud.HoneypotData.Raw) {
+		payloads["honeypot"] = r.Fraud.HoneypotData.Raw
+	}
+	if r.Fraud.GoPlusData != nil && json.Valid(r.Fraud.GoPlusData.Raw) {
+		payloads["goplus"] = r.Fraud.GoPlusData.Raw
+	}
+	return payloads
+}
+
+// resultChain returns the chain a result was screened on. Results from
+// before multi-chain support carry no chain and were screened on BSC.
+func resultChain(r pipeline.TokenResult) string {
+	if r.Chain == "" {
+		return "bsc"
+	}
+	return r.Chain
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
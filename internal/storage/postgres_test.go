@@ -0,0 +1,146 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/scoring"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/storage"
+)
+
+var listing = pipeline.ListingThresholds{Featured: 70, Visible: 50}
+
+// testDB connects to TEST_DATABASE_URL in a schema of its own, dropped when
+// the test ends. The test is skipped when the variable is not set.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	admin, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	schema := fmt.Sprintf("screener_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	db, err := sql.Open("pgx", u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestPostgresStore(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	store := storage.New(db, listing)
+
+	// Processes starting together take turns, and a second run is a no-op
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = store.Migrate(ctx)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	files, err := os.ReadDir("migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var applied int
+	if err := db.QueryRow(`SELECT count(*) FROM schema_migrations`).Scan(&applied); err != nil || applied != len(files) {
+		t.Errorf("schema_migrations has %d rows (%v), want %d", applied, err, len(files))
+	}
+
+	const cake = "0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82"
+	result := pipeline.TokenResult{
+		Chain: "bsc", Symbol: "CAKE", Name: "PancakeSwap Token", Decimals: 18, Address: cake,
+		Status: "PASSED", Stage: "scoring", Score: 62, Liquidity: 5e6, Verified: true,
+		CheckedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		TokenScore: &scoring.TokenScore{LiquidityScore: 90, ContractScore: 80, FraudScore: 95,
+			SlippageScore: 70, SlippageImpactPct: 1.5, SlippageSimulated: true, CompositeScore: 62},
+	}
+	runID, err := store.StartRun(ctx, "test", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveResult(ctx, runID, result); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving again in the same run replaces the result and its sub-scores
+	result.Score, result.TokenScore.CompositeScore, result.TokenScore.SlippageScore = 75, 75, 85
+	if err := store.SaveResult(ctx, runID, result); err != nil {
+		t.Fatalf("re-saving: %v", err)
+	}
+	if err := store.FinishRun(ctx, runID); err != nil {
+		t.Fatal(err)
+	}
+
+	var results int
+	var contractScore, fraudScore, slippageScore float64
+	err = db.QueryRow(`
+		SELECT count(*), max(s.contract_score), max(s.fraud_score), max(s.slippage_score)
+		FROM token_results r JOIN token_scores s ON s.result_id = r.id
+		WHERE r.run_id = $1`, runID).Scan(&results, &contractScore, &fraudScore, &slippageScore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results != 1 || contractScore != 80 || fraudScore != 95 || slippageScore != 85 {
+		t.Errorf("stored %d results with contract %.0f, fraud %.0f, slippage %.0f", results, contractScore, fraudScore, slippageScore)
+	}
+
+	latest, err := store.LatestResult(ctx, "", cake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Score != 75 || latest.TokenScore == nil || latest.TokenScore.SlippageScore != 85 {
+		t.Errorf("LatestResult = %+v", latest)
+	}
+	if _, err := store.LatestResult(ctx, "ethereum", cake); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("LatestResult on another chain = %v, want ErrNotFound", err)
+	}
+
+	tokens, err := store.ListTokens(ctx, "bsc", result.ListingStatus(listing), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 1 || tokens[0].Address != "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82" || tokens[0].CompositeScore != 75 {
+		t.Errorf("ListTokens = %+v", tokens)
+	}
+	if tokens, err := store.ListTokens(ctx, "ethereum", "", 10); err != nil || len(tokens) != 0 {
+		t.Errorf("ListTokens on another chain = %+v, %v", tokens, err)
+	}
+}
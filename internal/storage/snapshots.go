@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/liquidity"
+)
+
+// SaveSnapshots records one screening cycle's pairs in one transaction
+func (s *Store) SaveSnapshots(ctx context.Context, snapshots []liquidity.Snapshot) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, snap := range snapshots {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO liquidity_snapshots (chain, token, pair, dex_id, quote, liquidity_usd, volume_24h, price_usd, taken_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (chain, token, taken_at, pair) DO UPDATE SET
+				liquidity_usd = EXCLUDED.liquidity_usd,
+				volume_24h = EXCLUDED.volume_24h,
+				price_usd = EXCLUDED.price_usd`,
+			snap.Chain, strings.ToLower(snap.Token), strings.ToLower(snap.Pair), snap.DexID, snap.Quote,
+			snap.LiquidityUSD, snap.Volume24hUSD, snap.PriceUSD, snap.At)
+		if err != nil {
+			return fmt.Errorf("inserting snapshot of %s: %w", snap.Pair, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// History returns a token's snapshots taken at or after since, oldest first
+func (s *Store) History(ctx context.Context, chain, token string, since time.Time) ([]liquidity.Snapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain, token, pair, dex_id, quote, liquidity_usd, volume_24h, price_usd, taken_at
+		FROM liquidity_snapshots
+		WHERE chain = $1 AND token = $2 AND taken_at >= $3
+		ORDER BY taken_at, pair`, chain, strings.ToLower(token), since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []liquidity.Snapshot
+	for rows.Next() {
+		var snap liquidity.Snapshot
+		if err := rows.Scan(&snap.Chain, &snap.Token, &snap.Pair, &snap.DexID, &snap.Quote,
+			&snap.LiquidityUSD, &snap.Volume24hUSD, &snap.PriceUSD, &snap.At); err != nil {
+			return nil, err
+		}
+		history = append(history, snap)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/watch"
+)
+
+// RecordTransition appends a watched token's state change
+func (s *Store) RecordTransition(ctx context.Context, t watch.Transition) error {
+	reasons, _ := json.Marshal(nonNil(t.Reasons))
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO watch_transitions (chain, address, symbol, from_state, to_state, from_score, to_score, reasons, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		t.Chain, strings.ToLower(t.Address), t.Symbol, t.From, t.To, t.FromScore, t.ToScore, reasons, t.At)
+	return err
+}
+
+// LatestTransitions returns each token's most recent transition on chain,
+// keyed by lowercase address
+func (s *Store) LatestTransitions(ctx context.Context, chain string) (map[string]watch.Transition, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (address) chain, address, symbol, from_state, to_state, from_score, to_score, reasons, changed_at
+		FROM watch_transitions
+		WHERE chain = $1
+		ORDER BY address, changed_at DESC, id DESC`, chain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	latest := make(map[string]watch.Transition)
+	for rows.Next() {
+		t, err := scanTransition(rows)
+		if err != nil {
+			return nil, err
+		}
+		latest[t.Address] = t
+	}
+	return latest, rows.Err()
+}
+
+// Transitions returns a token's transitions, oldest first
+func (s *Store) Transitions(ctx context.Context, chain, address string) ([]watch.Transition, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain, address, symbol, from_state, to_state, from_score, to_score, reasons, changed_at
+		FROM watch_transitions
+		WHERE chain = $1 AND address = $2
+		ORDER BY changed_at, id`, chain, strings.ToLower(address))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []watch.Transition
+	for rows.Next() {
+		t, err := scanTransition(rows)
+		if err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+func scanTransition(rows *sql.Rows) (watch.Transition, error) {
+	var t watch.Transition
+	var reasons []byte
+	if err := rows.Scan(&t.Chain, &t.Address, &t.Symbol, &t.From, &t.To, &t.FromScore, &t.ToScore, &reasons, &t.At); err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(reasons, &t.Reasons); err != nil {
+		return t, fmt.Errorf("decoding transition reasons for %s: %w", t.Address, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,321 @@
+// Package transport provides the shared HTTP layer used by all API clients:
+// per-provider token-bucket rate limiting, retries with exponential backoff
+// and jitter, Retry-After handling and request counters.
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Provider names used to key limits and counters
+const (
+	ProviderDexScreener = "dexscreener"
+	ProviderHoneypot    = "honeypot"
+	ProviderGoPlus      = "goplus"
+	ProviderEtherscan   = "etherscan"
+	ProviderRPC         = "rpc"    // JSON-RPC node (public endpoint, private node or local fork)
+	ProviderNotify      = "notify" // Alert webhooks (generic, Slack, Telegram)
+)
+
+// Limits configures rate limiting and retry behaviour for one provider
+type Limits struct {
+	RequestsPerSecond float64       `yaml:"requests_per_second"` // Token refill rate (<= 0 disables limiting)
+	Burst             int           `yaml:"burst"`               // Bucket size
+	MaxRetries        int           `yaml:"max_retries"`         // Retries after the first attempt
+	BaseBackoff       time.Duration `yaml:"base_backoff"`        // First retry delay before jitter (<= 0 uses 500ms)
+	MaxBackoff        time.Duration `yaml:"max_backoff"`         // Upper bound for backoff delays, not Retry-After (<= 0 uses 30s)
+	Timeout           time.Duration `yaml:"timeout"`             // Per-attempt HTTP timeout
+}
+
+// MarshalYAML writes durations as "500ms" rather than nanoseconds, the form
+// config files use
+func (l Limits) MarshalYAML() (any, error) {
+	return struct {
+		RequestsPerSecond float64 `yaml:"requests_per_second"`
+		Burst             int     `yaml:"burst"`
+		MaxRetries        int     `yaml:"max_retries"`
+		BaseBackoff       string  `yaml:"base_backoff"`
+		MaxBackoff        string  `yaml:"max_backoff"`
+		Timeout           string  `yaml:"timeout"`
+	}{l.RequestsPerSecond, l.Burst, l.MaxRetries, l.BaseBackoff.String(), l.MaxBackoff.String(), l.Timeout.String()}, nil
+}
+
+// Backoff bounds used when Limits leaves them unset
+const (
+	defaultBaseBackoff = 500 * time.Millisecond
+	defaultMaxBackoff  = 30 * time.Second
+)
+
+// DefaultLimits returns conservative limits suitable for free API tiers
+func DefaultLimits(provider string) Limits {
+	limits := Limits{
+		RequestsPerSecond: 2,
+		Burst:             2,
+		MaxRetries:        4,
+		BaseBackoff:       defaultBaseBackoff,
+		MaxBackoff:        defaultMaxBackoff,
+		Timeout:           10 * time.Second,
+	}
+
+	switch provider {
+	case ProviderDexScreener:
+		limits.RequestsPerSecond, limits.Burst = 4, 4
+	case ProviderEtherscan:
+		limits.RequestsPerSecond, limits.Burst = 4, 4
+		limits.Timeout = 5 * time.Second
+	case ProviderGoPlus:
+		limits.RequestsPerSecond, limits.Burst = 1, 2
+	case ProviderRPC:
+		limits.RequestsPerSecond, limits.Burst = 10, 10
+		limits.Timeout = 5 * time.Second
+	case ProviderNotify:
+		limits.RequestsPerSecond, limits.Burst = 1, 5
+		limits.MaxRetries = 3
+	}
+
+	return limits
+}
+
+// Stats holds per-provider request counters
+type Stats struct {
+	Requests      int64         `json:"requests"`       // Attempts sent (including retries)
+	Retries       int64         `json:"retries"`        // Attempts that were retries
+	RateLimited   int64         `json:"rate_limited"`   // 429 responses
+	ServerErrors  int64         `json:"server_errors"`  // 5xx responses
+	NetworkErrors int64         `json:"network_errors"` // Transport-level failures
+	Failures      int64         `json:"failures"`       // Calls that failed after all retries
+	Throttled     time.Duration `json:"throttled_ns"`   // Total time spent waiting on the bucket
+}
+
+type counters struct {
+	requests, retries, rateLimited, serverErrors, networkErrors, failures, throttled atomic.Int64
+}
+
+// Client is a rate-limited, retrying HTTP client for a single provider.
+// It is safe for concurrent use.
+type Client struct {
+	provider   string
+	limits     Limits
+	httpClient *http.Client
+	bucket     *TokenBucket
+	counters   counters
+}
+
+// NewClient creates a client for provider with the given limits
+func NewClient(provider string, limits Limits) *Client {
+	return &Client{
+		provider:   provider,
+		limits:     limits,
+		httpClient: &http.Client{Timeout: limits.Timeout},
+		bucket:     NewTokenBucket(limits.RequestsPerSecond, limits.Burst),
+	}
+}
+
+// Provider returns the provider name this client is registered under
+func (c *Client) Provider() string {
+	return c.provider
+}
+
+// Get issues a GET request with rate limiting and retries. Cancelling ctx
+// abandons the request and any remaining retries.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends the request, retrying on network errors, 429 and 5xx responses.
+// A Retry-After header is waited out in full, even beyond MaxBackoff, for as
+// long as the request's context allows; when its deadline would pass first
+// the call fails at once. Requests with a body must set GetBody so the body
+// can be replayed.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.limits.MaxRetries; attempt++ {
+		if attempt > 0 {
+			c.counters.retries.Add(1)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+
+		waited, err := c.bucket.Wait(ctx)
+		c.counters.throttled.Add(int64(waited))
+		if err != nil {
+			c.counters.failures.Add(1)
+			return nil, err
+		}
+
+		c.counters.requests.Add(1)
+		resp, err := c.httpClient.Do(req)
+
+		var retryAfter time.Duration
+		switch {
+		case err != nil:
+			c.counters.networkErrors.Add(1)
+			lastErr = err
+		case resp.StatusCode == http.StatusTooManyRequests:
+			c.counters.rateLimited.Add(1)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			lastErr = fmt.Errorf("%s: rate limited (HTTP %d)", c.provider, resp.StatusCode)
+			drain(resp)
+		case resp.StatusCode >= 500:
+			c.counters.serverErrors.Add(1)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			lastErr = fmt.Errorf("%s: server error (HTTP %d)", c.provider, resp.StatusCode)
+			drain(resp)
+		default:
+			return resp, nil
+		}
+
+		if attempt == c.limits.MaxRetries {
+			break
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter > 0 {
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < retryAfter {
+				c.counters.failures.Add(1)
+				return nil, fmt.Errorf("%w; retry after %s outlasts the request: %w", lastErr, retryAfter, context.DeadlineExceeded)
+			}
+			delay = retryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			c.counters.failures.Add(1)
+			return nil, ctx.Err()
+		}
+	}
+
+	c.counters.failures.Add(1)
+	return nil, fmt.Errorf("%s: giving up after %d attempts: %w", c.provider, c.limits.MaxRetries+1, lastErr)
+}
+
+// Stats returns a snapshot of the client's counters
+func (c *Client) Stats() Stats {
+	return Stats{
+		Requests:      c.counters.requests.Load(),
+		Retries:       c.counters.retries.Load(),
+		RateLimited:   c.counters.rateLimited.Load(),
+		ServerErrors:  c.counters.serverErrors.Load(),
+		NetworkErrors: c.counters.networkErrors.Load(),
+		Failures:      c.counters.failures.Load(),
+		Throttled:     time.Duration(c.counters.throttled.Load()),
+	}
+}
+
+// backoff returns an exponential delay with full jitter for the given
+// attempt, never above maxBackoff
+func (c *Client) backoff(attempt int) time.Duration {
+	limit := c.maxBackoff()
+	ceiling := c.limits.BaseBackoff
+	if ceiling <= 0 {
+		ceiling = defaultBaseBackoff
+	}
+	// Doubling stops at the limit, so large attempts cannot overflow
+	for i := 0; i < attempt && ceiling < limit; i++ {
+		ceiling *= 2
+	}
+	if ceiling > limit || ceiling <= 0 {
+		ceiling = limit
+	}
+	return ceiling/2 + rand.N(ceiling/2+1)
+}
+
+// maxBackoff is the longest the client waits between attempts
+func (c *Client) maxBackoff() time.Duration {
+	if c.limits.MaxBackoff <= 0 {
+		return defaultMaxBackoff
+	}
+	return c.limits.MaxBackoff
+}
+
+// parseRetryAfter understands both delta-seconds and HTTP-date values
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+// Registry hands out one shared Client per provider so that every API client
+// talking to the same provider draws from the same bucket
+type Registry struct {
+	mu      sync.Mutex
+	limits  map[string]Limits
+	clients map[string]*Client
+}
+
+// NewRegistry creates a registry. Providers missing from limits use DefaultLimits.
+func NewRegistry(limits map[string]Limits) *Registry {
+	return &Registry{
+		limits:  limits,
+		clients: make(map[string]*Client),
+	}
+}
+
+// For returns the shared client for provider, creating it on first use
+func (r *Registry) For(provider string) *Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if client, ok := r.clients[provider]; ok {
+		return client
+	}
+
+	limits, ok := r.limits[provider]
+	if !ok {
+		limits = DefaultLimits(provider)
+	}
+	client := NewClient(provider, limits)
+	r.clients[provider] = client
+	return client
+}
+
+// Stats returns counters for every provider used so far
+func (r *Registry) Stats() map[string]Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[string]Stats, len(r.clients))
+	for provider, client := range r.clients {
+		stats[provider] = client.Stats()
+	}
+	return stats
+}
+
+// Default is used by API clients constructed without an explicit transport
+var Default = NewRegistry(nil)
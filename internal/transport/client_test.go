@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fast retries quickly and is not rate limited
+var fast = Limits{MaxRetries: 2, BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Timeout: 5 * time.Second}
+
+// replay answers each request with the next status in statuses, repeating
+// the last one, and records the request bodies
+type replay struct {
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	bodies   []string
+}
+
+func (r *replay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.bodies = append(r.bodies, string(body))
+	status := r.statuses[min(len(r.bodies), len(r.statuses))-1]
+	r.mu.Unlock()
+
+	if status != http.StatusOK {
+		for k, v := range r.header {
+			w.Header()[k] = v
+		}
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(`{}`))
+}
+
+func serve(t *testing.T, h http.Handler) string {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestRetriesServerErrorsUntilSuccess(t *testing.T) {
+	h := &replay{statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}}
+	c := NewClient("test", fast)
+
+	resp, err := c.Get(context.Background(), serve(t, h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+	if s := c.Stats(); s.Requests != 3 || s.Retries != 2 || s.ServerErrors != 2 || s.Failures != 0 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestGivesUpAfterLastAttempt(t *testing.T) {
+	h := &replay{statuses: []int{http.StatusInternalServerError}}
+	c := NewClient("test", fast)
+
+	_, err := c.Get(context.Background(), serve(t, h))
+	if err == nil || !strings.Contains(err.Error(), "giving up after 3 attempts") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(h.bodies) != 3 {
+		t.Errorf("server saw %d attempts, want 3", len(h.bodies))
+	}
+	if s := c.Stats(); s.Failures != 1 || s.Retries != 2 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestHonoursRetryAfter(t *testing.T) {
+	h := &replay{
+		statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+		header:   http.Header{"Retry-After": {"1"}},
+	}
+	c := NewClient("test", fast)
+
+	start := time.Now()
+	resp, err := c.Get(context.Background(), serve(t, h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	// The server's delay is not shortened to the backoff, even past MaxBackoff
+	if waited := time.Since(start); waited < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", waited)
+	}
+	if s := c.Stats(); s.RateLimited != 1 || s.Requests != 2 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestFailsFastWhenRetryAfterOutlastsDeadline(t *testing.T) {
+	h := &replay{
+		statuses: []int{http.StatusTooManyRequests},
+		header:   http.Header{"Retry-After": {"120"}},
+	}
+	c := NewClient("test", fast)
+
+	// A delay the context's deadline cannot cover fails without waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := c.Get(ctx, serve(t, h))
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "retry after 2m0s") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(h.bodies) != 1 || time.Since(start) > time.Second {
+		t.Errorf("%d attempts in %s, want one and no wait", len(h.bodies), time.Since(start))
+	}
+	if s := c.Stats(); s.Failures != 1 {
+		t.Errorf("Stats = %+v, want one failure", s)
+	}
+}
+
+func TestReplaysBodyOnRetry(t *testing.T) {
+	h := &replay{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	c := NewClient("test", fast)
+
+	payload := `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+	req, err := http.NewRequest(http.MethodPost, serve(t, h), bytes.NewReader([]byte(payload)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(h.bodies) != 2 || h.bodies[0] != payload || h.bodies[1] != payload {
+		t.Errorf("bodies = %q, want the payload twice", h.bodies)
+	}
+}
+
+func TestCancelAbandonsRetries(t *testing.T) {
+	h := &replay{statuses: []int{http.StatusServiceUnavailable}}
+	limits := fast
+	limits.BaseBackoff, limits.MaxBackoff = time.Second, time.Second
+	c := NewClient("test", limits)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.Get(ctx, serve(t, h)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the context's", err)
+	}
+	if waited := time.Since(start); waited > 500*time.Millisecond || len(h.bodies) != 1 {
+		t.Errorf("%d attempts in %s, want one and no backoff", len(h.bodies), waited)
+	}
+}
+
+func TestThrottledCancelCountsAsFailure(t *testing.T) {
+	h := &replay{statuses: []int{http.StatusOK}}
+	limits := fast
+	limits.RequestsPerSecond, limits.Burst = 0.01, 1
+	c := NewClient("test", limits)
+	url := serve(t, h)
+
+	resp, err := c.Get(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// The bucket is empty for the next 100s, so the deadline passes first
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, url); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the context's", err)
+	}
+	if s := c.Stats(); s.Requests != 1 || s.Failures != 1 {
+		t.Errorf("Stats = %+v, want one request and one failure", s)
+	}
+}
+
+func TestBackoffStaysWithinLimit(t *testing.T) {
+	for _, limits := range []Limits{
+		{},
+		{BaseBackoff: time.Hour},
+		{BaseBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond},
+	} {
+		c := NewClient("test", limits)
+		for _, attempt := range []int{0, 1, 10, 63, 64, 1000} {
+			if d := c.backoff(attempt); d <= 0 || d > c.maxBackoff() {
+				t.Errorf("%+v attempt %d: backoff %s outside (0, %s]", limits, attempt, d, c.maxBackoff())
+			}
+		}
+	}
+}
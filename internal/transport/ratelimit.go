@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a simple token-bucket rate limiter.
+// Tokens refill continuously at rate per second up to burst.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a bucket that starts full. A rate <= 0 disables limiting.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done.
+// It returns the time spent waiting.
+func (b *TokenBucket) Wait(ctx context.Context) (time.Duration, error) {
+	if b == nil || b.rate <= 0 {
+		return 0, nil
+	}
+
+	var waited time.Duration
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return waited, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			waited += delay
+		case <-ctx.Done():
+			timer.Stop()
+			return waited, ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise returns how long
+// until the next token is due
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	missing := 1 - b.tokens
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
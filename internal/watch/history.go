@@ -0,0 +1,76 @@
+package watch
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transition is a watched token moving from one state to another. The first
+// screening of a token with no recorded history is a transition from "".
+type Transition struct {
+	Chain     string    `json:"chain"`
+	Address   string    `json:"address"` // Lowercase token address
+	Symbol    string    `json:"symbol"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	FromScore float64   `json:"from_score"`
+	ToScore   float64   `json:"to_score"`
+	Reasons   []string  `json:"reasons"` // Why the token is in its new state
+	At        time.Time `json:"at"`
+}
+
+// History persists transitions. storage.Store implements it on Postgres.
+type History interface {
+	RecordTransition(ctx context.Context, t Transition) error
+	// LatestTransitions returns each token's most recent transition on chain,
+	// keyed by lowercase address
+	LatestTransitions(ctx context.Context, chain string) (map[string]Transition, error)
+	// Transitions returns a token's transitions, oldest first
+	Transitions(ctx context.Context, chain, address string) ([]Transition, error)
+}
+
+// MemoryHistory keeps transitions in process, for runs without a database
+// and for tests
+type MemoryHistory struct {
+	mu          sync.RWMutex
+	transitions map[string][]Transition // Keyed by "chain/address"
+}
+
+func NewMemoryHistory() *MemoryHistory {
+	return &MemoryHistory{transitions: make(map[string][]Transition)}
+}
+
+func (m *MemoryHistory) RecordTransition(ctx context.Context, t Transition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := t.Chain + "/" + strings.ToLower(t.Address)
+	m.transitions[key] = append(m.transitions[key], t)
+	return nil
+}
+
+func (m *MemoryHistory) LatestTransitions(ctx context.Context, chain string) (map[string]Transition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	latest := make(map[string]Transition)
+	for key, transitions := range m.transitions {
+		address, ok := strings.CutPrefix(key, chain+"/")
+		if !ok || len(transitions) == 0 {
+			continue
+		}
+		latest[address] = transitions[len(transitions)-1]
+	}
+	return latest, nil
+}
+
+func (m *MemoryHistory) Transitions(ctx context.Context, chain, address string) ([]Transition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	transitions := append([]Transition(nil), m.transitions[chain+"/"+strings.ToLower(address)]...)
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].At.Before(transitions[j].At) })
+	return transitions, nil
+}
@@ -0,0 +1,302 @@
+// Package watch keeps a watchlist of tokens under continuous screening.
+// Taxes, owners and liquidity can change long after a token passed, so each
+// token is screened again after an interval set by the state its last
+// screening left it in, and every change of state is recorded.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+)
+
+// Watch states: the listing tier of a passed token, otherwise its pipeline
+// status
+const (
+	StateFeatured = "FEATURED"
+	StateVisible  = "VISIBLE"
+	StateHidden   = "HIDDEN"
+	StateFailed   = pipeline.StatusFailed
+)
+
+// State is the watch state a result puts its token in. Errored results have
+// no state of their own: a provider outage is not a change in the token.
+func State(r pipeline.TokenResult, t pipeline.ListingThresholds) string {
+	switch r.Status {
+	case pipeline.StatusPassed:
+		return strings.ToUpper(r.ListingStatus(t))
+	case pipeline.StatusFailed:
+		return StateFailed
+	default:
+		return ""
+	}
+}
+
+// Screener runs the pipeline over a batch of tokens. *pipeline.Pipeline
+// implements it.
+type Screener interface {
+	Run(ctx context.Context, tokens []models.BasicTokenInfo, onResult func(i int, r pipeline.TokenResult)) []pipeline.TokenResult
+}
+
+// Entry is a watched token and its schedule
+type Entry struct {
+	Token     models.BasicTokenInfo
+	State     string    // "" until first screened
+	Score     float64   // Composite score of the last passed screening
+	LastError string    // Error of the last screening, if it errored
+	CheckedAt time.Time // Zero until first screened
+	NextCheck time.Time
+}
+
+// Watcher re-screens a watchlist on a per-state schedule
+type Watcher struct {
+	screener  Screener
+	chain     string
+	intervals config.WatchIntervals
+	listing   pipeline.ListingThresholds
+	history   History
+	now       func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*Entry     // Keyed by lowercase address
+	order    []string              // Watchlist order, so cycles screen in file order
+	restored map[string]Transition // Last recorded transitions, keyed by lowercase address
+	wake     chan struct{}
+}
+
+// New creates a watcher screening tokens on chain with screener, keeping
+// transition history in memory until WithHistory replaces it
+func New(screener Screener, chain string, intervals config.WatchIntervals, listing pipeline.ListingThresholds) *Watcher {
+	return &Watcher{
+		screener:  screener,
+		chain:     chain,
+		intervals: intervals,
+		listing:   listing,
+		history:   NewMemoryHistory(),
+		now:       time.Now,
+		entries:   make(map[string]*Entry),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// WithHistory records transitions in h (e.g. Postgres) instead of in memory
+func (w *Watcher) WithHistory(h History) *Watcher {
+	w.history = h
+	return w
+}
+
+// WithClock replaces time.Now, for tests
+func (w *Watcher) WithClock(now func() time.Time) *Watcher {
+	w.now = now
+	return w
+}
+
+// Restore loads each token's last recorded state from the history, so that
+// after a restart the first screening is compared with it rather than
+// treated as new. Restored tokens are still due immediately.
+func (w *Watcher) Restore(ctx context.Context) error {
+	latest, err := w.history.LatestTransitions(ctx, w.chain)
+	if err != nil {
+		return fmt.Errorf("loading watch history: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.restored = latest
+	for address, e := range w.entries {
+		if t, ok := latest[address]; ok && e.State == "" {
+			e.State, e.Score = t.To, t.ToScore
+		}
+	}
+	return nil
+}
+
+// Set replaces the watchlist. Tokens already watched keep their state and
+// schedule; new ones are due immediately.
+func (w *Watcher) Set(tokens []models.BasicTokenInfo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries := make(map[string]*Entry, len(tokens))
+	order := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		address := strings.ToLower(token.Address)
+		if _, dup := entries[address]; dup {
+			continue
+		}
+		e, ok := w.entries[address]
+		if !ok {
+			e = &Entry{NextCheck: w.now()}
+			if t, ok := w.restored[address]; ok {
+				e.State, e.Score = t.To, t.ToScore
+			}
+		}
+		e.Token = token
+		entries[address] = e
+		order = append(order, address)
+	}
+	w.entries, w.order = entries, order
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Entries returns the watchlist in order
+func (w *Watcher) Entries() []Entry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries := make([]Entry, 0, len(w.order))
+	for _, address := range w.order {
+		entries = append(entries, *w.entries[address])
+	}
+	return entries
+}
+
+// Interval is how long a token in state waits for its next screening. An
+// empty state is a token whose screening errored.
+func (w *Watcher) Interval(state string) time.Duration {
+	switch state {
+	case StateFeatured:
+		return w.intervals.Featured
+	case StateVisible:
+		return w.intervals.Visible
+	case StateHidden, StateFailed:
+		return w.intervals.Hidden
+	default:
+		return w.intervals.Error
+	}
+}
+
+// Cycle screens every token that is due and records the ones whose state
+// changed. onResult, if non-nil, receives each result with its transition,
+// or nil when the state is unchanged. It returns the number of tokens
+// screened; the error is the first transition that could not be recorded.
+func (w *Watcher) Cycle(ctx context.Context, onResult func(r pipeline.TokenResult, change *Transition)) (int, error) {
+	due := w.due(w.now())
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	var firstErr error
+	w.screener.Run(ctx, due, func(i int, r pipeline.TokenResult) {
+		// Tokens left unscreened by a shutdown stay due
+		if r.Status == pipeline.StatusError && ctx.Err() != nil {
+			return
+		}
+		change := w.update(r)
+		if change != nil {
+			if err := w.history.RecordTransition(ctx, *change); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("recording transition of %s: %w", change.Address, err)
+			}
+		}
+		if onResult != nil {
+			onResult(r, change)
+		}
+	})
+	return len(due), firstErr
+}
+
+// Run screens due tokens until ctx is cancelled, sleeping until the next
+// token is due or the watchlist changes. Errors from Cycle go to onError, if
+// non-nil, without stopping the loop.
+func (w *Watcher) Run(ctx context.Context, onResult func(r pipeline.TokenResult, change *Transition), onError func(error)) error {
+	for {
+		if _, err := w.Cycle(ctx, onResult); err != nil && onError != nil {
+			onError(err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(w.untilNext())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-w.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// due returns the tokens whose next check is at or before now
+func (w *Watcher) due(now time.Time) []models.BasicTokenInfo {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var tokens []models.BasicTokenInfo
+	for _, address := range w.order {
+		if e := w.entries[address]; !e.NextCheck.After(now) {
+			tokens = append(tokens, e.Token)
+		}
+	}
+	return tokens
+}
+
+// untilNext is the wait for the earliest next check; an empty watchlist
+// waits for Set
+func (w *Watcher) untilNext() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var next time.Time
+	for _, e := range w.entries {
+		if next.IsZero() || e.NextCheck.Before(next) {
+			next = e.NextCheck
+		}
+	}
+	if next.IsZero() {
+		return w.intervals.Hidden
+	}
+	return max(next.Sub(w.now()), 0)
+}
+
+// update reschedules the result's token and returns its transition, if any
+func (w *Watcher) update(r pipeline.TokenResult) *Transition {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	address := strings.ToLower(r.Address)
+	e, ok := w.entries[address]
+	if !ok {
+		return nil // Dropped from the watchlist while being screened
+	}
+
+	now := w.now()
+	e.CheckedAt = now
+	state := State(r, w.listing)
+	if state == "" {
+		e.LastError = r.ErrorReason
+		e.NextCheck = now.Add(w.Interval(""))
+		return nil
+	}
+	e.LastError = ""
+	e.NextCheck = now.Add(w.Interval(state))
+
+	from, fromScore := e.State, e.Score
+	e.State, e.Score = state, r.Score
+	if state == from {
+		return nil
+	}
+	return &Transition{
+		Chain:     r.Chain,
+		Address:   address,
+		Symbol:    r.Symbol,
+		From:      from,
+		To:        state,
+		FromScore: fromScore,
+		ToScore:   r.Score,
+		Reasons:   r.Listing(w.listing).Reasons,
+		At:        now,
+	}
+}
@@ -0,0 +1,220 @@
+package watch_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/config"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/models"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/pipeline"
+	"github.com/notlelouch/go-interview-practice/DEX-Token-Screener/internal/watch"
+)
+
+const (
+	cake = "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"
+	avl  = "0x9beee89723ceec27d7c2834bec6834208ffdc202"
+)
+
+var (
+	intervals = config.WatchIntervals{Featured: 15 * time.Minute, Visible: time.Hour, Hidden: 6 * time.Hour, Error: 10 * time.Minute}
+	listing   = pipeline.ListingThresholds{Featured: 70, Visible: 50}
+	tokens    = []models.BasicTokenInfo{{Address: cake, Symbol: "CAKE"}, {Address: avl, Symbol: "AVL"}}
+)
+
+// scripted returns each token's next queued result, repeating the last one
+type scripted struct {
+	mu       sync.Mutex
+	results  map[string][]pipeline.TokenResult
+	screened map[string]int
+}
+
+func newScripted() *scripted {
+	return &scripted{results: map[string][]pipeline.TokenResult{}, screened: map[string]int{}}
+}
+
+func (s *scripted) queue(address string, results ...pipeline.TokenResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range results {
+		r.Chain, r.Address = "bsc", address
+		s.results[address] = append(s.results[address], r)
+	}
+}
+
+func (s *scripted) count(address string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.screened[address]
+}
+
+func (s *scripted) Run(ctx context.Context, tokens []models.BasicTokenInfo, onResult func(i int, r pipeline.TokenResult)) []pipeline.TokenResult {
+	results := make([]pipeline.TokenResult, len(tokens))
+	for i, token := range tokens {
+		s.mu.Lock()
+		queued := s.results[token.Address]
+		r := queued[0]
+		if len(queued) > 1 {
+			s.results[token.Address] = queued[1:]
+		}
+		s.screened[token.Address]++
+		s.mu.Unlock()
+
+		r.Symbol = token.Symbol
+		results[i] = r
+		if onResult != nil {
+			onResult(i, r)
+		}
+	}
+	return results
+}
+
+func passed(score float64) pipeline.TokenResult {
+	return pipeline.TokenResult{Status: pipeline.StatusPassed, Score: score}
+}
+
+func failed(stage, reason string) pipeline.TokenResult {
+	return pipeline.TokenResult{Status: pipeline.StatusFailed, Stage: stage, FailureReasons: []string{reason}}
+}
+
+func TestCycleSchedulesByStateAndRecordsTransitions(t *testing.T) {
+	screener := newScripted()
+	screener.queue(cake, passed(85), passed(84), failed(pipeline.StageFraud, "Honeypot detected"))
+	screener.queue(avl, passed(40))
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := watch.NewMemoryHistory()
+	w := watch.New(screener, "bsc", intervals, listing).WithHistory(history).WithClock(func() time.Time { return now })
+	w.Set(tokens)
+
+	cycle := func() (int, []watch.Transition) {
+		var changes []watch.Transition
+		n, err := w.Cycle(context.Background(), func(r pipeline.TokenResult, change *watch.Transition) {
+			if change != nil {
+				changes = append(changes, *change)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n, changes
+	}
+
+	// First screening: both tokens are new
+	n, changes := cycle()
+	if n != 2 || len(changes) != 2 || changes[0].From != "" || changes[0].To != watch.StateFeatured || changes[1].To != watch.StateHidden {
+		t.Fatalf("first cycle screened %d, changes %+v", n, changes)
+	}
+	entries := w.Entries()
+	if !entries[0].NextCheck.Equal(now.Add(15*time.Minute)) || !entries[1].NextCheck.Equal(now.Add(6*time.Hour)) {
+		t.Errorf("next checks %s, %s", entries[0].NextCheck, entries[1].NextCheck)
+	}
+
+	// Nothing is due yet
+	if n, _ := cycle(); n != 0 {
+		t.Errorf("screened %d tokens before any was due", n)
+	}
+
+	// Featured tokens come round first; an unchanged state is not a transition
+	now = now.Add(15 * time.Minute)
+	if n, changes := cycle(); n != 1 || len(changes) != 0 {
+		t.Errorf("second cycle screened %d, changes %+v", n, changes)
+	}
+
+	now = now.Add(15 * time.Minute)
+	n, changes = cycle()
+	if n != 1 || len(changes) != 1 {
+		t.Fatalf("third cycle screened %d, changes %+v", n, changes)
+	}
+	if c := changes[0]; c.From != watch.StateFeatured || c.To != watch.StateFailed || c.FromScore != 84 ||
+		c.Symbol != "CAKE" || len(c.Reasons) != 2 || c.Reasons[1] != "Honeypot detected" {
+		t.Errorf("transition = %+v", c)
+	}
+	if e := w.Entries()[0]; !e.NextCheck.Equal(now.Add(6 * time.Hour)) {
+		t.Errorf("failed token next check %s, want the hidden interval", e.NextCheck)
+	}
+
+	recorded, err := history.Transitions(context.Background(), "bsc", cake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 2 || recorded[0].To != watch.StateFeatured || recorded[1].To != watch.StateFailed {
+		t.Errorf("recorded history %+v", recorded)
+	}
+}
+
+func TestCycleErrorsKeepState(t *testing.T) {
+	screener := newScripted()
+	screener.queue(cake, passed(85), pipeline.TokenResult{Status: pipeline.StatusError, ErrorReason: "dexscreener: 503"}, passed(86))
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := watch.New(screener, "bsc", intervals, listing).WithClock(func() time.Time { return now })
+	w.Set(tokens[:1])
+
+	var changes int
+	onResult := func(r pipeline.TokenResult, change *watch.Transition) {
+		if change != nil {
+			changes++
+		}
+	}
+	for range 3 {
+		if _, err := w.Cycle(context.Background(), onResult); err != nil {
+			t.Fatal(err)
+		}
+		e := w.Entries()[0]
+		if e.State != watch.StateFeatured {
+			t.Fatalf("state %q after %d screenings, want %s", e.State, screener.count(cake), watch.StateFeatured)
+		}
+		if e.LastError != "" && !e.NextCheck.Equal(now.Add(10*time.Minute)) {
+			t.Errorf("errored token next check %s, want the error interval", e.NextCheck)
+		}
+		now = e.NextCheck
+	}
+	if changes != 1 {
+		t.Errorf("got %d transitions, want only the first screening", changes)
+	}
+}
+
+func TestRestoreComparesWithRecordedState(t *testing.T) {
+	history := watch.NewMemoryHistory()
+	history.RecordTransition(context.Background(), watch.Transition{Chain: "bsc", Address: cake, To: watch.StateFeatured, ToScore: 90})
+
+	screener := newScripted()
+	screener.queue(cake, passed(60))
+	w := watch.New(screener, "bsc", intervals, listing).WithHistory(history)
+	if err := w.Restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	w.Set(tokens[:1])
+
+	var change *watch.Transition
+	w.Cycle(context.Background(), func(r pipeline.TokenResult, c *watch.Transition) { change = c })
+	if change == nil || change.From != watch.StateFeatured || change.To != watch.StateVisible || change.FromScore != 90 {
+		t.Errorf("transition after restart = %+v", change)
+	}
+}
+
+func TestRunRescreensFeaturedMoreOften(t *testing.T) {
+	screener := newScripted()
+	screener.queue(cake, passed(85))
+	screener.queue(avl, passed(40))
+
+	fast := config.WatchIntervals{Featured: 5 * time.Millisecond, Visible: time.Hour, Hidden: time.Hour, Error: time.Hour}
+	w := watch.New(screener, "bsc", fast, listing)
+	w.Set(tokens)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := w.Run(ctx, func(r pipeline.TokenResult, change *watch.Transition) {
+		if screener.count(cake) >= 3 {
+			cancel()
+		}
+	}, nil)
+	if err != context.Canceled {
+		t.Fatalf("Run returned %v, want context.Canceled", err)
+	}
+	if screener.count(avl) != 1 {
+		t.Errorf("hidden token screened %d times, want 1", screener.count(avl))
+	}
+}